@@ -32,4 +32,4 @@ func GetUserAndOrgID(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
 	orgID, _ := uuid.Parse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
 
 	return userID, orgID, nil
-}
\ No newline at end of file
+}
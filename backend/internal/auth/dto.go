@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type RegisterRequest struct {
@@ -20,13 +21,39 @@ type UserResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email          string    `json:"email" validate:"required,email"`
+	Password       string    `json:"password" validate:"required"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+
+	refreshTokenID pgtype.UUID
 }
 
 type LoginResponse struct {
-	AccessToken string       `json:"access_token"`
-	User        UserResponse `json:"user"`
+	TokenPair
+	User UserResponse `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type OrganizationResponse struct {
+	ID      uuid.UUID `json:"id"`
+	Name    string    `json:"name"`
+	Slug    string    `json:"slug"`
+	Role    string    `json:"role"`
+	Current bool      `json:"current"`
+}
+
+type SwitchOrganizationResponse struct {
+	TokenPair
+	Organization OrganizationResponse `json:"organization"`
 }
 
 type UpdateProfileRequest struct {
@@ -36,4 +63,4 @@ type UpdateProfileRequest struct {
 type UpdatePasswordRequest struct {
 	CurrentPassword string `json:"current_password" validate:"required"`
 	NewPassword     string `json:"new_password" validate:"required,min=6"`
-}
\ No newline at end of file
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/token"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -18,6 +19,18 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrInvalidRefreshToken   = errors.New("invalid refresh token")
+	ErrRefreshTokenReused    = errors.New("refresh token reuse detected")
+	ErrNotOrganizationMember = errors.New("user is not a member of this organization")
+)
+
 type Service struct {
 	q  *db.Queries
 	db *pgxpool.Pool
@@ -90,14 +103,18 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (LoginResponse, e
 	user, err := s.q.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return LoginResponse{}, errors.New("invalid credentials")
+			return LoginResponse{}, ErrInvalidCredentials
 		}
 		log.Error().Err(err).Msg("database error during login")
 		return LoginResponse{}, errors.New("internal error")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return LoginResponse{}, errors.New("invalid credentials")
+		return LoginResponse{}, ErrInvalidCredentials
+	}
+
+	if !user.IsActive {
+		return LoginResponse{}, ErrInvalidCredentials
 	}
 
 	orgs, err := s.q.GetUserOrganizations(ctx, user.ID)
@@ -107,22 +124,26 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (LoginResponse, e
 	}
 
 	org := orgs[0]
+	if req.OrganizationID != uuid.Nil {
+		found := false
+		for _, o := range orgs {
+			if uuid.UUID(o.ID.Bytes) == req.OrganizationID {
+				org, found = o, true
+				break
+			}
+		}
+		if !found {
+			return LoginResponse{}, ErrNotOrganizationMember
+		}
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":    uuid.UUID(user.ID.Bytes).String(),
-		"org_id": uuid.UUID(org.ID.Bytes).String(),
-		"role":   string(org.Role),
-		"exp":    time.Now().Add(time.Hour * 24).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	tokens, err := s.issueTokens(ctx, s.q, user.ID, org.ID, org.Role, pgtype.UUID{})
 	if err != nil {
-		log.Error().Err(err).Msg("failed to sign token")
-		return LoginResponse{}, errors.New("could not generate token")
+		return LoginResponse{}, err
 	}
 
 	return LoginResponse{
-		AccessToken: tokenString,
+		TokenPair: tokens,
 		User: UserResponse{
 			ID:        uuid.UUID(user.ID.Bytes),
 			Email:     user.Email,
@@ -132,6 +153,176 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (LoginResponse, e
 	}, nil
 }
 
+// Refresh troca um refresh token válido por um novo par de tokens. O token
+// apresentado é revogado e encadeado ao novo (rotação); se um token já
+// rotacionado for apresentado de novo, toda a família é revogada.
+func (s *Service) Refresh(ctx context.Context, req RefreshRequest) (TokenPair, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	current, err := qtx.GetRefreshTokenByHash(ctx, token.Hash(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+		return TokenPair{}, err
+	}
+
+	if current.IsRevoked {
+		if !current.ReplacedBy.Valid {
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+
+		log.Warn().
+			Str("user_id", uuid.UUID(current.UserID.Bytes).String()).
+			Str("family_id", uuid.UUID(current.FamilyID.Bytes).String()).
+			Msg("refresh token reuse detected, revoking family")
+
+		if err := qtx.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+			return TokenPair{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, ErrRefreshTokenReused
+	}
+
+	if !current.ExpiresAt.Valid || time.Now().After(current.ExpiresAt.Time) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	user, err := qtx.GetUserByID(ctx, current.UserID)
+	if err != nil || !user.IsActive {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	member, err := qtx.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{
+		OrganizationID: current.OrganizationID,
+		UserID:         current.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if err := qtx.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+				return TokenPair{}, err
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return TokenPair{}, err
+			}
+			return TokenPair{}, ErrInvalidRefreshToken
+		}
+		return TokenPair{}, err
+	}
+
+	tokens, err := s.issueTokens(ctx, qtx, current.UserID, member.OrganizationID, member.Role, current.FamilyID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := qtx.RotateRefreshToken(ctx, db.RotateRefreshTokenParams{
+		ID:         current.ID,
+		ReplacedBy: tokens.refreshTokenID,
+	}); err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TokenPair{}, err
+	}
+
+	return tokens, nil
+}
+
+// Logout revoga a família inteira do refresh token informado, encerrando a
+// sessão em todos os tokens derivados dele.
+func (s *Service) Logout(ctx context.Context, req RefreshRequest) error {
+	current, err := s.q.GetRefreshTokenByHash(ctx, token.Hash(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	return s.q.RevokeRefreshTokenFamily(ctx, current.FamilyID)
+}
+
+func (s *Service) ListOrganizations(ctx context.Context, userID, currentOrgID uuid.UUID) ([]OrganizationResponse, error) {
+	orgs, err := s.q.GetUserOrganizations(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]OrganizationResponse, 0, len(orgs))
+	for _, o := range orgs {
+		res = append(res, toOrganizationResponse(o, currentOrgID))
+	}
+
+	return res, nil
+}
+
+// SwitchOrganization reemite os tokens da sessão para outra organização do
+// usuário, com o papel que ele tem nela. O vínculo é conferido no banco, não
+// no token atual. O refresh token apresentado precisa ser do usuário e ainda
+// válido; a família dele é revogada, como no logout, para que a sessão antiga
+// não continue renovando acesso à organização anterior.
+func (s *Service) SwitchOrganization(ctx context.Context, userID, orgID uuid.UUID, req RefreshRequest) (SwitchOrganizationResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return SwitchOrganizationResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	current, err := qtx.GetRefreshTokenByHash(ctx, token.Hash(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SwitchOrganizationResponse{}, ErrInvalidRefreshToken
+		}
+		return SwitchOrganizationResponse{}, err
+	}
+	if uuid.UUID(current.UserID.Bytes) != userID || current.IsRevoked ||
+		!current.ExpiresAt.Valid || time.Now().After(current.ExpiresAt.Time) {
+		return SwitchOrganizationResponse{}, ErrInvalidRefreshToken
+	}
+
+	orgs, err := qtx.GetUserOrganizations(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return SwitchOrganizationResponse{}, err
+	}
+
+	for _, o := range orgs {
+		if uuid.UUID(o.ID.Bytes) != orgID {
+			continue
+		}
+
+		if err := qtx.RevokeRefreshTokenFamily(ctx, current.FamilyID); err != nil {
+			return SwitchOrganizationResponse{}, err
+		}
+
+		tokens, err := s.issueTokens(ctx, qtx, pgtype.UUID{Bytes: userID, Valid: true}, o.ID, o.Role, pgtype.UUID{})
+		if err != nil {
+			return SwitchOrganizationResponse{}, err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return SwitchOrganizationResponse{}, err
+		}
+
+		return SwitchOrganizationResponse{
+			TokenPair:    tokens,
+			Organization: toOrganizationResponse(o, orgID),
+		}, nil
+	}
+
+	return SwitchOrganizationResponse{}, ErrNotOrganizationMember
+}
+
 func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, req UpdateProfileRequest) (db.UpdateUserNameRow, error) {
 	return s.q.UpdateUserName(ctx, db.UpdateUserNameParams{
 		ID:       pgtype.UUID{Bytes: userID, Valid: true},
@@ -160,8 +351,67 @@ func (s *Service) UpdatePassword(ctx context.Context, userID uuid.UUID, req Upda
 	})
 }
 
+// issueTokens assina um access token curto e grava um novo refresh token
+// (apenas o hash) na família informada. Uma família vazia inicia uma sessão.
+func (s *Service) issueTokens(ctx context.Context, q *db.Queries, userID, orgID pgtype.UUID, role db.UserRole, familyID pgtype.UUID) (TokenPair, error) {
+	expiresAt := time.Now().Add(accessTokenTTL)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":    uuid.UUID(userID.Bytes).String(),
+		"org_id": uuid.UUID(orgID.Bytes).String(),
+		"role":   string(role),
+		"exp":    expiresAt.Unix(),
+	})
+
+	accessToken, err := jwtToken.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to sign token")
+		return TokenPair{}, errors.New("could not generate token")
+	}
+
+	refreshToken, err := token.Generate()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate refresh token")
+		return TokenPair{}, errors.New("could not generate token")
+	}
+
+	if !familyID.Valid {
+		familyID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	}
+
+	refreshTokenID, err := q.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:         userID,
+		OrganizationID: orgID,
+		FamilyID:       familyID,
+		TokenHash:      token.Hash(refreshToken),
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(refreshTokenTTL), Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to store refresh token")
+		return TokenPair{}, errors.New("could not generate token")
+	}
+
+	return TokenPair{
+		AccessToken:    accessToken,
+		RefreshToken:   refreshToken,
+		ExpiresIn:      int64(accessTokenTTL.Seconds()),
+		refreshTokenID: refreshTokenID,
+	}, nil
+}
+
+func toOrganizationResponse(o db.GetUserOrganizationsRow, currentOrgID uuid.UUID) OrganizationResponse {
+	id := uuid.UUID(o.ID.Bytes)
+	return OrganizationResponse{
+		ID:      id,
+		Name:    o.Name,
+		Slug:    o.Slug,
+		Role:    string(o.Role),
+		Current: id == currentOrgID,
+	}
+}
+
 func slugify(s string) string {
 	s = strings.ToLower(s)
 	s = strings.ReplaceAll(s, " ", "-")
 	return s
-}
\ No newline at end of file
+}
@@ -0,0 +1,47 @@
+// Package barcode valida códigos de barras GTIN (EAN-8, UPC-A, EAN-13 e
+// GTIN-14).
+package barcode
+
+import "strings"
+
+// Normalize remove espaços e hífens que alguns leitores e planilhas incluem.
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+}
+
+// ValidGTIN confere tamanho (8, 12, 13 ou 14 dígitos) e dígito verificador.
+func ValidGTIN(s string) bool {
+	code := Normalize(s)
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return int(code[len(code)-1]-'0') == CheckDigit(code[:len(code)-1])
+}
+
+// CheckDigit calcula o dígito verificador GTIN (módulo 10) para os dígitos
+// informados, sem o verificador: pesos 3 e 1 alternados a partir da direita.
+func CheckDigit(digits string) int {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
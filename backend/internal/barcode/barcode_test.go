@@ -0,0 +1,33 @@
+package barcode
+
+import "testing"
+
+func TestValidGTIN(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"7891000315507", true},   // EAN-13
+		{"789-1000-315507", true}, // com hífens
+		{"7891000315508", false},
+		{"96385074", true}, // EAN-8
+		{"96385075", false},
+		{"036000291452", true},   // UPC-A
+		{"17891000315504", true}, // GTIN-14
+		{"789100031550", false},
+		{"78910003155O7", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidGTIN(tt.in); got != tt.want {
+			t.Errorf("ValidGTIN(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	if got := CheckDigit("789100031550"); got != 7 {
+		t.Errorf("CheckDigit = %d, want 7", got)
+	}
+}
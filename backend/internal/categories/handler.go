@@ -0,0 +1,105 @@
+package categories
+
+import (
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	category, err := h.service.Create(c.Context(), claims.OrgID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(category)
+}
+
+func (h *Handler) Tree(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	tree, err := h.service.Tree(c.Context(), claims.OrgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(tree)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	categoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	category, err := h.service.Update(c.Context(), claims.OrgID, categoryID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(category)
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	categoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.Delete(c.Context(), claims.OrgID, categoryID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrCategoryNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrParentNotFound), errors.Is(err, ErrCategoryCycle):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, ErrCategoryNameTaken), errors.Is(err, ErrCategoryHasSubcategory):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
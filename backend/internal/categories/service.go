@@ -0,0 +1,191 @@
+package categories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ParentID nulo cria ou move a categoria para a raiz.
+type CategoryRequest struct {
+	Name     string     `json:"name" validate:"required,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// Category é um nó da árvore de categorias. ProductCount conta só os produtos
+// ligados diretamente ao nó.
+type Category struct {
+	ID           pgtype.UUID `json:"id"`
+	ParentID     pgtype.UUID `json:"parent_id"`
+	Name         string      `json:"name"`
+	ProductCount int32       `json:"product_count"`
+	Children     []*Category `json:"children"`
+}
+
+var (
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrParentNotFound         = errors.New("parent category not found")
+	ErrCategoryCycle          = errors.New("category cannot be moved under itself or its subcategories")
+	ErrCategoryNameTaken      = errors.New("a sibling category already has this name")
+	ErrCategoryHasSubcategory = errors.New("category has subcategories")
+)
+
+type Service struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CategoryRequest) (db.Category, error) {
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	parentID, err := s.parent(ctx, s.q, pgOrgID, req.ParentID)
+	if err != nil {
+		return db.Category{}, err
+	}
+
+	category, err := s.q.CreateCategory(ctx, db.CreateCategoryParams{
+		OrganizationID: pgOrgID,
+		ParentID:       parentID,
+		Name:           req.Name,
+	})
+	if db.IsUniqueViolation(err, "idx_categories_sibling_name") {
+		return db.Category{}, ErrCategoryNameTaken
+	}
+	return category, err
+}
+
+// Tree devolve todas as categorias da organização como árvore, em ordem
+// alfabética em cada nível.
+func (s *Service) Tree(ctx context.Context, orgID uuid.UUID) ([]*Category, error) {
+	rows, err := s.q.ListCategories(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uuid.UUID]*Category, len(rows))
+	for _, r := range rows {
+		nodes[uuid.UUID(r.ID.Bytes)] = &Category{
+			ID:           r.ID,
+			ParentID:     r.ParentID,
+			Name:         r.Name,
+			ProductCount: r.ProductCount,
+			Children:     []*Category{},
+		}
+	}
+
+	roots := []*Category{}
+	for _, r := range rows {
+		node := nodes[uuid.UUID(r.ID.Bytes)]
+		if parent, ok := nodes[uuid.UUID(r.ParentID.Bytes)]; r.ParentID.Valid && ok {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+// Update renomeia ou move a categoria. Mover para dentro da própria subárvore
+// é recusado para não criar ciclos; as categorias da organização ficam
+// travadas durante a checagem para que movimentações simultâneas não a
+// burlem.
+func (s *Service) Update(ctx context.Context, orgID, categoryID uuid.UUID, req CategoryRequest) (db.Category, error) {
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+	pgID := pgtype.UUID{Bytes: categoryID, Valid: true}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return db.Category{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	if err := qtx.LockCategories(ctx, pgOrgID); err != nil {
+		return db.Category{}, err
+	}
+
+	parentID, err := s.parent(ctx, qtx, pgOrgID, req.ParentID)
+	if err != nil {
+		return db.Category{}, err
+	}
+
+	if parentID.Valid {
+		cycle, err := qtx.IsCategoryDescendant(ctx, db.IsCategoryDescendantParams{
+			CategoryID: pgID,
+			Candidate:  parentID,
+		})
+		if err != nil {
+			return db.Category{}, err
+		}
+		if cycle {
+			return db.Category{}, ErrCategoryCycle
+		}
+	}
+
+	category, err := qtx.UpdateCategory(ctx, db.UpdateCategoryParams{
+		ID:             pgID,
+		OrganizationID: pgOrgID,
+		Name:           req.Name,
+		ParentID:       parentID,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return db.Category{}, ErrCategoryNotFound
+	case db.IsUniqueViolation(err, "idx_categories_sibling_name"):
+		return db.Category{}, ErrCategoryNameTaken
+	case err != nil:
+		return db.Category{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.Category{}, err
+	}
+	return category, nil
+}
+
+// Delete apaga a categoria; os produtos dela ficam sem categoria. Categorias
+// com subcategorias precisam ser esvaziadas antes.
+func (s *Service) Delete(ctx context.Context, orgID, categoryID uuid.UUID) error {
+	n, err := s.q.DeleteCategory(ctx, db.DeleteCategoryParams{
+		ID:             pgtype.UUID{Bytes: categoryID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return ErrCategoryHasSubcategory
+		}
+		return err
+	}
+	if n == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (s *Service) parent(ctx context.Context, q *db.Queries, orgID pgtype.UUID, parentID *uuid.UUID) (pgtype.UUID, error) {
+	if parentID == nil {
+		return pgtype.UUID{}, nil
+	}
+
+	parent, err := q.GetCategory(ctx, db.GetCategoryParams{
+		ID:             pgtype.UUID{Bytes: *parentID, Valid: true},
+		OrganizationID: orgID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, ErrParentNotFound
+	}
+	return parent.ID, err
+}
@@ -0,0 +1,130 @@
+// Package cep valida CEPs e consulta endereços por CEP.
+package cep
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalid  = errors.New("invalid CEP; use 8 digits")
+	ErrNotFound = errors.New("CEP not found")
+)
+
+// Address é o que uma consulta de CEP devolve. IBGECode é o código do
+// município, exigido na nota fiscal; pode vir vazio.
+type Address struct {
+	CEP      string `json:"zip_code"`
+	Street   string `json:"street"`
+	District string `json:"district"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	IBGECode string `json:"ibge_code"`
+}
+
+// Provider consulta o endereço de um CEP já normalizado. Quem chama depende
+// só da interface, então a base local pode dar lugar a um serviço externo
+// sem outras mudanças.
+type Provider interface {
+	Lookup(ctx context.Context, cep string) (Address, error)
+}
+
+// Normalize tira hífen, ponto e espaços e confere se sobraram 8 dígitos.
+func Normalize(s string) (string, error) {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == '.' || r == ' ':
+		default:
+			return "", ErrInvalid
+		}
+	}
+	if b.Len() != 8 {
+		return "", ErrInvalid
+	}
+	return b.String(), nil
+}
+
+// Format devolve o CEP no formato 00000-000.
+func Format(cep string) string {
+	if len(cep) != 8 {
+		return cep
+	}
+	return cep[:5] + "-" + cep[5:]
+}
+
+type state struct {
+	uf   string
+	ibge string
+	// Faixas de CEP da UF, pelos cinco primeiros dígitos.
+	ranges [][2]int
+}
+
+var states = []state{
+	{"SP", "35", [][2]int{{1000, 19999}}},
+	{"RJ", "33", [][2]int{{20000, 28999}}},
+	{"ES", "32", [][2]int{{29000, 29999}}},
+	{"MG", "31", [][2]int{{30000, 39999}}},
+	{"BA", "29", [][2]int{{40000, 48999}}},
+	{"SE", "28", [][2]int{{49000, 49999}}},
+	{"PE", "26", [][2]int{{50000, 56999}}},
+	{"AL", "27", [][2]int{{57000, 57999}}},
+	{"PB", "25", [][2]int{{58000, 58999}}},
+	{"RN", "24", [][2]int{{59000, 59999}}},
+	{"CE", "23", [][2]int{{60000, 63999}}},
+	{"PI", "22", [][2]int{{64000, 64999}}},
+	{"MA", "21", [][2]int{{65000, 65999}}},
+	{"PA", "15", [][2]int{{66000, 68899}}},
+	{"AP", "16", [][2]int{{68900, 68999}}},
+	{"AM", "13", [][2]int{{69000, 69299}, {69400, 69899}}},
+	{"RR", "14", [][2]int{{69300, 69399}}},
+	{"AC", "12", [][2]int{{69900, 69999}}},
+	{"DF", "53", [][2]int{{70000, 72799}, {73000, 73699}}},
+	{"GO", "52", [][2]int{{72800, 72999}, {73700, 76799}}},
+	{"RO", "11", [][2]int{{76800, 76999}}},
+	{"TO", "17", [][2]int{{77000, 77999}}},
+	{"MT", "51", [][2]int{{78000, 78899}}},
+	{"MS", "50", [][2]int{{79000, 79999}}},
+	{"PR", "41", [][2]int{{80000, 87999}}},
+	{"SC", "42", [][2]int{{88000, 89999}}},
+	{"RS", "43", [][2]int{{90000, 99999}}},
+}
+
+// State devolve a UF dona da faixa do CEP normalizado, ou "" se nenhuma for.
+func State(cep string) string {
+	if len(cep) != 8 {
+		return ""
+	}
+	prefix, err := strconv.Atoi(cep[:5])
+	if err != nil {
+		return ""
+	}
+	for _, s := range states {
+		for _, r := range s.ranges {
+			if prefix >= r[0] && prefix <= r[1] {
+				return s.uf
+			}
+		}
+	}
+	return ""
+}
+
+// ValidState diz se uf é a sigla de um estado ou do DF, em maiúsculas.
+func ValidState(uf string) bool {
+	return StateIBGE(uf) != ""
+}
+
+// StateIBGE devolve o código IBGE da UF, que é o prefixo dos códigos de
+// município dela.
+func StateIBGE(uf string) string {
+	for _, s := range states {
+		if s.uf == uf {
+			return s.ibge
+		}
+	}
+	return ""
+}
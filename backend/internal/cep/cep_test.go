@@ -0,0 +1,84 @@
+package cep
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"01001-000", "01001000", nil},
+		{"01.001-000", "01001000", nil},
+		{" 01001000 ", "01001000", nil},
+		{"0100100", "", ErrInvalid},
+		{"01001-00A", "", ErrInvalid},
+		{"", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Normalize(%q) = %q, %v; want %q, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	tests := map[string]string{
+		"01001000": "SP",
+		"20040020": "RJ",
+		"69301000": "RR",
+		"69900000": "AC",
+		"70040010": "DF",
+		"73700000": "GO",
+		"90010000": "RS",
+		"00999999": "",
+	}
+	for in, want := range tests {
+		if got := State(in); got != want {
+			t.Errorf("State(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	l := NewLocal()
+	err := l.Load(strings.NewReader("\ufeffCEP;Logradouro;Bairro;Cidade;UF;IBGE\n" +
+		"01001-000;Praça da Sé;Sé;São Paulo;sp;3550308\n" +
+		"20040020;Avenida Rio Branco;Centro;Rio de Janeiro;RJ;3304557\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", l.Len())
+	}
+
+	addr, err := l.Lookup(context.Background(), "01001000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.Street != "Praça da Sé" || addr.State != "SP" || addr.IBGECode != "3550308" {
+		t.Errorf("Lookup = %+v", addr)
+	}
+	if _, err := l.Lookup(context.Background(), "99999999"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing CEP: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"cep,cidade,uf\n01001000,São Paulo,SP\n",
+		"cep,logradouro,bairro,cidade,uf\n0100,Praça da Sé,Sé,São Paulo,SP\n",
+	}
+	for _, in := range tests {
+		if err := NewLocal().Load(strings.NewReader(in)); !errors.Is(err, ErrInvalidDataset) {
+			t.Errorf("Load(%q) err = %v, want ErrInvalidDataset", in, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/sheet"
+)
+
+var ErrInvalidDataset = errors.New("invalid CEP dataset")
+
+// localColumns são as colunas esperadas no cabeçalho da base, em qualquer
+// ordem; ibge é opcional.
+var localColumns = []string{"cep", "logradouro", "bairro", "cidade", "uf"}
+
+// Local consulta CEPs numa base carregada em memória, sem acesso à rede. A
+// base é um CSV (vírgula, ponto e vírgula ou tabulação) com as colunas de
+// localColumns, como as exportações das bases públicas de CEP.
+type Local struct {
+	addresses map[string]Address
+}
+
+func NewLocal() *Local {
+	return &Local{addresses: make(map[string]Address)}
+}
+
+// LoadFile carrega a base do arquivo em path; ver Load.
+func (l *Local) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return l.Load(f)
+}
+
+// Load acrescenta os CEPs lidos de r à base. Um CEP repetido fica com a
+// última linha.
+func (l *Local) Load(r io.Reader) error {
+	rd, err := sheet.NewCSVReader(r)
+	if err != nil {
+		return err
+	}
+
+	header, err := rd.Read()
+	if err == io.EOF {
+		return fmt.Errorf("%w: empty file", ErrInvalidDataset)
+	}
+	if err != nil {
+		return err
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range localColumns {
+		if _, ok := cols[name]; !ok {
+			return fmt.Errorf("%w: missing column %q", ErrInvalidDataset, name)
+		}
+	}
+	ibge, hasIBGE := cols["ibge"]
+
+	for line := 2; ; line++ {
+		row, err := rd.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		get := func(i int) string {
+			if i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		code, err := Normalize(get(cols["cep"]))
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidDataset, line, err)
+		}
+		addr := Address{
+			CEP:      code,
+			Street:   get(cols["logradouro"]),
+			District: get(cols["bairro"]),
+			City:     get(cols["cidade"]),
+			State:    strings.ToUpper(get(cols["uf"])),
+		}
+		if hasIBGE {
+			addr.IBGECode = get(ibge)
+		}
+		l.addresses[code] = addr
+	}
+}
+
+// Len é o número de CEPs na base.
+func (l *Local) Len() int {
+	return len(l.addresses)
+}
+
+func (l *Local) Lookup(_ context.Context, cep string) (Address, error) {
+	addr, ok := l.addresses[cep]
+	if !ok {
+		return Address{}, ErrNotFound
+	}
+	return addr, nil
+}
@@ -0,0 +1,239 @@
+package customers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/cep"
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AddressRequest struct {
+	Type       string `json:"type" validate:"oneof=billing shipping"`
+	IsDefault  bool   `json:"is_default"`
+	ZipCode    string `json:"zip_code" validate:"required"`
+	Street     string `json:"street" validate:"required,max=200"`
+	Number     string `json:"number" validate:"required,max=20"`
+	Complement string `json:"complement" validate:"max=100"`
+	District   string `json:"district" validate:"required,max=100"`
+	City       string `json:"city" validate:"required,max=100"`
+	State      string `json:"state" validate:"required,len=2"`
+	IBGECode   string `json:"ibge_code" validate:"omitempty,len=7,numeric"`
+}
+
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrInvalidState    = errors.New("invalid state; use the two-letter UF")
+	ErrZipCodeState    = errors.New("zip code does not belong to the given state")
+	ErrIBGECodeState   = errors.New("ibge_code does not belong to the given state")
+)
+
+// checkAddress normaliza CEP e UF e confere se batem entre si e com o código
+// IBGE do município, quando informado.
+func checkAddress(req *AddressRequest) error {
+	zip, err := cep.Normalize(req.ZipCode)
+	if err != nil {
+		return err
+	}
+	req.ZipCode = zip
+
+	req.State = strings.ToUpper(strings.TrimSpace(req.State))
+	if !cep.ValidState(req.State) {
+		return ErrInvalidState
+	}
+	if cep.State(zip) != req.State {
+		return ErrZipCodeState
+	}
+	if req.IBGECode != "" && req.IBGECode[:2] != cep.StateIBGE(req.State) {
+		return ErrIBGECodeState
+	}
+
+	req.Street = strings.TrimSpace(req.Street)
+	req.Number = strings.TrimSpace(req.Number)
+	req.Complement = strings.TrimSpace(req.Complement)
+	req.District = strings.TrimSpace(req.District)
+	req.City = strings.TrimSpace(req.City)
+	return nil
+}
+
+// LookupCEP consulta o endereço do CEP no provedor configurado, para
+// preencher o cadastro.
+func (s *Service) LookupCEP(ctx context.Context, code string) (cep.Address, error) {
+	zip, err := cep.Normalize(code)
+	if err != nil {
+		return cep.Address{}, err
+	}
+	return s.cepProvider.Lookup(ctx, zip)
+}
+
+func (s *Service) ListAddresses(ctx context.Context, orgID, customerID uuid.UUID) ([]db.CustomerAddress, error) {
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	customer := pgtype.UUID{Bytes: customerID, Valid: true}
+
+	if _, err := s.q.GetCustomer(ctx, db.GetCustomerParams{ID: customer, OrganizationID: org}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	addresses, err := s.q.ListCustomerAddresses(ctx, db.ListCustomerAddressesParams{
+		CustomerID:     customer,
+		OrganizationID: org,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if addresses == nil {
+		addresses = []db.CustomerAddress{}
+	}
+	return addresses, nil
+}
+
+// CreateAddress cadastra um endereço do cliente. O primeiro endereço de cada
+// tipo vira o padrão; marcar outro como padrão desmarca o anterior.
+func (s *Service) CreateAddress(ctx context.Context, orgID, customerID uuid.UUID, req AddressRequest) (db.CustomerAddress, error) {
+	if err := checkAddress(&req); err != nil {
+		return db.CustomerAddress{}, err
+	}
+
+	return s.writeAddress(ctx, orgID, customerID, pgtype.UUID{}, req, func(qtx *db.Queries) (db.CustomerAddress, error) {
+		address, err := qtx.CreateCustomerAddress(ctx, db.CreateCustomerAddressParams{
+			Type:           req.Type,
+			IsDefault:      req.IsDefault,
+			ZipCode:        req.ZipCode,
+			Street:         req.Street,
+			Number:         req.Number,
+			Complement:     pgtype.Text{String: req.Complement, Valid: req.Complement != ""},
+			District:       req.District,
+			City:           req.City,
+			State:          req.State,
+			IbgeCode:       pgtype.Text{String: req.IBGECode, Valid: req.IBGECode != ""},
+			CustomerID:     pgtype.UUID{Bytes: customerID, Valid: true},
+			OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.CustomerAddress{}, missingCustomer(ctx, qtx, orgID, customerID)
+		}
+		return address, err
+	})
+}
+
+func (s *Service) UpdateAddress(ctx context.Context, orgID, customerID, addressID uuid.UUID, req AddressRequest) (db.CustomerAddress, error) {
+	if err := checkAddress(&req); err != nil {
+		return db.CustomerAddress{}, err
+	}
+
+	id := pgtype.UUID{Bytes: addressID, Valid: true}
+	return s.writeAddress(ctx, orgID, customerID, id, req, func(qtx *db.Queries) (db.CustomerAddress, error) {
+		address, err := qtx.UpdateCustomerAddress(ctx, db.UpdateCustomerAddressParams{
+			ID:             id,
+			CustomerID:     pgtype.UUID{Bytes: customerID, Valid: true},
+			OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+			Type:           req.Type,
+			IsDefault:      req.IsDefault,
+			ZipCode:        req.ZipCode,
+			Street:         req.Street,
+			Number:         req.Number,
+			Complement:     pgtype.Text{String: req.Complement, Valid: req.Complement != ""},
+			District:       req.District,
+			City:           req.City,
+			State:          req.State,
+			IbgeCode:       pgtype.Text{String: req.IBGECode, Valid: req.IBGECode != ""},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.CustomerAddress{}, ErrAddressNotFound
+		}
+		return address, err
+	})
+}
+
+// writeAddress roda write numa transação, acertando a marca de padrão antes
+// (desmarca os outros do tipo, se este for o padrão) e depois (promove um
+// endereço nos tipos que ficaram sem padrão). keep é o endereço sendo
+// alterado, que não deve ser desmarcado; nulo na criação.
+func (s *Service) writeAddress(ctx context.Context, orgID, customerID uuid.UUID, keep pgtype.UUID, req AddressRequest, write func(qtx *db.Queries) (db.CustomerAddress, error)) (db.CustomerAddress, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return db.CustomerAddress{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	customer := pgtype.UUID{Bytes: customerID, Valid: true}
+
+	if req.IsDefault {
+		if err := qtx.ClearDefaultCustomerAddress(ctx, db.ClearDefaultCustomerAddressParams{
+			OrganizationID: org,
+			CustomerID:     customer,
+			Type:           req.Type,
+			KeepID:         keep,
+		}); err != nil {
+			return db.CustomerAddress{}, err
+		}
+	}
+
+	address, err := write(qtx)
+	if err != nil {
+		return db.CustomerAddress{}, err
+	}
+
+	if err := qtx.EnsureDefaultCustomerAddresses(ctx, db.EnsureDefaultCustomerAddressesParams{
+		CustomerID:     customer,
+		OrganizationID: org,
+	}); err != nil {
+		return db.CustomerAddress{}, err
+	}
+	address, err = qtx.GetCustomerAddress(ctx, db.GetCustomerAddressParams{
+		ID:             address.ID,
+		CustomerID:     customer,
+		OrganizationID: org,
+	})
+	if err != nil {
+		return db.CustomerAddress{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.CustomerAddress{}, err
+	}
+	return address, nil
+}
+
+// DeleteAddress remove o endereço; se era o padrão do tipo, o mais antigo
+// que sobrar assume.
+func (s *Service) DeleteAddress(ctx context.Context, orgID, customerID, addressID uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	customer := pgtype.UUID{Bytes: customerID, Valid: true}
+
+	n, err := qtx.DeleteCustomerAddress(ctx, db.DeleteCustomerAddressParams{
+		ID:             pgtype.UUID{Bytes: addressID, Valid: true},
+		CustomerID:     customer,
+		OrganizationID: org,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAddressNotFound
+	}
+
+	if err := qtx.EnsureDefaultCustomerAddresses(ctx, db.EnsureDefaultCustomerAddressesParams{
+		CustomerID:     customer,
+		OrganizationID: org,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,110 @@
+package customers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// anonymizedName substitui o nome de clientes anonimizados.
+const anonymizedName = "Cliente anonimizado"
+
+// Archive tira o cliente das listagens e de novos pedidos, mantendo cadastro
+// e histórico.
+func (s *Service) Archive(ctx context.Context, orgID, customerID uuid.UUID) (Customer, error) {
+	customer, err := s.q.ArchiveCustomer(ctx, db.ArchiveCustomerParams{
+		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Customer{}, ErrCustomerNotFound
+	}
+	if err != nil {
+		return Customer{}, err
+	}
+	return toCustomer(customer), nil
+}
+
+// Restore reativa um cliente arquivado. Anonimizados ficam arquivados.
+func (s *Service) Restore(ctx context.Context, orgID, customerID uuid.UUID) (Customer, error) {
+	params := db.GetCustomerParams{
+		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	}
+	current, err := s.q.GetCustomer(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Customer{}, ErrCustomerNotFound
+	}
+	if err != nil {
+		return Customer{}, err
+	}
+	if current.AnonymizedAt.Valid {
+		return Customer{}, ErrCustomerAnonymized
+	}
+
+	customer, err := s.q.RestoreCustomer(ctx, db.RestoreCustomerParams(params))
+	if err != nil {
+		return Customer{}, err
+	}
+	return toCustomer(customer), nil
+}
+
+// Anonymize apaga os dados pessoais do cliente (nome, e-mail, telefone,
+// documento e endereços) e o arquiva, atendendo a pedidos de eliminação da
+// LGPD. O registro continua, então pedidos e relatórios de vendas não mudam.
+// Não tem volta.
+func (s *Service) Anonymize(ctx context.Context, orgID, customerID uuid.UUID) (Customer, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return Customer{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	customer, err := qtx.AnonymizeCustomer(ctx, db.AnonymizeCustomerParams{
+		Name:           anonymizedName,
+		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
+		OrganizationID: org,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Customer{}, ErrCustomerNotFound
+	}
+	if err != nil {
+		return Customer{}, err
+	}
+
+	if err := qtx.DeleteAllCustomerAddresses(ctx, db.DeleteAllCustomerAddressesParams{
+		CustomerID:     customer.ID,
+		OrganizationID: org,
+	}); err != nil {
+		return Customer{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Customer{}, err
+	}
+	return toCustomer(customer), nil
+}
+
+// missingCustomer explica por que uma escrita não encontrou o cliente: ele
+// não existe na organização ou foi anonimizado.
+func missingCustomer(ctx context.Context, q *db.Queries, orgID, customerID uuid.UUID) error {
+	customer, err := q.GetCustomer(ctx, db.GetCustomerParams{
+		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrCustomerNotFound
+	case err != nil:
+		return err
+	case customer.AnonymizedAt.Valid:
+		return ErrCustomerAnonymized
+	}
+	return ErrCustomerNotFound
+}
@@ -0,0 +1,56 @@
+package customers
+
+import (
+	"context"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/document"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportColumns usa os nomes de ImportSpec, para que o arquivo exportado
+// possa ser editado e importado de volta.
+var ExportColumns = []string{"id", "name", "email", "phone", "document", "type", "created_at"}
+
+// Export grava os clientes com os filtros e a ordenação de List, sem paginar:
+// as linhas vão para w à medida que saem do banco.
+func (s *Service) Export(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params, w sheet.Writer) error {
+	var b pagination.Builder
+	listWhere(&b, orgID, filter, page.Search)
+
+	rows, err := s.db.Query(ctx, "SELECT c.* FROM customers c"+b.WhereSQL()+page.OrderBy("c.id"), b.Args()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	header := make([]sheet.Cell, len(ExportColumns))
+	for i, col := range ExportColumns {
+		header[i] = sheet.Text(col)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		c, err := pgx.RowToStructByName[db.Customer](rows)
+		if err != nil {
+			return err
+		}
+		if err := w.Write([]sheet.Cell{
+			sheet.Text(uuid.UUID(c.ID.Bytes).String()),
+			sheet.Text(c.Name),
+			sheet.Text(c.Email.String),
+			sheet.Text(c.Phone.String),
+			sheet.Text(document.Format(c.Document.String)),
+			sheet.Text(c.Type.String),
+			sheet.Text(c.CreatedAt.Time.Format("2006-01-02 15:04:05")),
+		}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
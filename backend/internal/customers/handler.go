@@ -1,17 +1,30 @@
 package customers
 
 import (
+	"context"
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/cep"
+	"github.com/dcastro0/aether-backend/internal/export"
+	"github.com/dcastro0/aether-backend/internal/importer"
 	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *validator.Validate
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
 }
 
 func (h *Handler) Create(c *fiber.Ctx) error {
@@ -22,18 +35,49 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	customer, err := h.service.Create(c.Context(), claims.OrgID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(customer)
 }
 
+// Import recebe a planilha em multipart; ver importer.ReadRequest.
+func (h *Handler) Import(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	records, opts, err := importer.ReadRequest(c, ImportSpec)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.service.Import(c.Context(), claims.OrgID, records, opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(result)
+}
+
 func (h *Handler) List(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
-	customers, err := h.service.List(c.Context(), claims.OrgID)
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	customers, err := h.service.List(c.Context(), claims.OrgID, filter, page)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -41,6 +85,41 @@ func (h *Handler) List(c *fiber.Ctx) error {
 	return c.JSON(customers)
 }
 
+// listFilter lê ?status= (active, archived ou all).
+func listFilter(c *fiber.Ctx) (ListFilter, error) {
+	filter := ListFilter{Status: c.Query("status", StatusActive)}
+	switch filter.Status {
+	case StatusActive, StatusArchived, StatusAll:
+		return filter, nil
+	}
+	return ListFilter{}, ErrInvalidStatus
+}
+
+// Export baixa os clientes com os filtros e a ordenação de List, em CSV ou
+// XLSX (?format=).
+func (h *Handler) Export(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	format, err := export.SheetFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return export.Sheet(c, "clientes", format, func(ctx context.Context, w sheet.Writer) error {
+		return h.service.Export(ctx, claims.OrgID, filter, page, w)
+	})
+}
+
 func (h *Handler) Update(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
@@ -54,14 +133,44 @@ func (h *Handler) Update(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
 	}
 
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	customer, err := h.service.Update(c.Context(), claims.OrgID, customerID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(customer)
 }
 
+// Merge junta os clientes de source_ids ao cliente da URL; ver Service.Merge.
+func (h *Handler) Merge(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req MergeCustomersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.service.Merge(c.Context(), claims.OrgID, customerID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(result)
+}
+
 func (h *Handler) Delete(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
@@ -71,8 +180,177 @@ func (h *Handler) Delete(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.Delete(c.Context(), claims.OrgID, customerID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
-}
\ No newline at end of file
+}
+
+func (h *Handler) Archive(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Archive)
+}
+
+func (h *Handler) Restore(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Restore)
+}
+
+// Anonymize apaga os dados pessoais do cliente; ver Service.Anonymize.
+func (h *Handler) Anonymize(c *fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Anonymize)
+}
+
+func (h *Handler) changeStatus(c *fiber.Ctx, change func(ctx context.Context, orgID, customerID uuid.UUID) (Customer, error)) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	customer, err := change(c.Context(), claims.OrgID, customerID)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(customer)
+}
+
+// Summary devolve o resumo de compras do cliente. ?top= limita os produtos
+// mais comprados; limit, cursor, sort e order paginam o histórico.
+func (h *Handler) Summary(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	top := c.QueryInt("top", DefaultTopProducts)
+	if top < 1 || top > MaxTopProducts {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": ErrInvalidTop.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, HistorySortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	summary, err := h.service.Summary(c.Context(), claims.OrgID, customerID, top, page)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(summary)
+}
+
+// LookupCEP devolve o endereço do CEP, para preencher o formulário.
+func (h *Handler) LookupCEP(c *fiber.Ctx) error {
+	address, err := h.service.LookupCEP(c.Context(), c.Params("cep"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(address)
+}
+
+func (h *Handler) ListAddresses(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	addresses, err := h.service.ListAddresses(c.Context(), claims.OrgID, customerID)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(addresses)
+}
+
+func (h *Handler) CreateAddress(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	address, err := h.service.CreateAddress(c.Context(), claims.OrgID, customerID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(address)
+}
+
+func (h *Handler) UpdateAddress(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	addressID, err := uuid.Parse(c.Params("addressId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid address id"})
+	}
+
+	var req AddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	address, err := h.service.UpdateAddress(c.Context(), claims.OrgID, customerID, addressID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(address)
+}
+
+func (h *Handler) DeleteAddress(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	customerID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	addressID, err := uuid.Parse(c.Params("addressId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid address id"})
+	}
+
+	if err := h.service.DeleteAddress(c.Context(), claims.OrgID, customerID, addressID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrCustomerNotFound), errors.Is(err, ErrAddressNotFound), errors.Is(err, cep.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrInvalidDocument), errors.Is(err, ErrDocumentTypeMismatch), errors.Is(err, ErrMergeIntoItself),
+		errors.Is(err, cep.ErrInvalid), errors.Is(err, ErrInvalidState), errors.Is(err, ErrZipCodeState), errors.Is(err, ErrIBGECodeState):
+		return fiber.StatusBadRequest
+	case errors.Is(err, ErrDocumentConflict), errors.Is(err, ErrCustomerHasOrders), errors.Is(err, ErrCustomerAnonymized):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
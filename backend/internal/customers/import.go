@@ -0,0 +1,145 @@
+package customers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/document"
+	"github.com/dcastro0/aether-backend/internal/importer"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ImportSpec são as colunas aceitas em POST /customers/import.
+var ImportSpec = importer.Spec{
+	Fields:   []string{"name", "email", "phone", "document", "type"},
+	Required: []string{"name"},
+}
+
+type importRow struct {
+	id  pgtype.UUID
+	req CreateCustomerRequest
+}
+
+// Import cadastra clientes a partir de uma planilha, com as regras de
+// CreateCustomerRequest; type vazio é deduzido do documento (CNPJ vale
+// company, o resto individual). O documento, comparado sem pontuação, é a
+// chave do upsert: linha com documento já cadastrado
+// atualiza o cliente, mantendo o valor atual dos campos sem coluna.
+func (s *Service) Import(ctx context.Context, orgID uuid.UUID, records []importer.Record, opts importer.Options) (*importer.Result, error) {
+	res := importer.NewResult(opts, len(records))
+
+	existing, err := s.customersByDocument(ctx, orgID, records)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int, len(records))
+	jobs := make([]importer.Job[importRow], 0, len(records))
+	for _, rec := range records {
+		req := CreateCustomerRequest{
+			Name:     rec.Get("name"),
+			Email:    rec.Get("email"),
+			Phone:    rec.Get("phone"),
+			Document: rec.Get("document"),
+			Type:     rec.Get("type"),
+		}
+
+		current, found := existing[document.Normalize(req.Document)]
+		if found {
+			req = mergeCustomerRow(current, rec, req)
+		}
+		if req.Type == "" {
+			req.Type = "individual"
+			if document.Kind(req.Document) == document.CNPJ {
+				req.Type = "company"
+			}
+		}
+		res.Validate(rec.Row, req)
+		if err := checkDocument(&req); err != nil {
+			res.Fail(rec.Row, "document", err.Error())
+		}
+
+		if key := document.Normalize(req.Document); key != "" {
+			if first, dup := seen[key]; dup {
+				res.Fail(rec.Row, "document", fmt.Sprintf("duplicate document, first seen at row %d", first))
+			} else {
+				seen[key] = rec.Row
+			}
+		}
+		if res.Failing(rec.Row) {
+			continue
+		}
+
+		jobs = append(jobs, importer.Job[importRow]{
+			Row:      rec.Row,
+			Existing: found,
+			Data:     importRow{id: current.ID, req: req},
+		})
+	}
+
+	write := func(ctx context.Context, q *db.Queries, job importer.Job[importRow]) error {
+		var err error
+		if job.Existing {
+			_, err = q.UpdateCustomer(ctx, updateParams(job.Data.id, orgID, job.Data.req))
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCustomerAnonymized
+			}
+		} else {
+			_, err = q.CreateCustomer(ctx, createParams(orgID, job.Data.req))
+		}
+		return documentError(err)
+	}
+	public := func(err error) bool {
+		return errors.Is(err, ErrDocumentConflict) || errors.Is(err, ErrCustomerAnonymized)
+	}
+	if err := importer.Commit(ctx, s.db, res, jobs, write, public); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// mergeCustomerRow mantém os dados atuais nos campos que a planilha não traz.
+func mergeCustomerRow(current db.Customer, rec importer.Record, req CreateCustomerRequest) CreateCustomerRequest {
+	if !rec.Has("email") {
+		req.Email = current.Email.String
+	}
+	if !rec.Has("phone") {
+		req.Phone = current.Phone.String
+	}
+	if req.Type == "" {
+		req.Type = current.Type.String
+	}
+	return req
+}
+
+// customersByDocument busca de uma vez os clientes cujos documentos aparecem
+// na planilha, indexados pelo documento normalizado.
+func (s *Service) customersByDocument(ctx context.Context, orgID uuid.UUID, records []importer.Record) (map[string]db.Customer, error) {
+	documents := make([]string, 0, len(records))
+	for _, rec := range records {
+		if v := document.Normalize(rec.Get("document")); v != "" {
+			documents = append(documents, v)
+		}
+	}
+	if len(documents) == 0 {
+		return map[string]db.Customer{}, nil
+	}
+
+	customers, err := s.q.FindCustomersByDocument(ctx, db.FindCustomersByDocumentParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Documents:      documents,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]db.Customer, len(customers))
+	for _, c := range customers {
+		index[document.Normalize(c.Document.String)] = c
+	}
+	return index, nil
+}
@@ -0,0 +1,144 @@
+package customers
+
+import (
+	"context"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type MergeCustomersRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids" validate:"required,min=1,max=50"`
+}
+
+type MergeResult struct {
+	Customer    Customer `json:"customer"`
+	Merged      int      `json:"merged"`
+	OrdersMoved int64    `json:"orders_moved"`
+}
+
+// Merge junta ao cliente targetID os clientes de req.SourceIDs, em geral
+// duplicatas do mesmo comprador. Os pedidos e endereços das origens passam
+// para o destino, e-mail, telefone e documento que faltam no destino são copiados
+// das origens (a mais antiga primeiro) e as origens são apagadas.
+func (s *Service) Merge(ctx context.Context, orgID, targetID uuid.UUID, req MergeCustomersRequest) (MergeResult, error) {
+	ids := []pgtype.UUID{{Bytes: targetID, Valid: true}}
+	seen := map[uuid.UUID]bool{targetID: true}
+	for _, id := range req.SourceIDs {
+		if id == targetID {
+			return MergeResult{}, ErrMergeIntoItself
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, pgtype.UUID{Bytes: id, Valid: true})
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	customers, err := qtx.GetCustomersForUpdate(ctx, db.GetCustomersForUpdateParams{
+		OrganizationID: org,
+		Ids:            ids,
+	})
+	if err != nil {
+		return MergeResult{}, err
+	}
+	if len(customers) != len(ids) {
+		return MergeResult{}, ErrCustomerNotFound
+	}
+
+	var target db.Customer
+	sources := make([]db.Customer, 0, len(customers)-1)
+	for _, c := range customers {
+		if uuid.UUID(c.ID.Bytes) == targetID {
+			target = c
+		} else {
+			sources = append(sources, c)
+		}
+	}
+	if target.AnonymizedAt.Valid {
+		return MergeResult{}, ErrCustomerAnonymized
+	}
+
+	moved, err := qtx.ReassignCustomerOrders(ctx, db.ReassignCustomerOrdersParams{
+		TargetID:       target.ID,
+		OrganizationID: org,
+		SourceIds:      ids[1:],
+	})
+	if err != nil {
+		return MergeResult{}, err
+	}
+
+	if err := qtx.MoveCustomerAddresses(ctx, db.MoveCustomerAddressesParams{
+		TargetID:       target.ID,
+		OrganizationID: org,
+		SourceIds:      ids[1:],
+	}); err != nil {
+		return MergeResult{}, err
+	}
+	if err := qtx.EnsureDefaultCustomerAddresses(ctx, db.EnsureDefaultCustomerAddressesParams{
+		CustomerID:     target.ID,
+		OrganizationID: org,
+	}); err != nil {
+		return MergeResult{}, err
+	}
+
+	// As origens saem antes de o destino herdar o documento, para não
+	// esbarrar no índice único.
+	if err := qtx.DeleteCustomers(ctx, db.DeleteCustomersParams{
+		OrganizationID: org,
+		Ids:            ids[1:],
+	}); err != nil {
+		return MergeResult{}, err
+	}
+
+	merged := mergeFields(target, sources)
+	updated, err := qtx.UpdateCustomer(ctx, updateParams(target.ID, orgID, merged))
+	if err != nil {
+		return MergeResult{}, documentError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return MergeResult{}, err
+	}
+	return MergeResult{
+		Customer:    toCustomer(updated),
+		Merged:      len(sources),
+		OrdersMoved: moved,
+	}, nil
+}
+
+// mergeFields completa os campos vazios do destino com os das origens. Um
+// documento só é herdado se for válido para o tipo do destino.
+func mergeFields(target db.Customer, sources []db.Customer) CreateCustomerRequest {
+	req := CreateCustomerRequest{
+		Name:     target.Name,
+		Email:    target.Email.String,
+		Phone:    target.Phone.String,
+		Document: target.Document.String,
+		Type:     target.Type.String,
+	}
+	for _, src := range sources {
+		if req.Email == "" {
+			req.Email = src.Email.String
+		}
+		if req.Phone == "" {
+			req.Phone = src.Phone.String
+		}
+		if req.Document == "" && src.Document.Valid {
+			candidate := req
+			candidate.Document = src.Document.String
+			if checkDocument(&candidate) == nil {
+				req.Document = candidate.Document
+			}
+		}
+	}
+	return req
+}
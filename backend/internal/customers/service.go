@@ -2,9 +2,16 @@ package customers
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
+	"github.com/dcastro0/aether-backend/internal/cep"
 	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/document"
+	"github.com/dcastro0/aether-backend/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -17,48 +24,220 @@ type CreateCustomerRequest struct {
 	Type     string `json:"type" validate:"oneof=individual company"`
 }
 
+// Customer é o cliente como sai na API. O documento é gravado só com letras e
+// dígitos e devolvido com a máscara de CPF ou CNPJ.
+type Customer struct {
+	db.Customer
+	Document pgtype.Text `json:"document"`
+}
+
+func toCustomer(c db.Customer) Customer {
+	doc := c.Document
+	if doc.Valid {
+		doc.String = document.Format(doc.String)
+	}
+	return Customer{Customer: c, Document: doc}
+}
+
+var (
+	ErrCustomerNotFound     = errors.New("customer not found")
+	ErrInvalidDocument      = errors.New("invalid CPF or CNPJ")
+	ErrDocumentTypeMismatch = errors.New("document does not match customer type: individuals use CPF and companies use CNPJ")
+	ErrDocumentConflict     = errors.New("document already in use by another customer")
+	ErrMergeIntoItself      = errors.New("cannot merge a customer into itself")
+	ErrCustomerHasOrders    = errors.New("customer has orders and cannot be deleted; archive it instead, or anonymize it to erase personal data while keeping the sales history")
+	ErrCustomerAnonymized   = errors.New("customer was anonymized and cannot be changed back")
+	ErrInvalidStatus        = errors.New("status must be active, archived or all")
+)
+
 type Service struct {
-	q  *db.Queries
-	db *pgxpool.Pool
+	q           *db.Queries
+	db          *pgxpool.Pool
+	cepProvider cep.Provider
 }
 
-func NewService(pool *pgxpool.Pool) *Service {
+func NewService(pool *pgxpool.Pool, cepProvider cep.Provider) *Service {
 	return &Service{
-		q:  db.New(pool),
-		db: pool,
+		q:           db.New(pool),
+		db:          pool,
+		cepProvider: cepProvider,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CreateCustomerRequest) (Customer, error) {
+	if err := checkDocument(&req); err != nil {
+		return Customer{}, err
+	}
+
+	customer, err := s.q.CreateCustomer(ctx, createParams(orgID, req))
+	if err != nil {
+		return Customer{}, documentError(err)
+	}
+	return toCustomer(customer), nil
+}
+
+// checkDocument normaliza o documento e confere se é um CPF ou CNPJ válido e
+// compatível com o tipo: pessoa física usa CPF, pessoa jurídica usa CNPJ.
+// Documento é opcional.
+func checkDocument(req *CreateCustomerRequest) error {
+	req.Document = document.Normalize(req.Document)
+	if req.Document == "" {
+		return nil
+	}
+
+	kind := document.Kind(req.Document)
+	if kind == "" {
+		return ErrInvalidDocument
+	}
+	if (kind == document.CPF) != (req.Type == "individual") {
+		return ErrDocumentTypeMismatch
+	}
+	return nil
+}
+
+// documentError traduz a violação do índice único de documento para
+// ErrDocumentConflict.
+func documentError(err error) error {
+	if db.IsUniqueViolation(err, "idx_customers_org_document") {
+		return ErrDocumentConflict
 	}
+	return err
 }
 
-func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CreateCustomerRequest) (db.Customer, error) {
-	return s.q.CreateCustomer(ctx, db.CreateCustomerParams{
+func createParams(orgID uuid.UUID, req CreateCustomerRequest) db.CreateCustomerParams {
+	return db.CreateCustomerParams{
 		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
 		Name:           req.Name,
 		Email:          pgtype.Text{String: req.Email, Valid: req.Email != ""},
 		Phone:          pgtype.Text{String: req.Phone, Valid: req.Phone != ""},
 		Document:       pgtype.Text{String: req.Document, Valid: req.Document != ""},
 		Type:           pgtype.Text{String: req.Type, Valid: req.Type != ""},
-	})
+	}
 }
 
-func (s *Service) List(ctx context.Context, orgID uuid.UUID) ([]db.Customer, error) {
-	return s.q.ListCustomers(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+// SortFields são as ordenações aceitas em GET /customers.
+var SortFields = []pagination.SortField{
+	{Name: "created_at", Column: "c.created_at", Type: "timestamptz"},
+	{Name: "name", Column: "c.name", Type: "text"},
+	{Name: "email", Column: "COALESCE(c.email, '')", Type: "text"},
 }
 
-func (s *Service) Update(ctx context.Context, orgID uuid.UUID, customerID uuid.UUID, req CreateCustomerRequest) (db.Customer, error) {
-	return s.q.UpdateCustomer(ctx, db.UpdateCustomerParams{
-		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
+// Valores de ListFilter.Status.
+const (
+	StatusActive   = "active"
+	StatusArchived = "archived"
+	StatusAll      = "all"
+)
+
+// ListFilter.Status vazio vale StatusActive: arquivados só aparecem quando
+// pedidos.
+type ListFilter struct {
+	Status string
+}
+
+// List devolve uma página dos clientes da organização. A busca procura no
+// nome, e-mail e documento; no documento, com ou sem máscara.
+func (s *Service) List(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params) (pagination.Page[Customer], error) {
+	var b pagination.Builder
+	listWhere(&b, orgID, filter, page.Search)
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM customers c"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[Customer]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT c.* FROM customers c"+page.Keyset(&b, "c.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[Customer]{}, err
+	}
+	customers, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.Customer])
+	if err != nil {
+		return pagination.Page[Customer]{}, err
+	}
+
+	items := make([]Customer, len(customers))
+	for i, c := range customers {
+		items[i] = toCustomer(c)
+	}
+	return pagination.NewPage(items, page, total, func(c Customer) (string, uuid.UUID) {
+		return customerSortValue(c.Customer, page.Sort.Name), uuid.UUID(c.ID.Bytes)
+	}), nil
+}
+
+// listWhere monta os filtros de List, reaproveitados por Export.
+func listWhere(b *pagination.Builder, orgID uuid.UUID, filter ListFilter, search string) {
+	b.Where("c.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	switch filter.Status {
+	case StatusArchived:
+		b.Where("NOT c.is_active")
+	case StatusAll:
+	default:
+		b.Where("c.is_active")
+	}
+	if search != "" {
+		pattern := b.Arg(pagination.ContainsPattern(search))
+		cond := "c.name ILIKE " + pattern + " OR c.email ILIKE " + pattern
+		if doc := document.Normalize(search); strings.ContainsAny(doc, "0123456789") {
+			cond += " OR c.document LIKE " + b.Arg(pagination.ContainsPattern(doc))
+		}
+		b.Where("(" + cond + ")")
+	}
+}
+
+func customerSortValue(c db.Customer, sort string) string {
+	switch sort {
+	case "name":
+		return c.Name
+	case "email":
+		return c.Email.String
+	default:
+		return c.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+}
+
+func (s *Service) Update(ctx context.Context, orgID uuid.UUID, customerID uuid.UUID, req CreateCustomerRequest) (Customer, error) {
+	if err := checkDocument(&req); err != nil {
+		return Customer{}, err
+	}
+
+	customer, err := s.q.UpdateCustomer(ctx, updateParams(pgtype.UUID{Bytes: customerID, Valid: true}, orgID, req))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Customer{}, missingCustomer(ctx, s.q, orgID, customerID)
+	}
+	if err != nil {
+		return Customer{}, documentError(err)
+	}
+	return toCustomer(customer), nil
+}
+
+func updateParams(id pgtype.UUID, orgID uuid.UUID, req CreateCustomerRequest) db.UpdateCustomerParams {
+	return db.UpdateCustomerParams{
+		ID:             id,
 		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
 		Name:           req.Name,
 		Email:          pgtype.Text{String: req.Email, Valid: req.Email != ""},
 		Phone:          pgtype.Text{String: req.Phone, Valid: req.Phone != ""},
 		Document:       pgtype.Text{String: req.Document, Valid: req.Document != ""},
 		Type:           pgtype.Text{String: req.Type, Valid: req.Type != ""},
-	})
+	}
 }
 
+// Delete apaga o cliente de vez, com os endereços. Só vale para quem não tem
+// pedidos; os demais são arquivados ou anonimizados, para não perder o
+// histórico de vendas.
 func (s *Service) Delete(ctx context.Context, orgID uuid.UUID, customerID uuid.UUID) error {
-	return s.q.DeleteCustomer(ctx, db.DeleteCustomerParams{
+	n, err := s.q.DeleteCustomer(ctx, db.DeleteCustomerParams{
 		ID:             pgtype.UUID{Bytes: customerID, Valid: true},
 		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
 	})
-}
\ No newline at end of file
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return ErrCustomerHasOrders
+		}
+		return err
+	}
+	if n == 0 {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
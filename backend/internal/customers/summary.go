@@ -0,0 +1,140 @@
+package customers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultTopProducts = 5
+	MaxTopProducts     = 20
+)
+
+var ErrInvalidTop = errors.New("top must be between 1 and 20")
+
+// HistorySortFields são as ordenações aceitas no histórico de pedidos de
+// GET /customers/:id/summary.
+var HistorySortFields = []pagination.SortField{
+	{Name: "created_at", Column: "o.created_at", Type: "timestamptz"},
+	{Name: "total_amount", Column: "o.total_amount", Type: "numeric"},
+}
+
+// Summary é a visão do cliente para quem vai atendê-lo: números de compra,
+// o que mais compra e os pedidos, do mais recente ao mais antigo. Só pedidos
+// concluídos entram nos números; o histórico traz todos.
+type Summary struct {
+	Customer              Customer                          `json:"customer"`
+	OrderCount            int64                             `json:"order_count"`
+	CanceledCount         int64                             `json:"canceled_count"`
+	LifetimeValue         money.Money                       `json:"lifetime_value"`
+	AverageTicket         money.Money                       `json:"average_ticket"`
+	FirstPurchaseAt       *time.Time                        `json:"first_purchase_at"`
+	LastPurchaseAt        *time.Time                        `json:"last_purchase_at"`
+	DaysSinceLastPurchase *int                              `json:"days_since_last_purchase"`
+	TopProducts           []db.ListCustomerTopProductsRow   `json:"top_products"`
+	Orders                pagination.Page[OrderHistoryItem] `json:"orders"`
+}
+
+// OrderHistoryItem é uma linha do histórico de pedidos do cliente.
+type OrderHistoryItem struct {
+	ID            pgtype.UUID        `json:"id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	Status        string             `json:"status"`
+	PaymentMethod string             `json:"payment_method"`
+	TotalAmount   money.Money        `json:"total_amount"`
+	ItemCount     int64              `json:"item_count"`
+}
+
+// Summary monta o resumo do cliente. Os números saem de uma única agregação
+// sobre os pedidos dele, e o histórico é paginado por keyset, os dois
+// apoiados no índice (customer_id, created_at, id).
+func (s *Service) Summary(ctx context.Context, orgID, customerID uuid.UUID, top int, page pagination.Params) (Summary, error) {
+	org := pgtype.UUID{Bytes: orgID, Valid: true}
+	id := pgtype.UUID{Bytes: customerID, Valid: true}
+
+	customer, err := s.q.GetCustomer(ctx, db.GetCustomerParams{ID: id, OrganizationID: org})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Summary{}, ErrCustomerNotFound
+	}
+	if err != nil {
+		return Summary{}, err
+	}
+
+	stats, err := s.q.GetCustomerStats(ctx, db.GetCustomerStatsParams{CustomerID: id, OrganizationID: org})
+	if err != nil {
+		return Summary{}, err
+	}
+
+	products, err := s.q.ListCustomerTopProducts(ctx, db.ListCustomerTopProductsParams{
+		CustomerID:     id,
+		OrganizationID: org,
+		Limit:          int32(top),
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	if products == nil {
+		products = []db.ListCustomerTopProductsRow{}
+	}
+
+	orders, err := s.orderHistory(ctx, orgID, customerID, page)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		Customer:      toCustomer(customer),
+		OrderCount:    stats.OrderCount,
+		CanceledCount: stats.CanceledCount,
+		LifetimeValue: stats.LifetimeValue,
+		AverageTicket: stats.LifetimeValue.MulRatio(1, stats.OrderCount),
+		TopProducts:   products,
+		Orders:        orders,
+	}
+	if stats.FirstPurchaseAt.Valid {
+		summary.FirstPurchaseAt = &stats.FirstPurchaseAt.Time
+	}
+	if stats.LastPurchaseAt.Valid {
+		summary.LastPurchaseAt = &stats.LastPurchaseAt.Time
+		days := int(time.Since(stats.LastPurchaseAt.Time) / (24 * time.Hour))
+		summary.DaysSinceLastPurchase = &days
+	}
+	return summary, nil
+}
+
+func (s *Service) orderHistory(ctx context.Context, orgID, customerID uuid.UUID, page pagination.Params) (pagination.Page[OrderHistoryItem], error) {
+	var b pagination.Builder
+	b.Where("o.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	b.Where("o.customer_id = " + b.Arg(pgtype.UUID{Bytes: customerID, Valid: true}))
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM orders o"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[OrderHistoryItem]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT o.id, o.created_at, o.status, o.payment_method, o.total_amount,
+		(SELECT COALESCE(SUM(oi.quantity), 0) FROM order_items oi WHERE oi.order_id = o.id)::BIGINT AS item_count
+		FROM orders o`+page.Keyset(&b, "o.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[OrderHistoryItem]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[OrderHistoryItem])
+	if err != nil {
+		return pagination.Page[OrderHistoryItem]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(o OrderHistoryItem) (string, uuid.UUID) {
+		if page.Sort.Name == "total_amount" {
+			return o.TotalAmount.String(), uuid.UUID(o.ID.Bytes)
+		}
+		return o.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(o.ID.Bytes)
+	}), nil
+}
@@ -22,4 +22,4 @@ func (h *Handler) GetMetrics(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(metrics)
-}
\ No newline at end of file
+}
@@ -4,22 +4,34 @@ import (
 	"context"
 
 	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DailySales struct {
-	Date  string  `json:"date"`
-	Total float64 `json:"total"`
+	Date  string      `json:"date"`
+	Total money.Money `json:"total"`
+}
+
+// CategorySales soma as vendas de uma categoria raiz e de todas as suas
+// subcategorias. CategoryID nulo agrupa os produtos sem categoria.
+type CategorySales struct {
+	CategoryID   pgtype.UUID `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+	OrdersCount  int32       `json:"orders_count"`
+	QuantitySold int64       `json:"quantity_sold"`
+	Total        money.Money `json:"total"`
 }
 
 type MetricsResponse struct {
-	TotalRevenue   float64      `json:"total_revenue"`
-	SalesCount     int32        `json:"sales_count"`
-	CustomersCount int32        `json:"customers_count"`
-	LowStockCount  int32        `json:"low_stock_count"`
-	SalesOverTime  []DailySales `json:"sales_over_time"`
+	TotalRevenue    money.Money     `json:"total_revenue"`
+	SalesCount      int32           `json:"sales_count"`
+	CustomersCount  int32           `json:"customers_count"`
+	LowStockCount   int32           `json:"low_stock_count"`
+	SalesOverTime   []DailySales    `json:"sales_over_time"`
+	SalesByCategory []CategorySales `json:"sales_by_category"`
 }
 
 type Service struct {
@@ -55,11 +67,28 @@ func (s *Service) GetMetrics(ctx context.Context, orgID uuid.UUID) (MetricsRespo
 		})
 	}
 
+	categoryRows, err := s.q.GetSalesByCategory(ctx, pgOrgID)
+	if err != nil {
+		return MetricsResponse{}, err
+	}
+
+	salesByCategory := make([]CategorySales, 0, len(categoryRows))
+	for _, r := range categoryRows {
+		salesByCategory = append(salesByCategory, CategorySales{
+			CategoryID:   r.CategoryID,
+			CategoryName: r.CategoryName,
+			OrdersCount:  r.OrdersCount,
+			QuantitySold: r.QuantitySold,
+			Total:        r.TotalSales,
+		})
+	}
+
 	return MetricsResponse{
-		TotalRevenue:   row.TotalRevenue,
-		SalesCount:     row.SalesCount,
-		CustomersCount: row.CustomersCount,
-		LowStockCount:  row.LowStockCount,
-		SalesOverTime:  salesOverTime,
+		TotalRevenue:    row.TotalRevenue,
+		SalesCount:      row.SalesCount,
+		CustomersCount:  row.CustomersCount,
+		LowStockCount:   row.LowStockCount,
+		SalesOverTime:   salesOverTime,
+		SalesByCategory: salesByCategory,
 	}, nil
-}
\ No newline at end of file
+}
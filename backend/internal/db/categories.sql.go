@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: categories.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO categories (organization_id, parent_id, name)
+VALUES ($1, $2, $3)
+RETURNING id, organization_id, parent_id, name, created_at, updated_at
+`
+
+type CreateCategoryParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	ParentID       pgtype.UUID `json:"parent_id"`
+	Name           string      `json:"name"`
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, createCategory, arg.OrganizationID, arg.ParentID, arg.Name)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1 AND organization_id = $2
+`
+
+type DeleteCategoryParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCategory, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCategory = `-- name: GetCategory :one
+SELECT id, organization_id, parent_id, name, created_at, updated_at FROM categories
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+`
+
+type GetCategoryParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetCategory(ctx context.Context, arg GetCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, getCategory, arg.ID, arg.OrganizationID)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isCategoryDescendant = `-- name: IsCategoryDescendant :one
+WITH RECURSIVE subtree AS (
+    SELECT c.id FROM categories c WHERE c.id = $2
+    UNION ALL
+    SELECT c.id FROM categories c JOIN subtree st ON c.parent_id = st.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree st WHERE st.id = $1::UUID)::BOOLEAN
+`
+
+type IsCategoryDescendantParams struct {
+	Candidate  pgtype.UUID `json:"candidate"`
+	CategoryID pgtype.UUID `json:"category_id"`
+}
+
+// Indica se candidate está na subárvore de category_id (incluindo ela mesma).
+func (q *Queries) IsCategoryDescendant(ctx context.Context, arg IsCategoryDescendantParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isCategoryDescendant, arg.Candidate, arg.CategoryID)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listCategories = `-- name: ListCategories :many
+SELECT
+    c.id, c.organization_id, c.parent_id, c.name, c.created_at, c.updated_at,
+    (SELECT COUNT(*) FROM products p WHERE p.category_id = c.id AND p.parent_id IS NULL)::INT AS product_count
+FROM categories c
+WHERE c.organization_id = $1
+ORDER BY c.name, c.id
+`
+
+type ListCategoriesRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	ParentID       pgtype.UUID        `json:"parent_id"`
+	Name           string             `json:"name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	ProductCount   int32              `json:"product_count"`
+}
+
+// product_count conta só os produtos ligados diretamente à categoria.
+func (q *Queries) ListCategories(ctx context.Context, organizationID pgtype.UUID) ([]ListCategoriesRow, error) {
+	rows, err := q.db.Query(ctx, listCategories, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCategoriesRow
+	for rows.Next() {
+		var i ListCategoriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.ParentID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProductCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockCategories = `-- name: LockCategories :exec
+SELECT id FROM categories
+WHERE organization_id = $1
+FOR UPDATE
+`
+
+// Trava as categorias da organização para que duas movimentações simultâneas
+// não passem ambas pela checagem de ciclo.
+func (q *Queries) LockCategories(ctx context.Context, organizationID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockCategories, organizationID)
+	return err
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE categories
+SET name = $3, parent_id = $4, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, parent_id, name, created_at, updated_at
+`
+
+type UpdateCategoryParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Name           string      `json:"name"`
+	ParentID       pgtype.UUID `json:"parent_id"`
+}
+
+func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, updateCategory,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Name,
+		arg.ParentID,
+	)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ParentID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,327 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: customer_addresses.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearDefaultCustomerAddress = `-- name: ClearDefaultCustomerAddress :exec
+UPDATE customer_addresses
+SET is_default = FALSE, updated_at = NOW()
+WHERE organization_id = $1 AND customer_id = $2
+  AND type = $3 AND is_default
+  AND id IS DISTINCT FROM $4
+`
+
+type ClearDefaultCustomerAddressParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	Type           string      `json:"type"`
+	KeepID         pgtype.UUID `json:"keep_id"`
+}
+
+// Tira a marca de padrão dos outros endereços do mesmo tipo, antes de marcar
+// keep_id; keep_id nulo limpa todos.
+func (q *Queries) ClearDefaultCustomerAddress(ctx context.Context, arg ClearDefaultCustomerAddressParams) error {
+	_, err := q.db.Exec(ctx, clearDefaultCustomerAddress,
+		arg.OrganizationID,
+		arg.CustomerID,
+		arg.Type,
+		arg.KeepID,
+	)
+	return err
+}
+
+const createCustomerAddress = `-- name: CreateCustomerAddress :one
+INSERT INTO customer_addresses (
+  organization_id, customer_id, type, is_default, zip_code, street, number,
+  complement, district, city, state, ibge_code
+)
+SELECT c.organization_id, c.id, $1, $2, $3,
+  $4, $5, $6, $7,
+  $8, $9, $10
+FROM customers c
+WHERE c.id = $11 AND c.organization_id = $12
+  AND c.anonymized_at IS NULL
+RETURNING id, organization_id, customer_id, type, is_default, zip_code, street, number, complement, district, city, state, ibge_code, created_at, updated_at
+`
+
+type CreateCustomerAddressParams struct {
+	Type           string      `json:"type"`
+	IsDefault      bool        `json:"is_default"`
+	ZipCode        string      `json:"zip_code"`
+	Street         string      `json:"street"`
+	Number         string      `json:"number"`
+	Complement     pgtype.Text `json:"complement"`
+	District       string      `json:"district"`
+	City           string      `json:"city"`
+	State          string      `json:"state"`
+	IbgeCode       pgtype.Text `json:"ibge_code"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+// Só grava se o cliente for da organização e não tiver sido anonimizado.
+func (q *Queries) CreateCustomerAddress(ctx context.Context, arg CreateCustomerAddressParams) (CustomerAddress, error) {
+	row := q.db.QueryRow(ctx, createCustomerAddress,
+		arg.Type,
+		arg.IsDefault,
+		arg.ZipCode,
+		arg.Street,
+		arg.Number,
+		arg.Complement,
+		arg.District,
+		arg.City,
+		arg.State,
+		arg.IbgeCode,
+		arg.CustomerID,
+		arg.OrganizationID,
+	)
+	var i CustomerAddress
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.Type,
+		&i.IsDefault,
+		&i.ZipCode,
+		&i.Street,
+		&i.Number,
+		&i.Complement,
+		&i.District,
+		&i.City,
+		&i.State,
+		&i.IbgeCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteAllCustomerAddresses = `-- name: DeleteAllCustomerAddresses :exec
+DELETE FROM customer_addresses
+WHERE customer_id = $1 AND organization_id = $2
+`
+
+type DeleteAllCustomerAddressesParams struct {
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) DeleteAllCustomerAddresses(ctx context.Context, arg DeleteAllCustomerAddressesParams) error {
+	_, err := q.db.Exec(ctx, deleteAllCustomerAddresses, arg.CustomerID, arg.OrganizationID)
+	return err
+}
+
+const deleteCustomerAddress = `-- name: DeleteCustomerAddress :execrows
+DELETE FROM customer_addresses
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3
+`
+
+type DeleteCustomerAddressParams struct {
+	ID             pgtype.UUID `json:"id"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) DeleteCustomerAddress(ctx context.Context, arg DeleteCustomerAddressParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCustomerAddress, arg.ID, arg.CustomerID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const ensureDefaultCustomerAddresses = `-- name: EnsureDefaultCustomerAddresses :exec
+UPDATE customer_addresses a
+SET is_default = TRUE, updated_at = NOW()
+WHERE a.id IN (
+    SELECT DISTINCT ON (f.type) f.id
+    FROM customer_addresses f
+    WHERE f.customer_id = $1 AND f.organization_id = $2
+    ORDER BY f.type, f.created_at, f.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM customer_addresses d
+    WHERE d.customer_id = a.customer_id AND d.type = a.type AND d.is_default
+)
+`
+
+type EnsureDefaultCustomerAddressesParams struct {
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+// Marca como padrão o endereço mais antigo de cada tipo que ficou sem padrão.
+func (q *Queries) EnsureDefaultCustomerAddresses(ctx context.Context, arg EnsureDefaultCustomerAddressesParams) error {
+	_, err := q.db.Exec(ctx, ensureDefaultCustomerAddresses, arg.CustomerID, arg.OrganizationID)
+	return err
+}
+
+const getCustomerAddress = `-- name: GetCustomerAddress :one
+SELECT id, organization_id, customer_id, type, is_default, zip_code, street, number, complement, district, city, state, ibge_code, created_at, updated_at FROM customer_addresses
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3
+`
+
+type GetCustomerAddressParams struct {
+	ID             pgtype.UUID `json:"id"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetCustomerAddress(ctx context.Context, arg GetCustomerAddressParams) (CustomerAddress, error) {
+	row := q.db.QueryRow(ctx, getCustomerAddress, arg.ID, arg.CustomerID, arg.OrganizationID)
+	var i CustomerAddress
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.Type,
+		&i.IsDefault,
+		&i.ZipCode,
+		&i.Street,
+		&i.Number,
+		&i.Complement,
+		&i.District,
+		&i.City,
+		&i.State,
+		&i.IbgeCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCustomerAddresses = `-- name: ListCustomerAddresses :many
+SELECT id, organization_id, customer_id, type, is_default, zip_code, street, number, complement, district, city, state, ibge_code, created_at, updated_at FROM customer_addresses
+WHERE customer_id = $1 AND organization_id = $2
+ORDER BY type, is_default DESC, created_at
+`
+
+type ListCustomerAddressesParams struct {
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) ListCustomerAddresses(ctx context.Context, arg ListCustomerAddressesParams) ([]CustomerAddress, error) {
+	rows, err := q.db.Query(ctx, listCustomerAddresses, arg.CustomerID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomerAddress
+	for rows.Next() {
+		var i CustomerAddress
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.CustomerID,
+			&i.Type,
+			&i.IsDefault,
+			&i.ZipCode,
+			&i.Street,
+			&i.Number,
+			&i.Complement,
+			&i.District,
+			&i.City,
+			&i.State,
+			&i.IbgeCode,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveCustomerAddresses = `-- name: MoveCustomerAddresses :exec
+UPDATE customer_addresses
+SET customer_id = $1, is_default = FALSE, updated_at = NOW()
+WHERE organization_id = $2 AND customer_id = ANY($3::UUID[])
+`
+
+type MoveCustomerAddressesParams struct {
+	TargetID       pgtype.UUID   `json:"target_id"`
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	SourceIds      []pgtype.UUID `json:"source_ids"`
+}
+
+// Usado na junção de clientes: os endereços das origens vão para o destino
+// sem a marca de padrão.
+func (q *Queries) MoveCustomerAddresses(ctx context.Context, arg MoveCustomerAddressesParams) error {
+	_, err := q.db.Exec(ctx, moveCustomerAddresses, arg.TargetID, arg.OrganizationID, arg.SourceIds)
+	return err
+}
+
+const updateCustomerAddress = `-- name: UpdateCustomerAddress :one
+UPDATE customer_addresses
+SET type = $4, is_default = $5, zip_code = $6, street = $7, number = $8,
+    complement = $9, district = $10, city = $11, state = $12, ibge_code = $13,
+    updated_at = NOW()
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3
+RETURNING id, organization_id, customer_id, type, is_default, zip_code, street, number, complement, district, city, state, ibge_code, created_at, updated_at
+`
+
+type UpdateCustomerAddressParams struct {
+	ID             pgtype.UUID `json:"id"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Type           string      `json:"type"`
+	IsDefault      bool        `json:"is_default"`
+	ZipCode        string      `json:"zip_code"`
+	Street         string      `json:"street"`
+	Number         string      `json:"number"`
+	Complement     pgtype.Text `json:"complement"`
+	District       string      `json:"district"`
+	City           string      `json:"city"`
+	State          string      `json:"state"`
+	IbgeCode       pgtype.Text `json:"ibge_code"`
+}
+
+func (q *Queries) UpdateCustomerAddress(ctx context.Context, arg UpdateCustomerAddressParams) (CustomerAddress, error) {
+	row := q.db.QueryRow(ctx, updateCustomerAddress,
+		arg.ID,
+		arg.CustomerID,
+		arg.OrganizationID,
+		arg.Type,
+		arg.IsDefault,
+		arg.ZipCode,
+		arg.Street,
+		arg.Number,
+		arg.Complement,
+		arg.District,
+		arg.City,
+		arg.State,
+		arg.IbgeCode,
+	)
+	var i CustomerAddress
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.Type,
+		&i.IsDefault,
+		&i.ZipCode,
+		&i.Street,
+		&i.Number,
+		&i.Complement,
+		&i.District,
+		&i.City,
+		&i.State,
+		&i.IbgeCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
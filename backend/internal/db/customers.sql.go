@@ -8,15 +8,85 @@ package db
 import (
 	"context"
 
+	"github.com/dcastro0/aether-backend/internal/money"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeCustomer = `-- name: AnonymizeCustomer :one
+UPDATE customers
+SET name = $1, email = NULL, phone = NULL, document = NULL,
+    is_active = FALSE, archived_at = COALESCE(archived_at, NOW()),
+    anonymized_at = NOW(), updated_at = NOW()
+WHERE id = $2 AND organization_id = $3
+RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at
+`
+
+type AnonymizeCustomerParams struct {
+	Name           string      `json:"name"`
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+// Apaga os dados pessoais e arquiva; o id continua, então os pedidos ficam
+// ligados ao cliente.
+func (q *Queries) AnonymizeCustomer(ctx context.Context, arg AnonymizeCustomerParams) (Customer, error) {
+	row := q.db.QueryRow(ctx, anonymizeCustomer, arg.Name, arg.ID, arg.OrganizationID)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
+const archiveCustomer = `-- name: ArchiveCustomer :one
+UPDATE customers
+SET is_active = FALSE, archived_at = COALESCE(archived_at, NOW()), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at
+`
+
+type ArchiveCustomerParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) ArchiveCustomer(ctx context.Context, arg ArchiveCustomerParams) (Customer, error) {
+	row := q.db.QueryRow(ctx, archiveCustomer, arg.ID, arg.OrganizationID)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
 const createCustomer = `-- name: CreateCustomer :one
 INSERT INTO customers (
   organization_id, name, email, phone, document, type
 ) VALUES (
   $1, $2, $3, $4, $5, $6
-) RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at
+) RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at
 `
 
 type CreateCustomerParams struct {
@@ -48,11 +118,14 @@ func (q *Queries) CreateCustomer(ctx context.Context, arg CreateCustomerParams)
 		&i.Type,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
 
-const deleteCustomer = `-- name: DeleteCustomer :exec
+const deleteCustomer = `-- name: DeleteCustomer :execrows
 DELETE FROM customers
 WHERE id = $1 AND organization_id = $2
 `
@@ -62,19 +135,159 @@ type DeleteCustomerParams struct {
 	OrganizationID pgtype.UUID `json:"organization_id"`
 }
 
-func (q *Queries) DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) error {
-	_, err := q.db.Exec(ctx, deleteCustomer, arg.ID, arg.OrganizationID)
+func (q *Queries) DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCustomer, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteCustomers = `-- name: DeleteCustomers :exec
+DELETE FROM customers
+WHERE organization_id = $1 AND id = ANY($2::UUID[])
+`
+
+type DeleteCustomersParams struct {
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	Ids            []pgtype.UUID `json:"ids"`
+}
+
+func (q *Queries) DeleteCustomers(ctx context.Context, arg DeleteCustomersParams) error {
+	_, err := q.db.Exec(ctx, deleteCustomers, arg.OrganizationID, arg.Ids)
 	return err
 }
 
-const listCustomers = `-- name: ListCustomers :many
-SELECT id, organization_id, name, email, phone, document, type, created_at, updated_at FROM customers
+const findCustomersByDocument = `-- name: FindCustomersByDocument :many
+SELECT id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at FROM customers
 WHERE organization_id = $1
-ORDER BY created_at DESC
+  AND document = ANY($2::TEXT[])
+`
+
+type FindCustomersByDocumentParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Documents      []string    `json:"documents"`
+}
+
+// Clientes cujo documento está na lista, já normalizada. Usado pelo upsert da
+// importação.
+func (q *Queries) FindCustomersByDocument(ctx context.Context, arg FindCustomersByDocumentParams) ([]Customer, error) {
+	rows, err := q.db.Query(ctx, findCustomersByDocument, arg.OrganizationID, arg.Documents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Customer
+	for rows.Next() {
+		var i Customer
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Name,
+			&i.Email,
+			&i.Phone,
+			&i.Document,
+			&i.Type,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.IsActive,
+			&i.ArchivedAt,
+			&i.AnonymizedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCustomer = `-- name: GetCustomer :one
+SELECT id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at FROM customers
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+`
+
+type GetCustomerParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetCustomer(ctx context.Context, arg GetCustomerParams) (Customer, error) {
+	row := q.db.QueryRow(ctx, getCustomer, arg.ID, arg.OrganizationID)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
+const getCustomerStats = `-- name: GetCustomerStats :one
+SELECT
+    COUNT(*) FILTER (WHERE o.status = 'completed')::BIGINT AS order_count,
+    COUNT(*) FILTER (WHERE o.status = 'canceled')::BIGINT AS canceled_count,
+    COALESCE(SUM(o.total_amount) FILTER (WHERE o.status = 'completed'), 0)::NUMERIC AS lifetime_value,
+    MIN(o.created_at) FILTER (WHERE o.status = 'completed')::TIMESTAMPTZ AS first_purchase_at,
+    MAX(o.created_at) FILTER (WHERE o.status = 'completed')::TIMESTAMPTZ AS last_purchase_at
+FROM orders o
+WHERE o.customer_id = $1 AND o.organization_id = $2
 `
 
-func (q *Queries) ListCustomers(ctx context.Context, organizationID pgtype.UUID) ([]Customer, error) {
-	rows, err := q.db.Query(ctx, listCustomers, organizationID)
+type GetCustomerStatsParams struct {
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+type GetCustomerStatsRow struct {
+	OrderCount      int64              `json:"order_count"`
+	CanceledCount   int64              `json:"canceled_count"`
+	LifetimeValue   money.Money        `json:"lifetime_value"`
+	FirstPurchaseAt pgtype.Timestamptz `json:"first_purchase_at"`
+	LastPurchaseAt  pgtype.Timestamptz `json:"last_purchase_at"`
+}
+
+// Números de compra do cliente numa passada pelos pedidos dele; só pedidos
+// concluídos contam como compra.
+func (q *Queries) GetCustomerStats(ctx context.Context, arg GetCustomerStatsParams) (GetCustomerStatsRow, error) {
+	row := q.db.QueryRow(ctx, getCustomerStats, arg.CustomerID, arg.OrganizationID)
+	var i GetCustomerStatsRow
+	err := row.Scan(
+		&i.OrderCount,
+		&i.CanceledCount,
+		&i.LifetimeValue,
+		&i.FirstPurchaseAt,
+		&i.LastPurchaseAt,
+	)
+	return i, err
+}
+
+const getCustomersForUpdate = `-- name: GetCustomersForUpdate :many
+SELECT id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at FROM customers
+WHERE organization_id = $1 AND id = ANY($2::UUID[])
+ORDER BY created_at, id
+FOR UPDATE
+`
+
+type GetCustomersForUpdateParams struct {
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	Ids            []pgtype.UUID `json:"ids"`
+}
+
+// Trava os clientes envolvidos numa junção.
+func (q *Queries) GetCustomersForUpdate(ctx context.Context, arg GetCustomersForUpdateParams) ([]Customer, error) {
+	rows, err := q.db.Query(ctx, getCustomersForUpdate, arg.OrganizationID, arg.Ids)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +305,72 @@ func (q *Queries) ListCustomers(ctx context.Context, organizationID pgtype.UUID)
 			&i.Type,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.IsActive,
+			&i.ArchivedAt,
+			&i.AnonymizedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCustomerTopProducts = `-- name: ListCustomerTopProducts :many
+SELECT
+    p.id AS product_id,
+    p.name,
+    p.sku,
+    SUM(oi.quantity)::BIGINT AS quantity,
+    SUM(oi.total_price)::NUMERIC AS total,
+    COUNT(DISTINCT o.id)::BIGINT AS order_count,
+    MAX(o.created_at)::TIMESTAMPTZ AS last_purchased_at
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+JOIN products p ON p.id = oi.product_id
+WHERE o.customer_id = $1 AND o.organization_id = $2 AND o.status = 'completed'
+GROUP BY p.id, p.name, p.sku
+ORDER BY total DESC, quantity DESC, p.id
+LIMIT $3
+`
+
+type ListCustomerTopProductsParams struct {
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Limit          int32       `json:"limit"`
+}
+
+type ListCustomerTopProductsRow struct {
+	ProductID       pgtype.UUID        `json:"product_id"`
+	Name            string             `json:"name"`
+	Sku             pgtype.Text        `json:"sku"`
+	Quantity        int64              `json:"quantity"`
+	Total           money.Money        `json:"total"`
+	OrderCount      int64              `json:"order_count"`
+	LastPurchasedAt pgtype.Timestamptz `json:"last_purchased_at"`
+}
+
+// Produtos que o cliente mais comprou, por valor, em pedidos concluídos.
+func (q *Queries) ListCustomerTopProducts(ctx context.Context, arg ListCustomerTopProductsParams) ([]ListCustomerTopProductsRow, error) {
+	rows, err := q.db.Query(ctx, listCustomerTopProducts, arg.CustomerID, arg.OrganizationID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCustomerTopProductsRow
+	for rows.Next() {
+		var i ListCustomerTopProductsRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.Name,
+			&i.Sku,
+			&i.Quantity,
+			&i.Total,
+			&i.OrderCount,
+			&i.LastPurchasedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -103,11 +382,63 @@ func (q *Queries) ListCustomers(ctx context.Context, organizationID pgtype.UUID)
 	return items, nil
 }
 
+const reassignCustomerOrders = `-- name: ReassignCustomerOrders :execrows
+UPDATE orders
+SET customer_id = $1
+WHERE organization_id = $2 AND customer_id = ANY($3::UUID[])
+`
+
+type ReassignCustomerOrdersParams struct {
+	TargetID       pgtype.UUID   `json:"target_id"`
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	SourceIds      []pgtype.UUID `json:"source_ids"`
+}
+
+func (q *Queries) ReassignCustomerOrders(ctx context.Context, arg ReassignCustomerOrdersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignCustomerOrders, arg.TargetID, arg.OrganizationID, arg.SourceIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCustomer = `-- name: RestoreCustomer :one
+UPDATE customers
+SET is_active = TRUE, archived_at = NULL, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at
+`
+
+type RestoreCustomerParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) RestoreCustomer(ctx context.Context, arg RestoreCustomerParams) (Customer, error) {
+	row := q.db.QueryRow(ctx, restoreCustomer, arg.ID, arg.OrganizationID)
+	var i Customer
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
 const updateCustomer = `-- name: UpdateCustomer :one
 UPDATE customers
 SET name = $3, email = $4, phone = $5, document = $6, type = $7, updated_at = NOW()
-WHERE id = $1 AND organization_id = $2
-RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at
+WHERE id = $1 AND organization_id = $2 AND anonymized_at IS NULL
+RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at, is_active, archived_at, anonymized_at
 `
 
 type UpdateCustomerParams struct {
@@ -120,6 +451,7 @@ type UpdateCustomerParams struct {
 	Type           pgtype.Text `json:"type"`
 }
 
+// Clientes anonimizados não voltam a receber dados pessoais.
 func (q *Queries) UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error) {
 	row := q.db.QueryRow(ctx, updateCustomer,
 		arg.ID,
@@ -141,6 +473,9 @@ func (q *Queries) UpdateCustomer(ctx context.Context, arg UpdateCustomerParams)
 		&i.Type,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.IsActive,
+		&i.ArchivedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Códigos SQLSTATE do PostgreSQL tratados pelos serviços.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// IsUniqueViolation informa se err veio de uma constraint UNIQUE. Com
+// constraint não vazio, só casa se for aquela constraint (ou índice).
+func IsUniqueViolation(err error, constraint string) bool {
+	return hasCode(err, uniqueViolation, constraint)
+}
+
+// IsForeignKeyViolation informa se err veio de uma FOREIGN KEY.
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, foreignKeyViolation, "")
+}
+
+func hasCode(err error, code, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != code {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: invitations.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInvitation = `-- name: CreateInvitation :one
+INSERT INTO organization_invitations (
+  organization_id, email, role, token_hash, invited_by, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, organization_id, email, role, token_hash, invited_by, status, expires_at, responded_at, created_at
+`
+
+type CreateInvitationParams struct {
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	Email          string             `json:"email"`
+	Role           UserRole           `json:"role"`
+	TokenHash      string             `json:"token_hash"`
+	InvitedBy      pgtype.UUID        `json:"invited_by"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateInvitation(ctx context.Context, arg CreateInvitationParams) (OrganizationInvitation, error) {
+	row := q.db.QueryRow(ctx, createInvitation,
+		arg.OrganizationID,
+		arg.Email,
+		arg.Role,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i OrganizationInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInvitationByTokenHash = `-- name: GetInvitationByTokenHash :one
+SELECT i.id, i.organization_id, i.email, i.role, i.token_hash, i.invited_by, i.status, i.expires_at, i.responded_at, i.created_at, o.name AS organization_name
+FROM organization_invitations i
+JOIN organizations o ON o.id = i.organization_id
+WHERE i.token_hash = $1 LIMIT 1
+`
+
+type GetInvitationByTokenHashRow struct {
+	ID               pgtype.UUID        `json:"id"`
+	OrganizationID   pgtype.UUID        `json:"organization_id"`
+	Email            string             `json:"email"`
+	Role             UserRole           `json:"role"`
+	TokenHash        string             `json:"token_hash"`
+	InvitedBy        pgtype.UUID        `json:"invited_by"`
+	Status           string             `json:"status"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	RespondedAt      pgtype.Timestamptz `json:"responded_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	OrganizationName string             `json:"organization_name"`
+}
+
+func (q *Queries) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (GetInvitationByTokenHashRow, error) {
+	row := q.db.QueryRow(ctx, getInvitationByTokenHash, tokenHash)
+	var i GetInvitationByTokenHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+		&i.OrganizationName,
+	)
+	return i, err
+}
+
+const getInvitationByTokenHashForUpdate = `-- name: GetInvitationByTokenHashForUpdate :one
+SELECT id, organization_id, email, role, token_hash, invited_by, status, expires_at, responded_at, created_at FROM organization_invitations
+WHERE token_hash = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetInvitationByTokenHashForUpdate(ctx context.Context, tokenHash string) (OrganizationInvitation, error) {
+	row := q.db.QueryRow(ctx, getInvitationByTokenHashForUpdate, tokenHash)
+	var i OrganizationInvitation
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.RespondedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPendingInvitations = `-- name: ListPendingInvitations :many
+SELECT id, organization_id, email, role, token_hash, invited_by, status, expires_at, responded_at, created_at FROM organization_invitations
+WHERE organization_id = $1 AND status = 'pending' AND expires_at > NOW()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPendingInvitations(ctx context.Context, organizationID pgtype.UUID) ([]OrganizationInvitation, error) {
+	rows, err := q.db.Query(ctx, listPendingInvitations, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationInvitation
+	for rows.Next() {
+		var i OrganizationInvitation
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Email,
+			&i.Role,
+			&i.TokenHash,
+			&i.InvitedBy,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.RespondedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeInvitation = `-- name: RevokeInvitation :execrows
+UPDATE organization_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'pending'
+`
+
+type RevokeInvitationParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) RevokeInvitation(ctx context.Context, arg RevokeInvitationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeInvitation, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokePendingInvitationsForEmail = `-- name: RevokePendingInvitationsForEmail :exec
+UPDATE organization_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE organization_id = $1 AND email = $2 AND status = 'pending'
+`
+
+type RevokePendingInvitationsForEmailParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Email          string      `json:"email"`
+}
+
+func (q *Queries) RevokePendingInvitationsForEmail(ctx context.Context, arg RevokePendingInvitationsForEmailParams) error {
+	_, err := q.db.Exec(ctx, revokePendingInvitationsForEmail, arg.OrganizationID, arg.Email)
+	return err
+}
+
+const setInvitationStatus = `-- name: SetInvitationStatus :exec
+UPDATE organization_invitations
+SET status = $2, responded_at = NOW()
+WHERE id = $1
+`
+
+type SetInvitationStatusParams struct {
+	ID     pgtype.UUID `json:"id"`
+	Status string      `json:"status"`
+}
+
+func (q *Queries) SetInvitationStatus(ctx context.Context, arg SetInvitationStatusParams) error {
+	_, err := q.db.Exec(ctx, setInvitationStatus, arg.ID, arg.Status)
+	return err
+}
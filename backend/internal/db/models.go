@@ -8,9 +8,56 @@ import (
 	"database/sql/driver"
 	"fmt"
 
+	"github.com/dcastro0/aether-backend/internal/money"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type StockMovementType string
+
+const (
+	StockMovementTypeSale       StockMovementType = "sale"
+	StockMovementTypeReturn     StockMovementType = "return"
+	StockMovementTypePurchase   StockMovementType = "purchase"
+	StockMovementTypeAdjustment StockMovementType = "adjustment"
+	StockMovementTypeTransfer   StockMovementType = "transfer"
+	StockMovementTypeCount      StockMovementType = "count"
+)
+
+func (e *StockMovementType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StockMovementType(s)
+	case string:
+		*e = StockMovementType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StockMovementType: %T", src)
+	}
+	return nil
+}
+
+type NullStockMovementType struct {
+	StockMovementType StockMovementType `json:"stock_movement_type"`
+	Valid             bool              `json:"valid"` // Valid is true if StockMovementType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStockMovementType) Scan(value interface{}) error {
+	if value == nil {
+		ns.StockMovementType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StockMovementType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStockMovementType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StockMovementType), nil
+}
+
 type UserRole string
 
 const (
@@ -55,6 +102,15 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type Category struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	ParentID       pgtype.UUID        `json:"parent_id"`
+	Name           string             `json:"name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Customer struct {
 	ID             pgtype.UUID        `json:"id"`
 	OrganizationID pgtype.UUID        `json:"organization_id"`
@@ -65,25 +121,50 @@ type Customer struct {
 	Type           pgtype.Text        `json:"type"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	IsActive       bool               `json:"is_active"`
+	ArchivedAt     pgtype.Timestamptz `json:"archived_at"`
+	AnonymizedAt   pgtype.Timestamptz `json:"anonymized_at"`
+}
+
+type CustomerAddress struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	CustomerID     pgtype.UUID        `json:"customer_id"`
+	Type           string             `json:"type"`
+	IsDefault      bool               `json:"is_default"`
+	ZipCode        string             `json:"zip_code"`
+	Street         string             `json:"street"`
+	Number         string             `json:"number"`
+	Complement     pgtype.Text        `json:"complement"`
+	District       string             `json:"district"`
+	City           string             `json:"city"`
+	State          string             `json:"state"`
+	IbgeCode       pgtype.Text        `json:"ibge_code"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Order struct {
 	ID             pgtype.UUID        `json:"id"`
 	OrganizationID pgtype.UUID        `json:"organization_id"`
 	CustomerID     pgtype.UUID        `json:"customer_id"`
-	TotalAmount    pgtype.Numeric     `json:"total_amount"`
+	TotalAmount    money.Money        `json:"total_amount"`
 	Status         string             `json:"status"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	PaymentMethod  string             `json:"payment_method"`
+	CanceledAt     pgtype.Timestamptz `json:"canceled_at"`
+	CanceledBy     pgtype.UUID        `json:"canceled_by"`
+	CancelReason   pgtype.Text        `json:"cancel_reason"`
 }
 
 type OrderItem struct {
-	ID         pgtype.UUID    `json:"id"`
-	OrderID    pgtype.UUID    `json:"order_id"`
-	ProductID  pgtype.UUID    `json:"product_id"`
-	Quantity   int32          `json:"quantity"`
-	UnitPrice  pgtype.Numeric `json:"unit_price"`
-	TotalPrice pgtype.Numeric `json:"total_price"`
+	ID         pgtype.UUID `json:"id"`
+	OrderID    pgtype.UUID `json:"order_id"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	Quantity   int32       `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+	TotalPrice money.Money `json:"total_price"`
+	UnitCost   money.Money `json:"unit_cost"`
 }
 
 type Organization struct {
@@ -96,6 +177,19 @@ type Organization struct {
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
+type OrganizationInvitation struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	Email          string             `json:"email"`
+	Role           UserRole           `json:"role"`
+	TokenHash      string             `json:"token_hash"`
+	InvitedBy      pgtype.UUID        `json:"invited_by"`
+	Status         string             `json:"status"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	RespondedAt    pgtype.Timestamptz `json:"responded_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type OrganizationMember struct {
 	OrganizationID pgtype.UUID        `json:"organization_id"`
 	UserID         pgtype.UUID        `json:"user_id"`
@@ -103,26 +197,157 @@ type OrganizationMember struct {
 	JoinedAt       pgtype.Timestamptz `json:"joined_at"`
 }
 
+type OrganizationSetting struct {
+	OrganizationID       pgtype.UUID        `json:"organization_id"`
+	Currency             string             `json:"currency"`
+	Timezone             string             `json:"timezone"`
+	LowStockThreshold    int32              `json:"low_stock_threshold"`
+	DefaultPaymentMethod string             `json:"default_payment_method"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	SkuPattern           pgtype.Text        `json:"sku_pattern"`
+	SkuNextSeq           int64              `json:"sku_next_seq"`
+}
+
 type Product struct {
+	ID              pgtype.UUID        `json:"id"`
+	OrganizationID  pgtype.UUID        `json:"organization_id"`
+	Name            string             `json:"name"`
+	Description     pgtype.Text        `json:"description"`
+	Price           money.Money        `json:"price"`
+	StockQuantity   int32              `json:"stock_quantity"`
+	Sku             pgtype.Text        `json:"sku"`
+	IsActive        bool               `json:"is_active"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	MinStock        pgtype.Int4        `json:"min_stock"`
+	ReorderQuantity pgtype.Int4        `json:"reorder_quantity"`
+	AverageCost     money.Money        `json:"average_cost"`
+	LastCost        money.Money        `json:"last_cost"`
+	ParentID        pgtype.UUID        `json:"parent_id"`
+	HasVariants     bool               `json:"has_variants"`
+	OptionValues    []string           `json:"option_values"`
+	PriceOverridden bool               `json:"price_overridden"`
+	CategoryID      pgtype.UUID        `json:"category_id"`
+	Tags            []string           `json:"tags"`
+}
+
+type ProductBarcode struct {
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	Code           string             `json:"code"`
+	ProductID      pgtype.UUID        `json:"product_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type ProductOption struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	Position  int16       `json:"position"`
+	Name      string      `json:"name"`
+	Choices   []string    `json:"choices"`
+}
+
+type PurchaseOrder struct {
 	ID             pgtype.UUID        `json:"id"`
 	OrganizationID pgtype.UUID        `json:"organization_id"`
-	Name           string             `json:"name"`
-	Description    pgtype.Text        `json:"description"`
-	Price          pgtype.Numeric     `json:"price"`
-	StockQuantity  int32              `json:"stock_quantity"`
-	Sku            pgtype.Text        `json:"sku"`
-	IsActive       bool               `json:"is_active"`
+	SupplierID     pgtype.UUID        `json:"supplier_id"`
+	Status         string             `json:"status"`
+	TotalAmount    money.Money        `json:"total_amount"`
+	Note           pgtype.Text        `json:"note"`
+	ExpectedAt     pgtype.Date        `json:"expected_at"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	SentAt         pgtype.Timestamptz `json:"sent_at"`
+	ReceivedAt     pgtype.Timestamptz `json:"received_at"`
+	CanceledAt     pgtype.Timestamptz `json:"canceled_at"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
+type PurchaseOrderItem struct {
+	ID               pgtype.UUID `json:"id"`
+	PurchaseOrderID  pgtype.UUID `json:"purchase_order_id"`
+	ProductID        pgtype.UUID `json:"product_id"`
+	QuantityOrdered  int32       `json:"quantity_ordered"`
+	QuantityReceived int32       `json:"quantity_received"`
+	UnitCost         money.Money `json:"unit_cost"`
+}
+
+type PurchaseReceipt struct {
+	ID              pgtype.UUID        `json:"id"`
+	OrganizationID  pgtype.UUID        `json:"organization_id"`
+	PurchaseOrderID pgtype.UUID        `json:"purchase_order_id"`
+	Note            pgtype.Text        `json:"note"`
+	ReceivedBy      pgtype.UUID        `json:"received_by"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type PurchaseReceiptItem struct {
+	ID                  pgtype.UUID `json:"id"`
+	ReceiptID           pgtype.UUID `json:"receipt_id"`
+	PurchaseOrderItemID pgtype.UUID `json:"purchase_order_item_id"`
+	ProductID           pgtype.UUID `json:"product_id"`
+	Quantity            int32       `json:"quantity"`
+	UnitCost            money.Money `json:"unit_cost"`
+}
+
 type RefreshToken struct {
-	ID        pgtype.UUID        `json:"id"`
-	UserID    pgtype.UUID        `json:"user_id"`
-	TokenHash string             `json:"token_hash"`
-	IsRevoked bool               `json:"is_revoked"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	TokenHash      string             `json:"token_hash"`
+	IsRevoked      bool               `json:"is_revoked"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	FamilyID       pgtype.UUID        `json:"family_id"`
+	ReplacedBy     pgtype.UUID        `json:"replaced_by"`
+	RevokedAt      pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type StockCount struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	Status         string             `json:"status"`
+	Note           pgtype.Text        `json:"note"`
+	OpenedBy       pgtype.UUID        `json:"opened_by"`
+	PostedBy       pgtype.UUID        `json:"posted_by"`
+	PostedAt       pgtype.Timestamptz `json:"posted_at"`
+	CanceledAt     pgtype.Timestamptz `json:"canceled_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StockCountItem struct {
+	CountID         pgtype.UUID        `json:"count_id"`
+	ProductID       pgtype.UUID        `json:"product_id"`
+	CountedQuantity int32              `json:"counted_quantity"`
+	SystemQuantity  pgtype.Int4        `json:"system_quantity"`
+	CountedBy       pgtype.UUID        `json:"counted_by"`
+	CountedAt       pgtype.Timestamptz `json:"counted_at"`
+}
+
+type StockMovement struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	ProductID      pgtype.UUID        `json:"product_id"`
+	Type           StockMovementType  `json:"type"`
+	Quantity       int32              `json:"quantity"`
+	BalanceAfter   int32              `json:"balance_after"`
+	ReferenceType  pgtype.Text        `json:"reference_type"`
+	ReferenceID    pgtype.UUID        `json:"reference_id"`
+	Note           pgtype.Text        `json:"note"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ReasonCode     pgtype.Text        `json:"reason_code"`
+}
+
+type Supplier struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	Name           string             `json:"name"`
+	Email          pgtype.Text        `json:"email"`
+	Phone          pgtype.Text        `json:"phone"`
+	Document       pgtype.Text        `json:"document"`
+	Type           pgtype.Text        `json:"type"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
 type User struct {
@@ -8,23 +8,45 @@ package db
 import (
 	"context"
 
+	"github.com/dcastro0/aether-backend/internal/money"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const addProductStock = `-- name: AddProductStock :exec
-UPDATE products
-SET stock_quantity = stock_quantity + $2
-WHERE id = $1
+const cancelOrder = `-- name: CancelOrder :one
+UPDATE orders
+SET status = 'canceled', canceled_at = NOW(), canceled_by = $3, cancel_reason = $4
+WHERE id = $1 AND organization_id = $2 AND status <> 'canceled'
+RETURNING id, organization_id, customer_id, total_amount, status, created_at, payment_method, canceled_at, canceled_by, cancel_reason
 `
 
-type AddProductStockParams struct {
-	ID            pgtype.UUID `json:"id"`
-	StockQuantity int32       `json:"stock_quantity"`
+type CancelOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	CanceledBy     pgtype.UUID `json:"canceled_by"`
+	CancelReason   pgtype.Text `json:"cancel_reason"`
 }
 
-func (q *Queries) AddProductStock(ctx context.Context, arg AddProductStockParams) error {
-	_, err := q.db.Exec(ctx, addProductStock, arg.ID, arg.StockQuantity)
-	return err
+func (q *Queries) CancelOrder(ctx context.Context, arg CancelOrderParams) (Order, error) {
+	row := q.db.QueryRow(ctx, cancelOrder,
+		arg.ID,
+		arg.OrganizationID,
+		arg.CanceledBy,
+		arg.CancelReason,
+	)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.TotalAmount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.PaymentMethod,
+		&i.CanceledAt,
+		&i.CanceledBy,
+		&i.CancelReason,
+	)
+	return i, err
 }
 
 const createOrder = `-- name: CreateOrder :one
@@ -36,11 +58,11 @@ INSERT INTO orders (
 `
 
 type CreateOrderParams struct {
-	OrganizationID pgtype.UUID    `json:"organization_id"`
-	CustomerID     pgtype.UUID    `json:"customer_id"`
-	TotalAmount    pgtype.Numeric `json:"total_amount"`
-	Status         string         `json:"status"`
-	PaymentMethod  string         `json:"payment_method"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	CustomerID     pgtype.UUID `json:"customer_id"`
+	TotalAmount    money.Money `json:"total_amount"`
+	Status         string      `json:"status"`
+	PaymentMethod  string      `json:"payment_method"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (pgtype.UUID, error) {
@@ -58,18 +80,19 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (pgtyp
 
 const createOrderItem = `-- name: CreateOrderItem :one
 INSERT INTO order_items (
-  order_id, product_id, quantity, unit_price, total_price
+  order_id, product_id, quantity, unit_price, total_price, unit_cost
 ) VALUES (
-  $1, $2, $3, $4, $5
-) RETURNING id, order_id, product_id, quantity, unit_price, total_price
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, order_id, product_id, quantity, unit_price, total_price, unit_cost
 `
 
 type CreateOrderItemParams struct {
-	OrderID    pgtype.UUID    `json:"order_id"`
-	ProductID  pgtype.UUID    `json:"product_id"`
-	Quantity   int32          `json:"quantity"`
-	UnitPrice  pgtype.Numeric `json:"unit_price"`
-	TotalPrice pgtype.Numeric `json:"total_price"`
+	OrderID    pgtype.UUID `json:"order_id"`
+	ProductID  pgtype.UUID `json:"product_id"`
+	Quantity   int32       `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+	TotalPrice money.Money `json:"total_price"`
+	UnitCost   money.Money `json:"unit_cost"`
 }
 
 func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error) {
@@ -79,6 +102,7 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		arg.Quantity,
 		arg.UnitPrice,
 		arg.TotalPrice,
+		arg.UnitCost,
 	)
 	var i OrderItem
 	err := row.Scan(
@@ -88,6 +112,81 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 		&i.Quantity,
 		&i.UnitPrice,
 		&i.TotalPrice,
+		&i.UnitCost,
+	)
+	return i, err
+}
+
+const getOrder = `-- name: GetOrder :one
+SELECT o.id, o.organization_id, o.customer_id, o.total_amount, o.status, o.created_at, o.payment_method, o.canceled_at, o.canceled_by, o.cancel_reason, c.name AS customer_name
+FROM orders o
+JOIN customers c ON o.customer_id = c.id
+WHERE o.id = $1 AND o.organization_id = $2 LIMIT 1
+`
+
+type GetOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+type GetOrderRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	CustomerID     pgtype.UUID        `json:"customer_id"`
+	TotalAmount    money.Money        `json:"total_amount"`
+	Status         string             `json:"status"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	PaymentMethod  string             `json:"payment_method"`
+	CanceledAt     pgtype.Timestamptz `json:"canceled_at"`
+	CanceledBy     pgtype.UUID        `json:"canceled_by"`
+	CancelReason   pgtype.Text        `json:"cancel_reason"`
+	CustomerName   string             `json:"customer_name"`
+}
+
+func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (GetOrderRow, error) {
+	row := q.db.QueryRow(ctx, getOrder, arg.ID, arg.OrganizationID)
+	var i GetOrderRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.TotalAmount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.PaymentMethod,
+		&i.CanceledAt,
+		&i.CanceledBy,
+		&i.CancelReason,
+		&i.CustomerName,
+	)
+	return i, err
+}
+
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT id, organization_id, customer_id, total_amount, status, created_at, payment_method, canceled_at, canceled_by, cancel_reason FROM orders
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetOrderForUpdateParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdateParams) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, arg.ID, arg.OrganizationID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.CustomerID,
+		&i.TotalAmount,
+		&i.Status,
+		&i.CreatedAt,
+		&i.PaymentMethod,
+		&i.CanceledAt,
+		&i.CanceledBy,
+		&i.CancelReason,
 	)
 	return i, err
 }
@@ -102,13 +201,13 @@ WHERE oi.order_id = $1
 `
 
 type GetOrderItemsRow struct {
-	ID          pgtype.UUID    `json:"id"`
-	OrderID     pgtype.UUID    `json:"order_id"`
-	ProductID   pgtype.UUID    `json:"product_id"`
-	Quantity    int32          `json:"quantity"`
-	UnitPrice   pgtype.Numeric `json:"unit_price"`
-	TotalPrice  pgtype.Numeric `json:"total_price"`
-	ProductName string         `json:"product_name"`
+	ID          pgtype.UUID `json:"id"`
+	OrderID     pgtype.UUID `json:"order_id"`
+	ProductID   pgtype.UUID `json:"product_id"`
+	Quantity    int32       `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"`
+	TotalPrice  money.Money `json:"total_price"`
+	ProductName string      `json:"product_name"`
 }
 
 func (q *Queries) GetOrderItems(ctx context.Context, orderID pgtype.UUID) ([]GetOrderItemsRow, error) {
@@ -139,45 +238,50 @@ func (q *Queries) GetOrderItems(ctx context.Context, orderID pgtype.UUID) ([]Get
 	return items, nil
 }
 
-const listOrders = `-- name: ListOrders :many
-SELECT 
-    o.id, 
-    o.total_amount, 
-    o.status, 
-    o.created_at,
-    o.payment_method,
-    c.name as customer_name
-FROM orders o
-JOIN customers c ON o.customer_id = c.id
-WHERE o.organization_id = $1
-ORDER BY o.created_at DESC
+const getProductsForOrder = `-- name: GetProductsForOrder :many
+SELECT
+    p.id, p.name, p.price, p.average_cost, p.stock_quantity, p.has_variants,
+    (p.is_active AND COALESCE(parent.is_active, true))::BOOLEAN AS is_active
+FROM products p
+LEFT JOIN products parent ON parent.id = p.parent_id
+WHERE p.organization_id = $1 AND p.id = ANY($2::uuid[])
+ORDER BY p.id
+FOR UPDATE OF p
 `
 
-type ListOrdersRow struct {
-	ID            pgtype.UUID        `json:"id"`
-	TotalAmount   pgtype.Numeric     `json:"total_amount"`
-	Status        string             `json:"status"`
-	CreatedAt     pgtype.Timestamptz `json:"created_at"`
-	PaymentMethod string             `json:"payment_method"`
-	CustomerName  string             `json:"customer_name"`
+type GetProductsForOrderParams struct {
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	Ids            []pgtype.UUID `json:"ids"`
 }
 
-func (q *Queries) ListOrders(ctx context.Context, organizationID pgtype.UUID) ([]ListOrdersRow, error) {
-	rows, err := q.db.Query(ctx, listOrders, organizationID)
+type GetProductsForOrderRow struct {
+	ID            pgtype.UUID `json:"id"`
+	Name          string      `json:"name"`
+	Price         money.Money `json:"price"`
+	AverageCost   money.Money `json:"average_cost"`
+	StockQuantity int32       `json:"stock_quantity"`
+	HasVariants   bool        `json:"has_variants"`
+	IsActive      bool        `json:"is_active"`
+}
+
+// Uma variação só está ativa se o produto pai também estiver.
+func (q *Queries) GetProductsForOrder(ctx context.Context, arg GetProductsForOrderParams) ([]GetProductsForOrderRow, error) {
+	rows, err := q.db.Query(ctx, getProductsForOrder, arg.OrganizationID, arg.Ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListOrdersRow
+	var items []GetProductsForOrderRow
 	for rows.Next() {
-		var i ListOrdersRow
+		var i GetProductsForOrderRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.TotalAmount,
-			&i.Status,
-			&i.CreatedAt,
-			&i.PaymentMethod,
-			&i.CustomerName,
+			&i.Name,
+			&i.Price,
+			&i.AverageCost,
+			&i.StockQuantity,
+			&i.HasVariants,
+			&i.IsActive,
 		); err != nil {
 			return nil, err
 		}
@@ -188,19 +292,3 @@ func (q *Queries) ListOrders(ctx context.Context, organizationID pgtype.UUID) ([
 	}
 	return items, nil
 }
-
-const updateProductStock = `-- name: UpdateProductStock :exec
-UPDATE products
-SET stock_quantity = stock_quantity - $2
-WHERE id = $1 AND stock_quantity >= $2
-`
-
-type UpdateProductStockParams struct {
-	ID            pgtype.UUID `json:"id"`
-	StockQuantity int32       `json:"stock_quantity"`
-}
-
-func (q *Queries) UpdateProductStock(ctx context.Context, arg UpdateProductStockParams) error {
-	_, err := q.db.Exec(ctx, updateProductStock, arg.ID, arg.StockQuantity)
-	return err
-}
@@ -35,6 +35,18 @@ func (q *Queries) AddUserToOrganization(ctx context.Context, arg AddUserToOrgani
 	return i, err
 }
 
+const countOrganizationOwners = `-- name: CountOrganizationOwners :one
+SELECT COUNT(*) FROM organization_members
+WHERE organization_id = $1 AND role = 'owner'
+`
+
+func (q *Queries) CountOrganizationOwners(ctx context.Context, organizationID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizationOwners, organizationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createOrganization = `-- name: CreateOrganization :one
 INSERT INTO organizations (name, slug, document_number)
 VALUES ($1, $2, $3)
@@ -62,6 +74,26 @@ func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganization
 	return i, err
 }
 
+const getOrganizationByID = `-- name: GetOrganizationByID :one
+SELECT id, name, slug, document_number, is_active, created_at, updated_at FROM organizations
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOrganizationByID(ctx context.Context, id pgtype.UUID) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganizationByID, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.DocumentNumber,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getOrganizationBySlug = `-- name: GetOrganizationBySlug :one
 SELECT id, name, slug, document_number, is_active, created_at, updated_at FROM organizations
 WHERE slug = $1 LIMIT 1
@@ -82,11 +114,55 @@ func (q *Queries) GetOrganizationBySlug(ctx context.Context, slug string) (Organ
 	return i, err
 }
 
+const getOrganizationMember = `-- name: GetOrganizationMember :one
+SELECT organization_id, user_id, role, joined_at FROM organization_members
+WHERE organization_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetOrganizationMemberParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetOrganizationMember(ctx context.Context, arg GetOrganizationMemberParams) (OrganizationMember, error) {
+	row := q.db.QueryRow(ctx, getOrganizationMember, arg.OrganizationID, arg.UserID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.UserID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const getOrganizationSettings = `-- name: GetOrganizationSettings :one
+SELECT organization_id, currency, timezone, low_stock_threshold, default_payment_method, updated_at, sku_pattern, sku_next_seq FROM organization_settings
+WHERE organization_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOrganizationSettings(ctx context.Context, organizationID pgtype.UUID) (OrganizationSetting, error) {
+	row := q.db.QueryRow(ctx, getOrganizationSettings, organizationID)
+	var i OrganizationSetting
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Currency,
+		&i.Timezone,
+		&i.LowStockThreshold,
+		&i.DefaultPaymentMethod,
+		&i.UpdatedAt,
+		&i.SkuPattern,
+		&i.SkuNextSeq,
+	)
+	return i, err
+}
+
 const getUserOrganizations = `-- name: GetUserOrganizations :many
 SELECT o.id, o.name, o.slug, om.role
 FROM organizations o
 JOIN organization_members om ON o.id = om.organization_id
-WHERE om.user_id = $1
+WHERE om.user_id = $1 AND o.is_active = true
+ORDER BY om.joined_at ASC, o.name ASC
 `
 
 type GetUserOrganizationsRow struct {
@@ -120,3 +196,182 @@ func (q *Queries) GetUserOrganizations(ctx context.Context, userID pgtype.UUID)
 	}
 	return items, nil
 }
+
+const listOrganizationMembers = `-- name: ListOrganizationMembers :many
+SELECT om.user_id, u.email, u.full_name, om.role, om.joined_at
+FROM organization_members om
+JOIN users u ON u.id = om.user_id
+WHERE om.organization_id = $1
+ORDER BY om.joined_at ASC
+`
+
+type ListOrganizationMembersRow struct {
+	UserID   pgtype.UUID        `json:"user_id"`
+	Email    string             `json:"email"`
+	FullName string             `json:"full_name"`
+	Role     UserRole           `json:"role"`
+	JoinedAt pgtype.Timestamptz `json:"joined_at"`
+}
+
+func (q *Queries) ListOrganizationMembers(ctx context.Context, organizationID pgtype.UUID) ([]ListOrganizationMembersRow, error) {
+	rows, err := q.db.Query(ctx, listOrganizationMembers, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizationMembersRow
+	for rows.Next() {
+		var i ListOrganizationMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.FullName,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrganization = `-- name: LockOrganization :one
+SELECT id FROM organizations
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockOrganization(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockOrganization, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const removeOrganizationMember = `-- name: RemoveOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE organization_id = $1 AND user_id = $2
+`
+
+type RemoveOrganizationMemberParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) RemoveOrganizationMember(ctx context.Context, arg RemoveOrganizationMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeOrganizationMember, arg.OrganizationID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrganization = `-- name: UpdateOrganization :one
+UPDATE organizations
+SET name = $2, slug = $3, document_number = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, slug, document_number, is_active, created_at, updated_at
+`
+
+type UpdateOrganizationParams struct {
+	ID             pgtype.UUID `json:"id"`
+	Name           string      `json:"name"`
+	Slug           string      `json:"slug"`
+	DocumentNumber pgtype.Text `json:"document_number"`
+}
+
+func (q *Queries) UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganization,
+		arg.ID,
+		arg.Name,
+		arg.Slug,
+		arg.DocumentNumber,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.DocumentNumber,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOrganizationMemberRole = `-- name: UpdateOrganizationMemberRole :one
+UPDATE organization_members
+SET role = $3
+WHERE organization_id = $1 AND user_id = $2
+RETURNING organization_id, user_id, role, joined_at
+`
+
+type UpdateOrganizationMemberRoleParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	UserID         pgtype.UUID `json:"user_id"`
+	Role           UserRole    `json:"role"`
+}
+
+func (q *Queries) UpdateOrganizationMemberRole(ctx context.Context, arg UpdateOrganizationMemberRoleParams) (OrganizationMember, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationMemberRole, arg.OrganizationID, arg.UserID, arg.Role)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.UserID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const upsertOrganizationSettings = `-- name: UpsertOrganizationSettings :one
+INSERT INTO organization_settings (
+  organization_id, currency, timezone, low_stock_threshold, default_payment_method, sku_pattern
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (organization_id) DO UPDATE
+SET currency = EXCLUDED.currency,
+    timezone = EXCLUDED.timezone,
+    low_stock_threshold = EXCLUDED.low_stock_threshold,
+    default_payment_method = EXCLUDED.default_payment_method,
+    sku_pattern = EXCLUDED.sku_pattern,
+    updated_at = NOW()
+RETURNING organization_id, currency, timezone, low_stock_threshold, default_payment_method, updated_at, sku_pattern, sku_next_seq
+`
+
+type UpsertOrganizationSettingsParams struct {
+	OrganizationID       pgtype.UUID `json:"organization_id"`
+	Currency             string      `json:"currency"`
+	Timezone             string      `json:"timezone"`
+	LowStockThreshold    int32       `json:"low_stock_threshold"`
+	DefaultPaymentMethod string      `json:"default_payment_method"`
+	SkuPattern           pgtype.Text `json:"sku_pattern"`
+}
+
+func (q *Queries) UpsertOrganizationSettings(ctx context.Context, arg UpsertOrganizationSettingsParams) (OrganizationSetting, error) {
+	row := q.db.QueryRow(ctx, upsertOrganizationSettings,
+		arg.OrganizationID,
+		arg.Currency,
+		arg.Timezone,
+		arg.LowStockThreshold,
+		arg.DefaultPaymentMethod,
+		arg.SkuPattern,
+	)
+	var i OrganizationSetting
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Currency,
+		&i.Timezone,
+		&i.LowStockThreshold,
+		&i.DefaultPaymentMethod,
+		&i.UpdatedAt,
+		&i.SkuPattern,
+		&i.SkuNextSeq,
+	)
+	return i, err
+}
@@ -0,0 +1,149 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: product_barcodes.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addProductBarcode = `-- name: AddProductBarcode :one
+INSERT INTO product_barcodes (organization_id, code, product_id)
+SELECT p.organization_id, $1::VARCHAR, p.id
+FROM products p
+WHERE p.id = $2 AND p.organization_id = $3
+  AND NOT p.has_variants
+RETURNING organization_id, code, product_id, created_at
+`
+
+type AddProductBarcodeParams struct {
+	Code           string      `json:"code"`
+	ProductID      pgtype.UUID `json:"product_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+// Não grava em produtos com variações: o código vai na variação.
+func (q *Queries) AddProductBarcode(ctx context.Context, arg AddProductBarcodeParams) (ProductBarcode, error) {
+	row := q.db.QueryRow(ctx, addProductBarcode, arg.Code, arg.ProductID, arg.OrganizationID)
+	var i ProductBarcode
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Code,
+		&i.ProductID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteProductBarcode = `-- name: DeleteProductBarcode :execrows
+DELETE FROM product_barcodes
+WHERE organization_id = $1 AND product_id = $2 AND code = $3
+`
+
+type DeleteProductBarcodeParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	ProductID      pgtype.UUID `json:"product_id"`
+	Code           string      `json:"code"`
+}
+
+func (q *Queries) DeleteProductBarcode(ctx context.Context, arg DeleteProductBarcodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteProductBarcode, arg.OrganizationID, arg.ProductID, arg.Code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listProductBarcodes = `-- name: ListProductBarcodes :many
+SELECT organization_id, code, product_id, created_at FROM product_barcodes
+WHERE product_id = $1 AND organization_id = $2
+ORDER BY created_at, code
+`
+
+type ListProductBarcodesParams struct {
+	ProductID      pgtype.UUID `json:"product_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) ListProductBarcodes(ctx context.Context, arg ListProductBarcodesParams) ([]ProductBarcode, error) {
+	rows, err := q.db.Query(ctx, listProductBarcodes, arg.ProductID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductBarcode
+	for rows.Next() {
+		var i ProductBarcode
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.Code,
+			&i.ProductID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lookupProduct = `-- name: LookupProduct :one
+SELECT p.id, p.organization_id, p.name, p.description, p.price, p.stock_quantity, p.sku, p.is_active, p.created_at, p.updated_at, p.min_stock, p.reorder_quantity, p.average_cost, p.last_cost, p.parent_id, p.has_variants, p.option_values, p.price_overridden, p.category_id, p.tags FROM (
+    SELECT b.product_id, 1 AS rank
+    FROM product_barcodes b
+    WHERE b.organization_id = $1 AND b.code = $2
+    UNION ALL
+    SELECT s.id, 2
+    FROM products s
+    WHERE s.organization_id = $1 AND LOWER(s.sku) = LOWER($3)
+) m
+JOIN products p ON p.id = m.product_id
+WHERE p.is_active AND NOT p.has_variants
+ORDER BY m.rank, p.created_at
+LIMIT 1
+`
+
+type LookupProductParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Barcode        string      `json:"barcode"`
+	Sku            string      `json:"sku"`
+}
+
+// Resolve um código lido no caixa: primeiro como código de barras (chave
+// primária de product_barcodes), depois como SKU. O código de barras chega
+// normalizado e o SKU como digitado, já que SKUs costumam ter hífens. Só
+// devolve o que pode ser vendido: produtos ativos e sem variações.
+func (q *Queries) LookupProduct(ctx context.Context, arg LookupProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, lookupProduct, arg.OrganizationID, arg.Barcode, arg.Sku)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
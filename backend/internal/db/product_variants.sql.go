@@ -0,0 +1,362 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: product_variants.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createProductOption = `-- name: CreateProductOption :exec
+INSERT INTO product_options (product_id, position, name, choices)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateProductOptionParams struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	Position  int16       `json:"position"`
+	Name      string      `json:"name"`
+	Choices   []string    `json:"choices"`
+}
+
+func (q *Queries) CreateProductOption(ctx context.Context, arg CreateProductOptionParams) error {
+	_, err := q.db.Exec(ctx, createProductOption,
+		arg.ProductID,
+		arg.Position,
+		arg.Name,
+		arg.Choices,
+	)
+	return err
+}
+
+const createProductVariant = `-- name: CreateProductVariant :one
+INSERT INTO products (
+  organization_id, parent_id, name, description, price, sku,
+  min_stock, reorder_quantity, average_cost, last_cost, option_values, category_id, tags
+)
+SELECT
+  p.organization_id, p.id, $1::VARCHAR, p.description, p.price, $2::VARCHAR,
+  p.min_stock, p.reorder_quantity, p.average_cost, p.average_cost, $3::TEXT[],
+  p.category_id, p.tags
+FROM products p
+WHERE p.id = $4
+RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
+`
+
+type CreateProductVariantParams struct {
+	Name         string      `json:"name"`
+	Sku          pgtype.Text `json:"sku"`
+	OptionValues []string    `json:"option_values"`
+	ParentID     pgtype.UUID `json:"parent_id"`
+}
+
+// A variação nasce com o cadastro do pai: preço, custo, descrição, categoria,
+// tags e níveis de reposição. O estoque começa em zero.
+func (q *Queries) CreateProductVariant(ctx context.Context, arg CreateProductVariantParams) (Product, error) {
+	row := q.db.QueryRow(ctx, createProductVariant,
+		arg.Name,
+		arg.Sku,
+		arg.OptionValues,
+		arg.ParentID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const deactivateProductVariants = `-- name: DeactivateProductVariants :exec
+UPDATE products
+SET is_active = false, updated_at = NOW()
+WHERE parent_id = $1 AND is_active
+`
+
+func (q *Queries) DeactivateProductVariants(ctx context.Context, parentID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deactivateProductVariants, parentID)
+	return err
+}
+
+const deleteProductOptions = `-- name: DeleteProductOptions :exec
+DELETE FROM product_options
+WHERE product_id = $1
+`
+
+func (q *Queries) DeleteProductOptions(ctx context.Context, productID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteProductOptions, productID)
+	return err
+}
+
+const getProductVariant = `-- name: GetProductVariant :one
+SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags FROM products
+WHERE id = $1 AND parent_id = $2 AND organization_id = $3 LIMIT 1
+`
+
+type GetProductVariantParams struct {
+	ID             pgtype.UUID `json:"id"`
+	ParentID       pgtype.UUID `json:"parent_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetProductVariant(ctx context.Context, arg GetProductVariantParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductVariant, arg.ID, arg.ParentID, arg.OrganizationID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const listProductOptions = `-- name: ListProductOptions :many
+SELECT product_id, position, name, choices FROM product_options
+WHERE product_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListProductOptions(ctx context.Context, productID pgtype.UUID) ([]ProductOption, error) {
+	rows, err := q.db.Query(ctx, listProductOptions, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductOption
+	for rows.Next() {
+		var i ProductOption
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.Position,
+			&i.Name,
+			&i.Choices,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductVariants = `-- name: ListProductVariants :many
+SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags FROM products
+WHERE parent_id = $1 AND organization_id = $2
+ORDER BY created_at, id
+`
+
+type ListProductVariantsParams struct {
+	ParentID       pgtype.UUID `json:"parent_id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) ListProductVariants(ctx context.Context, arg ListProductVariantsParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductVariants, arg.ParentID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Name,
+			&i.Description,
+			&i.Price,
+			&i.StockQuantity,
+			&i.Sku,
+			&i.IsActive,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MinStock,
+			&i.ReorderQuantity,
+			&i.AverageCost,
+			&i.LastCost,
+			&i.ParentID,
+			&i.HasVariants,
+			&i.OptionValues,
+			&i.PriceOverridden,
+			&i.CategoryID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const productHasHistory = `-- name: ProductHasHistory :one
+SELECT (
+    EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = $1)
+    OR EXISTS (SELECT 1 FROM purchase_order_items pi WHERE pi.product_id = $1)
+    OR EXISTS (SELECT 1 FROM stock_count_items ci WHERE ci.product_id = $1)
+    OR EXISTS (
+        SELECT 1 FROM stock_movements m
+        WHERE m.product_id = $1
+          AND COALESCE(m.reference_type, '') NOT IN ('product', 'opening_balance')
+    )
+)::BOOLEAN AS has_history
+`
+
+// Diz se o produto já foi vendido, comprado, contado ou movimentado além do
+// saldo de abertura. Um produto assim não pode virar pai de variações: o
+// estoque e os pedidos antigos continuam apontando para ele.
+func (q *Queries) ProductHasHistory(ctx context.Context, productID pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, productHasHistory, productID)
+	var has_history bool
+	err := row.Scan(&has_history)
+	return has_history, err
+}
+
+const setProductHasVariants = `-- name: SetProductHasVariants :exec
+UPDATE products
+SET has_variants = true, updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) SetProductHasVariants(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, setProductHasVariants, id)
+	return err
+}
+
+const setVariantActive = `-- name: SetVariantActive :exec
+UPDATE products
+SET is_active = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetVariantActiveParams struct {
+	ID       pgtype.UUID `json:"id"`
+	IsActive bool        `json:"is_active"`
+}
+
+func (q *Queries) SetVariantActive(ctx context.Context, arg SetVariantActiveParams) error {
+	_, err := q.db.Exec(ctx, setVariantActive, arg.ID, arg.IsActive)
+	return err
+}
+
+const syncProductVariants = `-- name: SyncProductVariants :exec
+UPDATE products v
+SET
+  name = p.name || ' - ' || array_to_string(v.option_values, ' / '),
+  description = p.description,
+  category_id = p.category_id,
+  tags = p.tags,
+  price = CASE WHEN v.price_overridden THEN v.price ELSE p.price END,
+  updated_at = NOW()
+FROM products p
+WHERE v.parent_id = p.id AND p.id = $1
+`
+
+// Propaga nome, descrição, categoria, tags e preço do pai para as variações;
+// preço só nas que não têm preço próprio.
+func (q *Queries) SyncProductVariants(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, syncProductVariants, id)
+	return err
+}
+
+const updateProductVariant = `-- name: UpdateProductVariant :one
+UPDATE products
+SET
+  sku = $4,
+  price = $5,
+  price_overridden = $6,
+  is_active = $7,
+  updated_at = NOW()
+WHERE id = $1 AND parent_id = $2 AND organization_id = $3
+RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
+`
+
+type UpdateProductVariantParams struct {
+	ID              pgtype.UUID `json:"id"`
+	ParentID        pgtype.UUID `json:"parent_id"`
+	OrganizationID  pgtype.UUID `json:"organization_id"`
+	Sku             pgtype.Text `json:"sku"`
+	Price           money.Money `json:"price"`
+	PriceOverridden bool        `json:"price_overridden"`
+	IsActive        bool        `json:"is_active"`
+}
+
+func (q *Queries) UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProductVariant,
+		arg.ID,
+		arg.ParentID,
+		arg.OrganizationID,
+		arg.Sku,
+		arg.Price,
+		arg.PriceOverridden,
+		arg.IsActive,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
@@ -8,24 +8,30 @@ package db
 import (
 	"context"
 
+	"github.com/dcastro0/aether-backend/internal/money"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (
-  organization_id, name, description, price, stock_quantity, sku
+  organization_id, name, description, price, sku, min_stock, reorder_quantity, average_cost, last_cost,
+  category_id, tags
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
-) RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $8, $9, $10
+) RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
 `
 
 type CreateProductParams struct {
-	OrganizationID pgtype.UUID    `json:"organization_id"`
-	Name           string         `json:"name"`
-	Description    pgtype.Text    `json:"description"`
-	Price          pgtype.Numeric `json:"price"`
-	StockQuantity  int32          `json:"stock_quantity"`
-	Sku            pgtype.Text    `json:"sku"`
+	OrganizationID  pgtype.UUID `json:"organization_id"`
+	Name            string      `json:"name"`
+	Description     pgtype.Text `json:"description"`
+	Price           money.Money `json:"price"`
+	Sku             pgtype.Text `json:"sku"`
+	MinStock        pgtype.Int4 `json:"min_stock"`
+	ReorderQuantity pgtype.Int4 `json:"reorder_quantity"`
+	AverageCost     money.Money `json:"average_cost"`
+	CategoryID      pgtype.UUID `json:"category_id"`
+	Tags            []string    `json:"tags"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
@@ -34,8 +40,12 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.StockQuantity,
 		arg.Sku,
+		arg.MinStock,
+		arg.ReorderQuantity,
+		arg.AverageCost,
+		arg.CategoryID,
+		arg.Tags,
 	)
 	var i Product
 	err := row.Scan(
@@ -49,38 +59,52 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
 	)
 	return i, err
 }
 
-const getProductMetrics = `-- name: GetProductMetrics :one
-SELECT
-  COUNT(*) as total_products,
-  SUM(CASE WHEN stock_quantity < 5 THEN 1 ELSE 0 END)::BIGINT as low_stock_count
-FROM products
-WHERE organization_id = $1
+const deleteProduct = `-- name: DeleteProduct :execrows
+DELETE FROM products
+WHERE id = $1 AND organization_id = $2
 `
 
-type GetProductMetricsRow struct {
-	TotalProducts int64 `json:"total_products"`
-	LowStockCount int64 `json:"low_stock_count"`
+type DeleteProductParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
 }
 
-func (q *Queries) GetProductMetrics(ctx context.Context, organizationID pgtype.UUID) (GetProductMetricsRow, error) {
-	row := q.db.QueryRow(ctx, getProductMetrics, organizationID)
-	var i GetProductMetricsRow
-	err := row.Scan(&i.TotalProducts, &i.LowStockCount)
-	return i, err
+func (q *Queries) DeleteProduct(ctx context.Context, arg DeleteProductParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteProduct, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const listProducts = `-- name: ListProducts :many
-SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at FROM products
-WHERE organization_id = $1
-ORDER BY created_at DESC
+const findProductsBySKU = `-- name: FindProductsBySKU :many
+SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags FROM products
+WHERE organization_id = $1 AND LOWER(sku) = ANY($2::TEXT[])
 `
 
-func (q *Queries) ListProducts(ctx context.Context, organizationID pgtype.UUID) ([]Product, error) {
-	rows, err := q.db.Query(ctx, listProducts, organizationID)
+type FindProductsBySKUParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Skus           []string    `json:"skus"`
+}
+
+// Produtos e variações com os SKUs informados (em minúsculas), para o upsert
+// da importação.
+func (q *Queries) FindProductsBySKU(ctx context.Context, arg FindProductsBySKUParams) ([]Product, error) {
+	rows, err := q.db.Query(ctx, findProductsBySKU, arg.OrganizationID, arg.Skus)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +123,16 @@ func (q *Queries) ListProducts(ctx context.Context, organizationID pgtype.UUID)
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MinStock,
+			&i.ReorderQuantity,
+			&i.AverageCost,
+			&i.LastCost,
+			&i.ParentID,
+			&i.HasVariants,
+			&i.OptionValues,
+			&i.PriceOverridden,
+			&i.CategoryID,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -110,41 +144,303 @@ func (q *Queries) ListProducts(ctx context.Context, organizationID pgtype.UUID)
 	return items, nil
 }
 
+const getProduct = `-- name: GetProduct :one
+SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags FROM products
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+`
+
+type GetProductParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetProduct(ctx context.Context, arg GetProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getProduct, arg.ID, arg.OrganizationID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getProductCostForUpdate = `-- name: GetProductCostForUpdate :one
+SELECT id, average_cost, stock_quantity FROM products
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetProductCostForUpdateParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+type GetProductCostForUpdateRow struct {
+	ID            pgtype.UUID `json:"id"`
+	AverageCost   money.Money `json:"average_cost"`
+	StockQuantity int32       `json:"stock_quantity"`
+}
+
+func (q *Queries) GetProductCostForUpdate(ctx context.Context, arg GetProductCostForUpdateParams) (GetProductCostForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getProductCostForUpdate, arg.ID, arg.OrganizationID)
+	var i GetProductCostForUpdateRow
+	err := row.Scan(&i.ID, &i.AverageCost, &i.StockQuantity)
+	return i, err
+}
+
+const getProductForUpdate = `-- name: GetProductForUpdate :one
+SELECT id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags FROM products
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetProductForUpdateParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetProductForUpdate(ctx context.Context, arg GetProductForUpdateParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductForUpdate, arg.ID, arg.OrganizationID)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getProductMetrics = `-- name: GetProductMetrics :one
+SELECT
+  COUNT(*) FILTER (WHERE p.parent_id IS NULL) as total_products,
+  COUNT(*) FILTER (
+    WHERE p.is_active AND NOT p.has_variants
+      AND p.stock_quantity < COALESCE(p.min_stock, s.low_stock_threshold, 5)
+  )::BIGINT as low_stock_count
+FROM products p
+LEFT JOIN organization_settings s ON s.organization_id = p.organization_id
+WHERE p.organization_id = $1
+`
+
+type GetProductMetricsRow struct {
+	TotalProducts int64 `json:"total_products"`
+	LowStockCount int64 `json:"low_stock_count"`
+}
+
+// Estoque baixo considera só produtos ativos, comparando com o min_stock do
+// produto ou, na falta dele, com o padrão da organização. Variações não entram
+// no total de produtos; produtos com variações não têm estoque próprio e ficam
+// fora do estoque baixo.
+func (q *Queries) GetProductMetrics(ctx context.Context, organizationID pgtype.UUID) (GetProductMetricsRow, error) {
+	row := q.db.QueryRow(ctx, getProductMetrics, organizationID)
+	var i GetProductMetricsRow
+	err := row.Scan(&i.TotalProducts, &i.LowStockCount)
+	return i, err
+}
+
+const nextSKUSequence = `-- name: NextSKUSequence :one
+UPDATE organization_settings
+SET sku_next_seq = sku_next_seq + 1
+WHERE organization_id = $1 AND sku_pattern IS NOT NULL
+RETURNING sku_pattern::VARCHAR AS pattern, (sku_next_seq - 1)::BIGINT AS seq
+`
+
+type NextSKUSequenceRow struct {
+	Pattern string `json:"pattern"`
+	Seq     int64  `json:"seq"`
+}
+
+// Reserva o próximo sequencial do gerador de SKU, travando a linha de
+// configurações até o fim da transação. Sem padrão configurado, não devolve
+// linha.
+func (q *Queries) NextSKUSequence(ctx context.Context, organizationID pgtype.UUID) (NextSKUSequenceRow, error) {
+	row := q.db.QueryRow(ctx, nextSKUSequence, organizationID)
+	var i NextSKUSequenceRow
+	err := row.Scan(&i.Pattern, &i.Seq)
+	return i, err
+}
+
+const sKUExists = `-- name: SKUExists :one
+SELECT EXISTS (
+  SELECT 1 FROM products
+  WHERE organization_id = $1 AND LOWER(sku) = LOWER($2)
+)::BOOLEAN
+`
+
+type SKUExistsParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Sku            string      `json:"sku"`
+}
+
+func (q *Queries) SKUExists(ctx context.Context, arg SKUExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, sKUExists, arg.OrganizationID, arg.Sku)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const setProductActive = `-- name: SetProductActive :one
+UPDATE products
+SET is_active = $3, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
+`
+
+type SetProductActiveParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	IsActive       bool        `json:"is_active"`
+}
+
+func (q *Queries) SetProductActive(ctx context.Context, arg SetProductActiveParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductActive, arg.ID, arg.OrganizationID, arg.IsActive)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const setProductAverageCost = `-- name: SetProductAverageCost :one
+UPDATE products
+SET average_cost = $3, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
+`
+
+type SetProductAverageCostParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	AverageCost    money.Money `json:"average_cost"`
+}
+
+func (q *Queries) SetProductAverageCost(ctx context.Context, arg SetProductAverageCostParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductAverageCost, arg.ID, arg.OrganizationID, arg.AverageCost)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Description,
+		&i.Price,
+		&i.StockQuantity,
+		&i.Sku,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
+	)
+	return i, err
+}
+
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
 SET
   name = $2,
   description = $3,
   price = $4,
-  stock_quantity = $5,
-  sku = $6,
-  is_active = $7,
+  sku = $5,
+  is_active = $6,
+  min_stock = $8,
+  reorder_quantity = $9,
+  category_id = $10,
+  tags = $11,
   updated_at = NOW()
-WHERE id = $1 AND organization_id = $8
-RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at
+WHERE id = $1 AND organization_id = $7 AND parent_id IS NULL
+RETURNING id, organization_id, name, description, price, stock_quantity, sku, is_active, created_at, updated_at, min_stock, reorder_quantity, average_cost, last_cost, parent_id, has_variants, option_values, price_overridden, category_id, tags
 `
 
 type UpdateProductParams struct {
-	ID             pgtype.UUID    `json:"id"`
-	Name           string         `json:"name"`
-	Description    pgtype.Text    `json:"description"`
-	Price          pgtype.Numeric `json:"price"`
-	StockQuantity  int32          `json:"stock_quantity"`
-	Sku            pgtype.Text    `json:"sku"`
-	IsActive       bool           `json:"is_active"`
-	OrganizationID pgtype.UUID    `json:"organization_id"`
+	ID              pgtype.UUID `json:"id"`
+	Name            string      `json:"name"`
+	Description     pgtype.Text `json:"description"`
+	Price           money.Money `json:"price"`
+	Sku             pgtype.Text `json:"sku"`
+	IsActive        bool        `json:"is_active"`
+	OrganizationID  pgtype.UUID `json:"organization_id"`
+	MinStock        pgtype.Int4 `json:"min_stock"`
+	ReorderQuantity pgtype.Int4 `json:"reorder_quantity"`
+	CategoryID      pgtype.UUID `json:"category_id"`
+	Tags            []string    `json:"tags"`
 }
 
+// Variações são alteradas por UpdateProductVariant.
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
 	row := q.db.QueryRow(ctx, updateProduct,
 		arg.ID,
 		arg.Name,
 		arg.Description,
 		arg.Price,
-		arg.StockQuantity,
 		arg.Sku,
 		arg.IsActive,
 		arg.OrganizationID,
+		arg.MinStock,
+		arg.ReorderQuantity,
+		arg.CategoryID,
+		arg.Tags,
 	)
 	var i Product
 	err := row.Scan(
@@ -158,6 +454,33 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MinStock,
+		&i.ReorderQuantity,
+		&i.AverageCost,
+		&i.LastCost,
+		&i.ParentID,
+		&i.HasVariants,
+		&i.OptionValues,
+		&i.PriceOverridden,
+		&i.CategoryID,
+		&i.Tags,
 	)
 	return i, err
 }
+
+const updateProductCost = `-- name: UpdateProductCost :exec
+UPDATE products
+SET average_cost = $2, last_cost = $3, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateProductCostParams struct {
+	ID          pgtype.UUID `json:"id"`
+	AverageCost money.Money `json:"average_cost"`
+	LastCost    money.Money `json:"last_cost"`
+}
+
+func (q *Queries) UpdateProductCost(ctx context.Context, arg UpdateProductCostParams) error {
+	_, err := q.db.Exec(ctx, updateProductCost, arg.ID, arg.AverageCost, arg.LastCost)
+	return err
+}
@@ -0,0 +1,559 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: purchase_orders.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addPurchaseOrderItemReceived = `-- name: AddPurchaseOrderItemReceived :execrows
+UPDATE purchase_order_items
+SET quantity_received = quantity_received + $1::INTEGER
+WHERE id = $2 AND purchase_order_id = $3
+  AND quantity_received + $1::INTEGER <= quantity_ordered
+`
+
+type AddPurchaseOrderItemReceivedParams struct {
+	Quantity        int32       `json:"quantity"`
+	ID              pgtype.UUID `json:"id"`
+	PurchaseOrderID pgtype.UUID `json:"purchase_order_id"`
+}
+
+// Não deixa receber mais do que o pedido.
+func (q *Queries) AddPurchaseOrderItemReceived(ctx context.Context, arg AddPurchaseOrderItemReceivedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addPurchaseOrderItemReceived, arg.Quantity, arg.ID, arg.PurchaseOrderID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const cancelPurchaseOrder = `-- name: CancelPurchaseOrder :one
+UPDATE purchase_orders
+SET status = 'canceled', canceled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status IN ('draft', 'sent')
+RETURNING id, organization_id, supplier_id, status, total_amount, note, expected_at, created_by, sent_at, received_at, canceled_at, created_at, updated_at
+`
+
+type CancelPurchaseOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) CancelPurchaseOrder(ctx context.Context, arg CancelPurchaseOrderParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, cancelPurchaseOrder, arg.ID, arg.OrganizationID)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countPendingPurchaseOrderItems = `-- name: CountPendingPurchaseOrderItems :one
+SELECT COUNT(*) FROM purchase_order_items
+WHERE purchase_order_id = $1 AND quantity_received < quantity_ordered
+`
+
+func (q *Queries) CountPendingPurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countPendingPurchaseOrderItems, purchaseOrderID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPurchaseOrder = `-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+  organization_id, supplier_id, total_amount, note, expected_at, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, organization_id, supplier_id, status, total_amount, note, expected_at, created_by, sent_at, received_at, canceled_at, created_at, updated_at
+`
+
+type CreatePurchaseOrderParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	SupplierID     pgtype.UUID `json:"supplier_id"`
+	TotalAmount    money.Money `json:"total_amount"`
+	Note           pgtype.Text `json:"note"`
+	ExpectedAt     pgtype.Date `json:"expected_at"`
+	CreatedBy      pgtype.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, createPurchaseOrder,
+		arg.OrganizationID,
+		arg.SupplierID,
+		arg.TotalAmount,
+		arg.Note,
+		arg.ExpectedAt,
+		arg.CreatedBy,
+	)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPurchaseOrderItem = `-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (
+  purchase_order_id, product_id, quantity_ordered, unit_cost
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, purchase_order_id, product_id, quantity_ordered, quantity_received, unit_cost
+`
+
+type CreatePurchaseOrderItemParams struct {
+	PurchaseOrderID pgtype.UUID `json:"purchase_order_id"`
+	ProductID       pgtype.UUID `json:"product_id"`
+	QuantityOrdered int32       `json:"quantity_ordered"`
+	UnitCost        money.Money `json:"unit_cost"`
+}
+
+func (q *Queries) CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error) {
+	row := q.db.QueryRow(ctx, createPurchaseOrderItem,
+		arg.PurchaseOrderID,
+		arg.ProductID,
+		arg.QuantityOrdered,
+		arg.UnitCost,
+	)
+	var i PurchaseOrderItem
+	err := row.Scan(
+		&i.ID,
+		&i.PurchaseOrderID,
+		&i.ProductID,
+		&i.QuantityOrdered,
+		&i.QuantityReceived,
+		&i.UnitCost,
+	)
+	return i, err
+}
+
+const createPurchaseReceipt = `-- name: CreatePurchaseReceipt :one
+INSERT INTO purchase_receipts (
+  organization_id, purchase_order_id, note, received_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, organization_id, purchase_order_id, note, received_by, created_at
+`
+
+type CreatePurchaseReceiptParams struct {
+	OrganizationID  pgtype.UUID `json:"organization_id"`
+	PurchaseOrderID pgtype.UUID `json:"purchase_order_id"`
+	Note            pgtype.Text `json:"note"`
+	ReceivedBy      pgtype.UUID `json:"received_by"`
+}
+
+func (q *Queries) CreatePurchaseReceipt(ctx context.Context, arg CreatePurchaseReceiptParams) (PurchaseReceipt, error) {
+	row := q.db.QueryRow(ctx, createPurchaseReceipt,
+		arg.OrganizationID,
+		arg.PurchaseOrderID,
+		arg.Note,
+		arg.ReceivedBy,
+	)
+	var i PurchaseReceipt
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PurchaseOrderID,
+		&i.Note,
+		&i.ReceivedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPurchaseReceiptItem = `-- name: CreatePurchaseReceiptItem :exec
+INSERT INTO purchase_receipt_items (
+  receipt_id, purchase_order_item_id, product_id, quantity, unit_cost
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type CreatePurchaseReceiptItemParams struct {
+	ReceiptID           pgtype.UUID `json:"receipt_id"`
+	PurchaseOrderItemID pgtype.UUID `json:"purchase_order_item_id"`
+	ProductID           pgtype.UUID `json:"product_id"`
+	Quantity            int32       `json:"quantity"`
+	UnitCost            money.Money `json:"unit_cost"`
+}
+
+func (q *Queries) CreatePurchaseReceiptItem(ctx context.Context, arg CreatePurchaseReceiptItemParams) error {
+	_, err := q.db.Exec(ctx, createPurchaseReceiptItem,
+		arg.ReceiptID,
+		arg.PurchaseOrderItemID,
+		arg.ProductID,
+		arg.Quantity,
+		arg.UnitCost,
+	)
+	return err
+}
+
+const deletePurchaseOrderItems = `-- name: DeletePurchaseOrderItems :exec
+DELETE FROM purchase_order_items
+WHERE purchase_order_id = $1
+`
+
+func (q *Queries) DeletePurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deletePurchaseOrderItems, purchaseOrderID)
+	return err
+}
+
+const getProductsByIDs = `-- name: GetProductsByIDs :many
+SELECT id, name, is_active, has_variants
+FROM products
+WHERE organization_id = $1 AND id = ANY($2::uuid[])
+`
+
+type GetProductsByIDsParams struct {
+	OrganizationID pgtype.UUID   `json:"organization_id"`
+	Ids            []pgtype.UUID `json:"ids"`
+}
+
+type GetProductsByIDsRow struct {
+	ID          pgtype.UUID `json:"id"`
+	Name        string      `json:"name"`
+	IsActive    bool        `json:"is_active"`
+	HasVariants bool        `json:"has_variants"`
+}
+
+func (q *Queries) GetProductsByIDs(ctx context.Context, arg GetProductsByIDsParams) ([]GetProductsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, getProductsByIDs, arg.OrganizationID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductsByIDsRow
+	for rows.Next() {
+		var i GetProductsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.IsActive,
+			&i.HasVariants,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPurchaseOrder = `-- name: GetPurchaseOrder :one
+SELECT po.id, po.organization_id, po.supplier_id, po.status, po.total_amount, po.note, po.expected_at, po.created_by, po.sent_at, po.received_at, po.canceled_at, po.created_at, po.updated_at, s.name AS supplier_name
+FROM purchase_orders po
+JOIN suppliers s ON po.supplier_id = s.id
+WHERE po.id = $1 AND po.organization_id = $2 LIMIT 1
+`
+
+type GetPurchaseOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+type GetPurchaseOrderRow struct {
+	ID             pgtype.UUID        `json:"id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	SupplierID     pgtype.UUID        `json:"supplier_id"`
+	Status         string             `json:"status"`
+	TotalAmount    money.Money        `json:"total_amount"`
+	Note           pgtype.Text        `json:"note"`
+	ExpectedAt     pgtype.Date        `json:"expected_at"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	SentAt         pgtype.Timestamptz `json:"sent_at"`
+	ReceivedAt     pgtype.Timestamptz `json:"received_at"`
+	CanceledAt     pgtype.Timestamptz `json:"canceled_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	SupplierName   string             `json:"supplier_name"`
+}
+
+func (q *Queries) GetPurchaseOrder(ctx context.Context, arg GetPurchaseOrderParams) (GetPurchaseOrderRow, error) {
+	row := q.db.QueryRow(ctx, getPurchaseOrder, arg.ID, arg.OrganizationID)
+	var i GetPurchaseOrderRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SupplierName,
+	)
+	return i, err
+}
+
+const getPurchaseOrderForUpdate = `-- name: GetPurchaseOrderForUpdate :one
+SELECT id, organization_id, supplier_id, status, total_amount, note, expected_at, created_by, sent_at, received_at, canceled_at, created_at, updated_at FROM purchase_orders
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetPurchaseOrderForUpdateParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetPurchaseOrderForUpdate(ctx context.Context, arg GetPurchaseOrderForUpdateParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, getPurchaseOrderForUpdate, arg.ID, arg.OrganizationID)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listPurchaseOrderItems = `-- name: ListPurchaseOrderItems :many
+SELECT i.id, i.purchase_order_id, i.product_id, i.quantity_ordered, i.quantity_received, i.unit_cost, p.name AS product_name, p.sku
+FROM purchase_order_items i
+JOIN products p ON i.product_id = p.id
+WHERE i.purchase_order_id = $1
+ORDER BY p.name, i.id
+`
+
+type ListPurchaseOrderItemsRow struct {
+	ID               pgtype.UUID `json:"id"`
+	PurchaseOrderID  pgtype.UUID `json:"purchase_order_id"`
+	ProductID        pgtype.UUID `json:"product_id"`
+	QuantityOrdered  int32       `json:"quantity_ordered"`
+	QuantityReceived int32       `json:"quantity_received"`
+	UnitCost         money.Money `json:"unit_cost"`
+	ProductName      string      `json:"product_name"`
+	Sku              pgtype.Text `json:"sku"`
+}
+
+func (q *Queries) ListPurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) ([]ListPurchaseOrderItemsRow, error) {
+	rows, err := q.db.Query(ctx, listPurchaseOrderItems, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPurchaseOrderItemsRow
+	for rows.Next() {
+		var i ListPurchaseOrderItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PurchaseOrderID,
+			&i.ProductID,
+			&i.QuantityOrdered,
+			&i.QuantityReceived,
+			&i.UnitCost,
+			&i.ProductName,
+			&i.Sku,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPurchaseReceiptItems = `-- name: ListPurchaseReceiptItems :many
+SELECT
+    r.id AS receipt_id,
+    r.created_at,
+    r.note,
+    r.received_by,
+    ri.purchase_order_item_id,
+    ri.product_id,
+    ri.quantity,
+    ri.unit_cost,
+    p.name AS product_name
+FROM purchase_receipts r
+JOIN purchase_receipt_items ri ON ri.receipt_id = r.id
+JOIN products p ON ri.product_id = p.id
+WHERE r.purchase_order_id = $1
+ORDER BY r.created_at, r.id, p.name
+`
+
+type ListPurchaseReceiptItemsRow struct {
+	ReceiptID           pgtype.UUID        `json:"receipt_id"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	Note                pgtype.Text        `json:"note"`
+	ReceivedBy          pgtype.UUID        `json:"received_by"`
+	PurchaseOrderItemID pgtype.UUID        `json:"purchase_order_item_id"`
+	ProductID           pgtype.UUID        `json:"product_id"`
+	Quantity            int32              `json:"quantity"`
+	UnitCost            money.Money        `json:"unit_cost"`
+	ProductName         string             `json:"product_name"`
+}
+
+func (q *Queries) ListPurchaseReceiptItems(ctx context.Context, purchaseOrderID pgtype.UUID) ([]ListPurchaseReceiptItemsRow, error) {
+	rows, err := q.db.Query(ctx, listPurchaseReceiptItems, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPurchaseReceiptItemsRow
+	for rows.Next() {
+		var i ListPurchaseReceiptItemsRow
+		if err := rows.Scan(
+			&i.ReceiptID,
+			&i.CreatedAt,
+			&i.Note,
+			&i.ReceivedBy,
+			&i.PurchaseOrderItemID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.UnitCost,
+			&i.ProductName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sendPurchaseOrder = `-- name: SendPurchaseOrder :one
+UPDATE purchase_orders
+SET status = 'sent', sent_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'draft'
+RETURNING id, organization_id, supplier_id, status, total_amount, note, expected_at, created_by, sent_at, received_at, canceled_at, created_at, updated_at
+`
+
+type SendPurchaseOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) SendPurchaseOrder(ctx context.Context, arg SendPurchaseOrderParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, sendPurchaseOrder, arg.ID, arg.OrganizationID)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setPurchaseOrderReceiptStatus = `-- name: SetPurchaseOrderReceiptStatus :exec
+UPDATE purchase_orders
+SET status = $1,
+    received_at = CASE WHEN $1 = 'received' THEN NOW() ELSE received_at END,
+    updated_at = NOW()
+WHERE id = $2
+`
+
+type SetPurchaseOrderReceiptStatusParams struct {
+	Status string      `json:"status"`
+	ID     pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) SetPurchaseOrderReceiptStatus(ctx context.Context, arg SetPurchaseOrderReceiptStatusParams) error {
+	_, err := q.db.Exec(ctx, setPurchaseOrderReceiptStatus, arg.Status, arg.ID)
+	return err
+}
+
+const updatePurchaseOrder = `-- name: UpdatePurchaseOrder :one
+UPDATE purchase_orders
+SET supplier_id = $3, total_amount = $4, note = $5, expected_at = $6, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'draft'
+RETURNING id, organization_id, supplier_id, status, total_amount, note, expected_at, created_by, sent_at, received_at, canceled_at, created_at, updated_at
+`
+
+type UpdatePurchaseOrderParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	SupplierID     pgtype.UUID `json:"supplier_id"`
+	TotalAmount    money.Money `json:"total_amount"`
+	Note           pgtype.Text `json:"note"`
+	ExpectedAt     pgtype.Date `json:"expected_at"`
+}
+
+func (q *Queries) UpdatePurchaseOrder(ctx context.Context, arg UpdatePurchaseOrderParams) (PurchaseOrder, error) {
+	row := q.db.QueryRow(ctx, updatePurchaseOrder,
+		arg.ID,
+		arg.OrganizationID,
+		arg.SupplierID,
+		arg.TotalAmount,
+		arg.Note,
+		arg.ExpectedAt,
+	)
+	var i PurchaseOrder
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.SupplierID,
+		&i.Status,
+		&i.TotalAmount,
+		&i.Note,
+		&i.ExpectedAt,
+		&i.CreatedBy,
+		&i.SentAt,
+		&i.ReceivedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -11,32 +11,189 @@ import (
 )
 
 type Querier interface {
-	AddProductStock(ctx context.Context, arg AddProductStockParams) error
+	// Não grava em produtos com variações: o código vai na variação.
+	AddProductBarcode(ctx context.Context, arg AddProductBarcodeParams) (ProductBarcode, error)
+	// Não deixa receber mais do que o pedido.
+	AddPurchaseOrderItemReceived(ctx context.Context, arg AddPurchaseOrderItemReceivedParams) (int64, error)
 	AddUserToOrganization(ctx context.Context, arg AddUserToOrganizationParams) (OrganizationMember, error)
+	// Apaga os dados pessoais e arquiva; o id continua, então os pedidos ficam
+	// ligados ao cliente.
+	AnonymizeCustomer(ctx context.Context, arg AnonymizeCustomerParams) (Customer, error)
+	ArchiveCustomer(ctx context.Context, arg ArchiveCustomerParams) (Customer, error)
+	CancelOrder(ctx context.Context, arg CancelOrderParams) (Order, error)
+	CancelPurchaseOrder(ctx context.Context, arg CancelPurchaseOrderParams) (PurchaseOrder, error)
+	CancelStockCount(ctx context.Context, arg CancelStockCountParams) (StockCount, error)
+	// Tira a marca de padrão dos outros endereços do mesmo tipo, antes de marcar
+	// keep_id; keep_id nulo limpa todos.
+	ClearDefaultCustomerAddress(ctx context.Context, arg ClearDefaultCustomerAddressParams) error
+	CountOrganizationOwners(ctx context.Context, organizationID pgtype.UUID) (int64, error)
+	CountPendingPurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) (int64, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
 	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	// Só grava se o cliente for da organização e não tiver sido anonimizado.
+	CreateCustomerAddress(ctx context.Context, arg CreateCustomerAddressParams) (CustomerAddress, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (OrganizationInvitation, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (pgtype.UUID, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
 	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
+	CreateProductOption(ctx context.Context, arg CreateProductOptionParams) error
+	// A variação nasce com o cadastro do pai: preço, custo, descrição, categoria,
+	// tags e níveis de reposição. O estoque começa em zero.
+	CreateProductVariant(ctx context.Context, arg CreateProductVariantParams) (Product, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	CreatePurchaseReceipt(ctx context.Context, arg CreatePurchaseReceiptParams) (PurchaseReceipt, error)
+	CreatePurchaseReceiptItem(ctx context.Context, arg CreatePurchaseReceiptItemParams) error
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (pgtype.UUID, error)
+	CreateStockCount(ctx context.Context, arg CreateStockCountParams) (StockCount, error)
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error)
-	DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) error
+	DeactivateProductVariants(ctx context.Context, parentID pgtype.UUID) error
+	DeleteAllCustomerAddresses(ctx context.Context, arg DeleteAllCustomerAddressesParams) error
+	DeleteCategory(ctx context.Context, arg DeleteCategoryParams) (int64, error)
+	DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) (int64, error)
+	DeleteCustomerAddress(ctx context.Context, arg DeleteCustomerAddressParams) (int64, error)
+	DeleteCustomers(ctx context.Context, arg DeleteCustomersParams) error
+	DeleteProduct(ctx context.Context, arg DeleteProductParams) (int64, error)
+	DeleteProductBarcode(ctx context.Context, arg DeleteProductBarcodeParams) (int64, error)
+	DeleteProductOptions(ctx context.Context, productID pgtype.UUID) error
+	DeletePurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) error
+	DeleteSupplier(ctx context.Context, arg DeleteSupplierParams) (int64, error)
+	// Marca como padrão o endereço mais antigo de cada tipo que ficou sem padrão.
+	EnsureDefaultCustomerAddresses(ctx context.Context, arg EnsureDefaultCustomerAddressesParams) error
+	// Clientes cujo documento está na lista, já normalizada. Usado pelo upsert da
+	// importação.
+	FindCustomersByDocument(ctx context.Context, arg FindCustomersByDocumentParams) ([]Customer, error)
+	// Produtos e variações com os SKUs informados (em minúsculas), para o upsert
+	// da importação.
+	FindProductsBySKU(ctx context.Context, arg FindProductsBySKUParams) ([]Product, error)
+	GetCategory(ctx context.Context, arg GetCategoryParams) (Category, error)
+	GetCustomer(ctx context.Context, arg GetCustomerParams) (Customer, error)
+	GetCustomerAddress(ctx context.Context, arg GetCustomerAddressParams) (CustomerAddress, error)
+	// Números de compra do cliente numa passada pelos pedidos dele; só pedidos
+	// concluídos contam como compra.
+	GetCustomerStats(ctx context.Context, arg GetCustomerStatsParams) (GetCustomerStatsRow, error)
+	// Trava os clientes envolvidos numa junção.
+	GetCustomersForUpdate(ctx context.Context, arg GetCustomersForUpdateParams) ([]Customer, error)
 	GetDashboardMetrics(ctx context.Context, dollar_1 pgtype.UUID) (GetDashboardMetricsRow, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (GetInvitationByTokenHashRow, error)
+	GetInvitationByTokenHashForUpdate(ctx context.Context, tokenHash string) (OrganizationInvitation, error)
+	// bucket é day, week ou month (date_trunc).
+	GetMarginByPeriod(ctx context.Context, arg GetMarginByPeriodParams) ([]GetMarginByPeriodRow, error)
+	GetOrder(ctx context.Context, arg GetOrderParams) (GetOrderRow, error)
+	GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdateParams) (Order, error)
 	GetOrderItems(ctx context.Context, orderID pgtype.UUID) ([]GetOrderItemsRow, error)
+	GetOrganizationByID(ctx context.Context, id pgtype.UUID) (Organization, error)
 	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetOrganizationMember(ctx context.Context, arg GetOrganizationMemberParams) (OrganizationMember, error)
+	GetOrganizationSettings(ctx context.Context, organizationID pgtype.UUID) (OrganizationSetting, error)
+	GetProduct(ctx context.Context, arg GetProductParams) (Product, error)
+	GetProductCostForUpdate(ctx context.Context, arg GetProductCostForUpdateParams) (GetProductCostForUpdateRow, error)
+	GetProductForUpdate(ctx context.Context, arg GetProductForUpdateParams) (Product, error)
+	// Vendas concluídas no intervalo [from_date, to_date], em datas do fuso da
+	// organização.
+	GetProductMargins(ctx context.Context, arg GetProductMarginsParams) ([]GetProductMarginsRow, error)
+	// Estoque baixo considera só produtos ativos, comparando com o min_stock do
+	// produto ou, na falta dele, com o padrão da organização. Variações não entram
+	// no total de produtos; produtos com variações não têm estoque próprio e ficam
+	// fora do estoque baixo.
 	GetProductMetrics(ctx context.Context, organizationID pgtype.UUID) (GetProductMetricsRow, error)
+	GetProductVariant(ctx context.Context, arg GetProductVariantParams) (Product, error)
+	GetProductsByIDs(ctx context.Context, arg GetProductsByIDsParams) ([]GetProductsByIDsRow, error)
+	// Uma variação só está ativa se o produto pai também estiver.
+	GetProductsForOrder(ctx context.Context, arg GetProductsForOrderParams) ([]GetProductsForOrderRow, error)
+	GetPurchaseOrder(ctx context.Context, arg GetPurchaseOrderParams) (GetPurchaseOrderRow, error)
+	GetPurchaseOrderForUpdate(ctx context.Context, arg GetPurchaseOrderForUpdateParams) (PurchaseOrder, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// Vendas concluídas dos últimos 30 dias por categoria raiz; itens de produtos
+	// sem categoria aparecem com category_id nulo.
+	GetSalesByCategory(ctx context.Context, dollar_1 pgtype.UUID) ([]GetSalesByCategoryRow, error)
 	GetSalesOverTime(ctx context.Context, dollar_1 pgtype.UUID) ([]GetSalesOverTimeRow, error)
+	GetStockCount(ctx context.Context, arg GetStockCountParams) (StockCount, error)
+	GetStockCountForUpdate(ctx context.Context, arg GetStockCountForUpdateParams) (StockCount, error)
+	GetSupplier(ctx context.Context, arg GetSupplierParams) (Supplier, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 	GetUserOrganizations(ctx context.Context, userID pgtype.UUID) ([]GetUserOrganizationsRow, error)
-	ListCustomers(ctx context.Context, organizationID pgtype.UUID) ([]Customer, error)
-	ListOrders(ctx context.Context, organizationID pgtype.UUID) ([]ListOrdersRow, error)
-	ListProducts(ctx context.Context, organizationID pgtype.UUID) ([]Product, error)
+	// Indica se candidate está na subárvore de category_id (incluindo ela mesma).
+	IsCategoryDescendant(ctx context.Context, arg IsCategoryDescendantParams) (bool, error)
+	// product_count conta só os produtos ligados diretamente à categoria.
+	ListCategories(ctx context.Context, organizationID pgtype.UUID) ([]ListCategoriesRow, error)
+	ListCustomerAddresses(ctx context.Context, arg ListCustomerAddressesParams) ([]CustomerAddress, error)
+	// Produtos que o cliente mais comprou, por valor, em pedidos concluídos.
+	ListCustomerTopProducts(ctx context.Context, arg ListCustomerTopProductsParams) ([]ListCustomerTopProductsRow, error)
+	ListOrganizationMembers(ctx context.Context, organizationID pgtype.UUID) ([]ListOrganizationMembersRow, error)
+	ListPendingInvitations(ctx context.Context, organizationID pgtype.UUID) ([]OrganizationInvitation, error)
+	ListProductBarcodes(ctx context.Context, arg ListProductBarcodesParams) ([]ProductBarcode, error)
+	ListProductOptions(ctx context.Context, productID pgtype.UUID) ([]ProductOption, error)
+	ListProductVariants(ctx context.Context, arg ListProductVariantsParams) ([]Product, error)
+	ListPurchaseOrderItems(ctx context.Context, purchaseOrderID pgtype.UUID) ([]ListPurchaseOrderItemsRow, error)
+	ListPurchaseReceiptItems(ctx context.Context, purchaseOrderID pgtype.UUID) ([]ListPurchaseReceiptItemsRow, error)
+	ListStockCountItems(ctx context.Context, countID pgtype.UUID) ([]ListStockCountItemsRow, error)
+	// Trava as categorias da organização para que duas movimentações simultâneas
+	// não passem ambas pela checagem de ciclo.
+	LockCategories(ctx context.Context, organizationID pgtype.UUID) error
+	LockOrganization(ctx context.Context, id pgtype.UUID) (pgtype.UUID, error)
+	LockStockCountProducts(ctx context.Context, countID pgtype.UUID) ([]LockStockCountProductsRow, error)
+	// Resolve um código lido no caixa: primeiro como código de barras (chave
+	// primária de product_barcodes), depois como SKU. O código de barras chega
+	// normalizado e o SKU como digitado, já que SKUs costumam ter hífens. Só
+	// devolve o que pode ser vendido: produtos ativos e sem variações.
+	LookupProduct(ctx context.Context, arg LookupProductParams) (Product, error)
+	// Usado na junção de clientes: os endereços das origens vão para o destino
+	// sem a marca de padrão.
+	MoveCustomerAddresses(ctx context.Context, arg MoveCustomerAddressesParams) error
+	// Reserva o próximo sequencial do gerador de SKU, travando a linha de
+	// configurações até o fim da transação. Sem padrão configurado, não devolve
+	// linha.
+	NextSKUSequence(ctx context.Context, organizationID pgtype.UUID) (NextSKUSequenceRow, error)
+	PostStockCount(ctx context.Context, arg PostStockCountParams) (StockCount, error)
+	// Diz se o produto já foi vendido, comprado, contado ou movimentado além do
+	// saldo de abertura. Um produto assim não pode virar pai de variações: o
+	// estoque e os pedidos antigos continuam apontando para ele.
+	ProductHasHistory(ctx context.Context, productID pgtype.UUID) (bool, error)
+	ReassignCustomerOrders(ctx context.Context, arg ReassignCustomerOrdersParams) (int64, error)
+	// Atualiza o saldo do produto e grava o movimento numa única instrução. Não
+	// devolve linha quando o produto não existe na organização, quando o saldo
+	// ficaria negativo ou quando o produto tem variações (o estoque fica nelas).
+	RecordStockMovement(ctx context.Context, arg RecordStockMovementParams) (StockMovement, error)
+	RemoveOrganizationMember(ctx context.Context, arg RemoveOrganizationMemberParams) (int64, error)
+	RestoreCustomer(ctx context.Context, arg RestoreCustomerParams) (Customer, error)
+	RevokeInvitation(ctx context.Context, arg RevokeInvitationParams) (int64, error)
+	RevokePendingInvitationsForEmail(ctx context.Context, arg RevokePendingInvitationsForEmailParams) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) error
+	RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) error
+	SKUExists(ctx context.Context, arg SKUExistsParams) (bool, error)
+	SendPurchaseOrder(ctx context.Context, arg SendPurchaseOrderParams) (PurchaseOrder, error)
+	SetInvitationStatus(ctx context.Context, arg SetInvitationStatusParams) error
+	SetProductActive(ctx context.Context, arg SetProductActiveParams) (Product, error)
+	SetProductAverageCost(ctx context.Context, arg SetProductAverageCostParams) (Product, error)
+	SetProductHasVariants(ctx context.Context, id pgtype.UUID) error
+	SetPurchaseOrderReceiptStatus(ctx context.Context, arg SetPurchaseOrderReceiptStatusParams) error
+	SetStockCountItemSystemQuantity(ctx context.Context, arg SetStockCountItemSystemQuantityParams) error
+	SetVariantActive(ctx context.Context, arg SetVariantActiveParams) error
+	// Propaga nome, descrição, categoria, tags e preço do pai para as variações;
+	// preço só nas que não têm preço próprio.
+	SyncProductVariants(ctx context.Context, id pgtype.UUID) error
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	// Clientes anonimizados não voltam a receber dados pessoais.
 	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error)
+	UpdateCustomerAddress(ctx context.Context, arg UpdateCustomerAddressParams) (CustomerAddress, error)
+	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
+	UpdateOrganizationMemberRole(ctx context.Context, arg UpdateOrganizationMemberRoleParams) (OrganizationMember, error)
+	// Variações são alteradas por UpdateProductVariant.
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
-	UpdateProductStock(ctx context.Context, arg UpdateProductStockParams) error
+	UpdateProductCost(ctx context.Context, arg UpdateProductCostParams) error
+	UpdateProductVariant(ctx context.Context, arg UpdateProductVariantParams) (Product, error)
+	UpdatePurchaseOrder(ctx context.Context, arg UpdatePurchaseOrderParams) (PurchaseOrder, error)
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
 	UpdateUserName(ctx context.Context, arg UpdateUserNameParams) (UpdateUserNameRow, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpsertOrganizationSettings(ctx context.Context, arg UpsertOrganizationSettingsParams) (OrganizationSetting, error)
+	// Só grava se o produto for da mesma organização da contagem e tiver estoque
+	// próprio (produtos com variações são contados pelas variações).
+	UpsertStockCountItem(ctx context.Context, arg UpsertStockCountItemParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
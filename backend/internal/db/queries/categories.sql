@@ -0,0 +1,43 @@
+-- name: CreateCategory :one
+INSERT INTO categories (organization_id, parent_id, name)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCategory :one
+SELECT * FROM categories
+WHERE id = $1 AND organization_id = $2 LIMIT 1;
+
+-- name: ListCategories :many
+-- product_count conta só os produtos ligados diretamente à categoria.
+SELECT
+    c.*,
+    (SELECT COUNT(*) FROM products p WHERE p.category_id = c.id AND p.parent_id IS NULL)::INT AS product_count
+FROM categories c
+WHERE c.organization_id = $1
+ORDER BY c.name, c.id;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = $3, parent_id = $4, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: LockCategories :exec
+-- Trava as categorias da organização para que duas movimentações simultâneas
+-- não passem ambas pela checagem de ciclo.
+SELECT id FROM categories
+WHERE organization_id = $1
+FOR UPDATE;
+
+-- name: IsCategoryDescendant :one
+-- Indica se candidate está na subárvore de category_id (incluindo ela mesma).
+WITH RECURSIVE subtree AS (
+    SELECT c.id FROM categories c WHERE c.id = sqlc.arg('category_id')
+    UNION ALL
+    SELECT c.id FROM categories c JOIN subtree st ON c.parent_id = st.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree st WHERE st.id = sqlc.arg('candidate')::UUID)::BOOLEAN;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1 AND organization_id = $2;
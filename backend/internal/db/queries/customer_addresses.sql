@@ -0,0 +1,69 @@
+-- name: ListCustomerAddresses :many
+SELECT * FROM customer_addresses
+WHERE customer_id = $1 AND organization_id = $2
+ORDER BY type, is_default DESC, created_at;
+
+-- name: CreateCustomerAddress :one
+-- Só grava se o cliente for da organização e não tiver sido anonimizado.
+INSERT INTO customer_addresses (
+  organization_id, customer_id, type, is_default, zip_code, street, number,
+  complement, district, city, state, ibge_code
+)
+SELECT c.organization_id, c.id, sqlc.arg('type'), sqlc.arg('is_default'), sqlc.arg('zip_code'),
+  sqlc.arg('street'), sqlc.arg('number'), sqlc.arg('complement'), sqlc.arg('district'),
+  sqlc.arg('city'), sqlc.arg('state'), sqlc.arg('ibge_code')
+FROM customers c
+WHERE c.id = sqlc.arg('customer_id') AND c.organization_id = sqlc.arg('organization_id')
+  AND c.anonymized_at IS NULL
+RETURNING *;
+
+-- name: UpdateCustomerAddress :one
+UPDATE customer_addresses
+SET type = $4, is_default = $5, zip_code = $6, street = $7, number = $8,
+    complement = $9, district = $10, city = $11, state = $12, ibge_code = $13,
+    updated_at = NOW()
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3
+RETURNING *;
+
+-- name: DeleteCustomerAddress :execrows
+DELETE FROM customer_addresses
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3;
+
+-- name: ClearDefaultCustomerAddress :exec
+-- Tira a marca de padrão dos outros endereços do mesmo tipo, antes de marcar
+-- keep_id; keep_id nulo limpa todos.
+UPDATE customer_addresses
+SET is_default = FALSE, updated_at = NOW()
+WHERE organization_id = sqlc.arg('organization_id') AND customer_id = sqlc.arg('customer_id')
+  AND type = sqlc.arg('type') AND is_default
+  AND id IS DISTINCT FROM sqlc.narg('keep_id');
+
+-- name: EnsureDefaultCustomerAddresses :exec
+-- Marca como padrão o endereço mais antigo de cada tipo que ficou sem padrão.
+UPDATE customer_addresses a
+SET is_default = TRUE, updated_at = NOW()
+WHERE a.id IN (
+    SELECT DISTINCT ON (f.type) f.id
+    FROM customer_addresses f
+    WHERE f.customer_id = $1 AND f.organization_id = $2
+    ORDER BY f.type, f.created_at, f.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM customer_addresses d
+    WHERE d.customer_id = a.customer_id AND d.type = a.type AND d.is_default
+);
+
+-- name: MoveCustomerAddresses :exec
+-- Usado na junção de clientes: os endereços das origens vão para o destino
+-- sem a marca de padrão.
+UPDATE customer_addresses
+SET customer_id = sqlc.arg('target_id'), is_default = FALSE, updated_at = NOW()
+WHERE organization_id = sqlc.arg('organization_id') AND customer_id = ANY(sqlc.arg('source_ids')::UUID[]);
+
+-- name: GetCustomerAddress :one
+SELECT * FROM customer_addresses
+WHERE id = $1 AND customer_id = $2 AND organization_id = $3;
+
+-- name: DeleteAllCustomerAddresses :exec
+DELETE FROM customer_addresses
+WHERE customer_id = $1 AND organization_id = $2;
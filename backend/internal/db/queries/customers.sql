@@ -5,17 +5,92 @@ INSERT INTO customers (
   $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
--- name: ListCustomers :many
+-- name: GetCustomer :one
 SELECT * FROM customers
-WHERE organization_id = $1
-ORDER BY created_at DESC;
+WHERE id = $1 AND organization_id = $2 LIMIT 1;
 
 -- name: UpdateCustomer :one
+-- Clientes anonimizados não voltam a receber dados pessoais.
 UPDATE customers
 SET name = $3, email = $4, phone = $5, document = $6, type = $7, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND anonymized_at IS NULL
+RETURNING *;
+
+-- name: DeleteCustomer :execrows
+DELETE FROM customers
+WHERE id = $1 AND organization_id = $2;
+
+-- name: ArchiveCustomer :one
+UPDATE customers
+SET is_active = FALSE, archived_at = COALESCE(archived_at, NOW()), updated_at = NOW()
 WHERE id = $1 AND organization_id = $2
 RETURNING *;
 
--- name: DeleteCustomer :exec
+-- name: RestoreCustomer :one
+UPDATE customers
+SET is_active = TRUE, archived_at = NULL, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: AnonymizeCustomer :one
+-- Apaga os dados pessoais e arquiva; o id continua, então os pedidos ficam
+-- ligados ao cliente.
+UPDATE customers
+SET name = sqlc.arg('name'), email = NULL, phone = NULL, document = NULL,
+    is_active = FALSE, archived_at = COALESCE(archived_at, NOW()),
+    anonymized_at = NOW(), updated_at = NOW()
+WHERE id = sqlc.arg('id') AND organization_id = sqlc.arg('organization_id')
+RETURNING *;
+
+-- name: FindCustomersByDocument :many
+-- Clientes cujo documento está na lista, já normalizada. Usado pelo upsert da
+-- importação.
+SELECT * FROM customers
+WHERE organization_id = $1
+  AND document = ANY(sqlc.arg('documents')::TEXT[]);
+
+-- name: GetCustomersForUpdate :many
+-- Trava os clientes envolvidos numa junção.
+SELECT * FROM customers
+WHERE organization_id = $1 AND id = ANY(sqlc.arg('ids')::UUID[])
+ORDER BY created_at, id
+FOR UPDATE;
+
+-- name: ReassignCustomerOrders :execrows
+UPDATE orders
+SET customer_id = sqlc.arg('target_id')
+WHERE organization_id = sqlc.arg('organization_id') AND customer_id = ANY(sqlc.arg('source_ids')::UUID[]);
+
+-- name: DeleteCustomers :exec
 DELETE FROM customers
-WHERE id = $1 AND organization_id = $2;
\ No newline at end of file
+WHERE organization_id = $1 AND id = ANY(sqlc.arg('ids')::UUID[]);
+
+-- name: GetCustomerStats :one
+-- Números de compra do cliente numa passada pelos pedidos dele; só pedidos
+-- concluídos contam como compra.
+SELECT
+    COUNT(*) FILTER (WHERE o.status = 'completed')::BIGINT AS order_count,
+    COUNT(*) FILTER (WHERE o.status = 'canceled')::BIGINT AS canceled_count,
+    COALESCE(SUM(o.total_amount) FILTER (WHERE o.status = 'completed'), 0)::NUMERIC AS lifetime_value,
+    MIN(o.created_at) FILTER (WHERE o.status = 'completed')::TIMESTAMPTZ AS first_purchase_at,
+    MAX(o.created_at) FILTER (WHERE o.status = 'completed')::TIMESTAMPTZ AS last_purchase_at
+FROM orders o
+WHERE o.customer_id = $1 AND o.organization_id = $2;
+
+-- name: ListCustomerTopProducts :many
+-- Produtos que o cliente mais comprou, por valor, em pedidos concluídos.
+SELECT
+    p.id AS product_id,
+    p.name,
+    p.sku,
+    SUM(oi.quantity)::BIGINT AS quantity,
+    SUM(oi.total_price)::NUMERIC AS total,
+    COUNT(DISTINCT o.id)::BIGINT AS order_count,
+    MAX(o.created_at)::TIMESTAMPTZ AS last_purchased_at
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+JOIN products p ON p.id = oi.product_id
+WHERE o.customer_id = $1 AND o.organization_id = $2 AND o.status = 'completed'
+GROUP BY p.id, p.name, p.sku
+ORDER BY total DESC, quantity DESC, p.id
+LIMIT $3;
@@ -1,17 +1,48 @@
 -- name: GetDashboardMetrics :one
 SELECT
-    COALESCE((SELECT SUM(total_amount) FROM orders o WHERE o.organization_id = $1::uuid AND o.status = 'completed'), 0)::FLOAT AS total_revenue,
+    COALESCE((SELECT SUM(total_amount) FROM orders o WHERE o.organization_id = $1::uuid AND o.status = 'completed'), 0)::NUMERIC AS total_revenue,
     (SELECT COUNT(*) FROM orders o2 WHERE o2.organization_id = $1::uuid AND o2.status = 'completed')::INT AS sales_count,
-    (SELECT COUNT(*) FROM customers c WHERE c.organization_id = $1::uuid)::INT AS customers_count,
-    (SELECT COUNT(*) FROM products p WHERE p.organization_id = $1::uuid AND p.stock_quantity < 5)::INT AS low_stock_count;
+    (SELECT COUNT(*) FROM customers c WHERE c.organization_id = $1::uuid AND c.is_active)::INT AS customers_count,
+    (SELECT COUNT(*) FROM products p
+        LEFT JOIN organization_settings s ON s.organization_id = p.organization_id
+        WHERE p.organization_id = $1::uuid AND p.is_active AND NOT p.has_variants
+          AND p.stock_quantity < COALESCE(p.min_stock, s.low_stock_threshold, 5))::INT AS low_stock_count;
 
 -- name: GetSalesOverTime :many
 SELECT
     DATE(created_at)::TEXT AS sale_date,
-    COALESCE(SUM(total_amount), 0)::FLOAT AS total_sales
+    COALESCE(SUM(total_amount), 0)::NUMERIC AS total_sales
 FROM orders
 WHERE organization_id = $1::uuid
   AND status = 'completed'
   AND created_at >= NOW() - INTERVAL '7 days'
 GROUP BY DATE(created_at)
-ORDER BY DATE(created_at) ASC;
\ No newline at end of file
+ORDER BY DATE(created_at) ASC;
+
+-- name: GetSalesByCategory :many
+-- Vendas concluídas dos últimos 30 dias por categoria raiz; itens de produtos
+-- sem categoria aparecem com category_id nulo.
+WITH RECURSIVE tree AS (
+    SELECT c.id, c.id AS root_id, c.name AS root_name
+    FROM categories c
+    WHERE c.organization_id = $1::uuid AND c.parent_id IS NULL
+    UNION ALL
+    SELECT c.id, t.root_id, t.root_name
+    FROM categories c
+    JOIN tree t ON c.parent_id = t.id
+)
+SELECT
+    t.root_id AS category_id,
+    COALESCE(t.root_name, 'Sem categoria')::TEXT AS category_name,
+    COUNT(DISTINCT o.id)::INT AS orders_count,
+    SUM(oi.quantity)::BIGINT AS quantity_sold,
+    SUM(oi.total_price)::NUMERIC AS total_sales
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.id
+JOIN products p ON oi.product_id = p.id
+LEFT JOIN tree t ON t.id = p.category_id
+WHERE o.organization_id = $1::uuid
+  AND o.status = 'completed'
+  AND o.created_at >= NOW() - INTERVAL '30 days'
+GROUP BY t.root_id, t.root_name
+ORDER BY total_sales DESC;
\ No newline at end of file
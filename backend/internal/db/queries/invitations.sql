@@ -0,0 +1,37 @@
+-- name: CreateInvitation :one
+INSERT INTO organization_invitations (
+  organization_id, email, role, token_hash, invited_by, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: RevokePendingInvitationsForEmail :exec
+UPDATE organization_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE organization_id = $1 AND email = $2 AND status = 'pending';
+
+-- name: GetInvitationByTokenHash :one
+SELECT i.*, o.name AS organization_name
+FROM organization_invitations i
+JOIN organizations o ON o.id = i.organization_id
+WHERE i.token_hash = $1 LIMIT 1;
+
+-- name: GetInvitationByTokenHashForUpdate :one
+SELECT * FROM organization_invitations
+WHERE token_hash = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListPendingInvitations :many
+SELECT * FROM organization_invitations
+WHERE organization_id = $1 AND status = 'pending' AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RevokeInvitation :execrows
+UPDATE organization_invitations
+SET status = 'revoked', responded_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'pending';
+
+-- name: SetInvitationStatus :exec
+UPDATE organization_invitations
+SET status = $2, responded_at = NOW()
+WHERE id = $1;
@@ -5,25 +5,12 @@ INSERT INTO orders (
   $1, $2, $3, $4, $5
 ) RETURNING id;
 
--- name: ListOrders :many
-SELECT 
-    o.id, 
-    o.total_amount, 
-    o.status, 
-    o.created_at,
-    o.payment_method,
-    c.name as customer_name
-FROM orders o
-JOIN customers c ON o.customer_id = c.id
-WHERE o.organization_id = $1
-ORDER BY o.created_at DESC;
-
 -- name: CreateOrderItem :one
 INSERT INTO order_items (
-  order_id, product_id, quantity, unit_price, total_price
+  order_id, product_id, quantity, unit_price, total_price, unit_cost
 ) VALUES (
-  $1, $2, $3, $4, $5
-) RETURNING id, order_id, product_id, quantity, unit_price, total_price;
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, order_id, product_id, quantity, unit_price, total_price, unit_cost;
 
 -- name: GetOrderItems :many
 SELECT 
@@ -33,12 +20,30 @@ FROM order_items oi
 JOIN products p ON oi.product_id = p.id
 WHERE oi.order_id = $1;
 
--- name: UpdateProductStock :exec
-UPDATE products
-SET stock_quantity = stock_quantity - $2
-WHERE id = $1 AND stock_quantity >= $2;
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE;
+
+-- name: CancelOrder :one
+UPDATE orders
+SET status = 'canceled', canceled_at = NOW(), canceled_by = $3, cancel_reason = $4
+WHERE id = $1 AND organization_id = $2 AND status <> 'canceled'
+RETURNING *;
+
+-- name: GetOrder :one
+SELECT o.*, c.name AS customer_name
+FROM orders o
+JOIN customers c ON o.customer_id = c.id
+WHERE o.id = $1 AND o.organization_id = $2 LIMIT 1;
 
--- name: AddProductStock :exec
-UPDATE products
-SET stock_quantity = stock_quantity + $2
-WHERE id = $1;
\ No newline at end of file
+-- name: GetProductsForOrder :many
+-- Uma variação só está ativa se o produto pai também estiver.
+SELECT
+    p.id, p.name, p.price, p.average_cost, p.stock_quantity, p.has_variants,
+    (p.is_active AND COALESCE(parent.is_active, true))::BOOLEAN AS is_active
+FROM products p
+LEFT JOIN products parent ON parent.id = p.parent_id
+WHERE p.organization_id = $1 AND p.id = ANY(sqlc.arg('ids')::uuid[])
+ORDER BY p.id
+FOR UPDATE OF p;
@@ -16,4 +16,64 @@ RETURNING *;
 SELECT o.id, o.name, o.slug, om.role
 FROM organizations o
 JOIN organization_members om ON o.id = om.organization_id
-WHERE om.user_id = $1;
\ No newline at end of file
+WHERE om.user_id = $1 AND o.is_active = true
+ORDER BY om.joined_at ASC, o.name ASC;
+
+-- name: GetOrganizationMember :one
+SELECT * FROM organization_members
+WHERE organization_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: LockOrganization :one
+SELECT id FROM organizations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListOrganizationMembers :many
+SELECT om.user_id, u.email, u.full_name, om.role, om.joined_at
+FROM organization_members om
+JOIN users u ON u.id = om.user_id
+WHERE om.organization_id = $1
+ORDER BY om.joined_at ASC;
+
+-- name: CountOrganizationOwners :one
+SELECT COUNT(*) FROM organization_members
+WHERE organization_id = $1 AND role = 'owner';
+
+-- name: UpdateOrganizationMemberRole :one
+UPDATE organization_members
+SET role = $3
+WHERE organization_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: RemoveOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE organization_id = $1 AND user_id = $2;
+
+-- name: GetOrganizationByID :one
+SELECT * FROM organizations
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateOrganization :one
+UPDATE organizations
+SET name = $2, slug = $3, document_number = $4, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetOrganizationSettings :one
+SELECT * FROM organization_settings
+WHERE organization_id = $1 LIMIT 1;
+
+-- name: UpsertOrganizationSettings :one
+INSERT INTO organization_settings (
+  organization_id, currency, timezone, low_stock_threshold, default_payment_method, sku_pattern
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (organization_id) DO UPDATE
+SET currency = EXCLUDED.currency,
+    timezone = EXCLUDED.timezone,
+    low_stock_threshold = EXCLUDED.low_stock_threshold,
+    default_payment_method = EXCLUDED.default_payment_method,
+    sku_pattern = EXCLUDED.sku_pattern,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,36 @@
+-- name: ListProductBarcodes :many
+SELECT * FROM product_barcodes
+WHERE product_id = $1 AND organization_id = $2
+ORDER BY created_at, code;
+
+-- name: AddProductBarcode :one
+-- Não grava em produtos com variações: o código vai na variação.
+INSERT INTO product_barcodes (organization_id, code, product_id)
+SELECT p.organization_id, sqlc.arg('code')::VARCHAR, p.id
+FROM products p
+WHERE p.id = sqlc.arg('product_id') AND p.organization_id = sqlc.arg('organization_id')
+  AND NOT p.has_variants
+RETURNING *;
+
+-- name: DeleteProductBarcode :execrows
+DELETE FROM product_barcodes
+WHERE organization_id = $1 AND product_id = $2 AND code = $3;
+
+-- name: LookupProduct :one
+-- Resolve um código lido no caixa: primeiro como código de barras (chave
+-- primária de product_barcodes), depois como SKU. O código de barras chega
+-- normalizado e o SKU como digitado, já que SKUs costumam ter hífens. Só
+-- devolve o que pode ser vendido: produtos ativos e sem variações.
+SELECT p.* FROM (
+    SELECT b.product_id, 1 AS rank
+    FROM product_barcodes b
+    WHERE b.organization_id = sqlc.arg('organization_id') AND b.code = sqlc.arg('barcode')
+    UNION ALL
+    SELECT s.id, 2
+    FROM products s
+    WHERE s.organization_id = sqlc.arg('organization_id') AND LOWER(s.sku) = LOWER(sqlc.arg('sku'))
+) m
+JOIN products p ON p.id = m.product_id
+WHERE p.is_active AND NOT p.has_variants
+ORDER BY m.rank, p.created_at
+LIMIT 1;
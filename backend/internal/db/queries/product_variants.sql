@@ -0,0 +1,91 @@
+-- name: ListProductOptions :many
+SELECT * FROM product_options
+WHERE product_id = $1
+ORDER BY position;
+
+-- name: DeleteProductOptions :exec
+DELETE FROM product_options
+WHERE product_id = $1;
+
+-- name: CreateProductOption :exec
+INSERT INTO product_options (product_id, position, name, choices)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListProductVariants :many
+SELECT * FROM products
+WHERE parent_id = $1 AND organization_id = $2
+ORDER BY created_at, id;
+
+-- name: CreateProductVariant :one
+-- A variação nasce com o cadastro do pai: preço, custo, descrição, categoria,
+-- tags e níveis de reposição. O estoque começa em zero.
+INSERT INTO products (
+  organization_id, parent_id, name, description, price, sku,
+  min_stock, reorder_quantity, average_cost, last_cost, option_values, category_id, tags
+)
+SELECT
+  p.organization_id, p.id, sqlc.arg('name')::VARCHAR, p.description, p.price, sqlc.narg('sku')::VARCHAR,
+  p.min_stock, p.reorder_quantity, p.average_cost, p.average_cost, sqlc.arg('option_values')::TEXT[],
+  p.category_id, p.tags
+FROM products p
+WHERE p.id = sqlc.arg('parent_id')
+RETURNING *;
+
+-- name: SetProductHasVariants :exec
+UPDATE products
+SET has_variants = true, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetProductVariant :one
+SELECT * FROM products
+WHERE id = $1 AND parent_id = $2 AND organization_id = $3 LIMIT 1;
+
+-- name: UpdateProductVariant :one
+UPDATE products
+SET
+  sku = $4,
+  price = $5,
+  price_overridden = $6,
+  is_active = $7,
+  updated_at = NOW()
+WHERE id = $1 AND parent_id = $2 AND organization_id = $3
+RETURNING *;
+
+-- name: SetVariantActive :exec
+UPDATE products
+SET is_active = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeactivateProductVariants :exec
+UPDATE products
+SET is_active = false, updated_at = NOW()
+WHERE parent_id = $1 AND is_active;
+
+-- name: SyncProductVariants :exec
+-- Propaga nome, descrição, categoria, tags e preço do pai para as variações;
+-- preço só nas que não têm preço próprio.
+UPDATE products v
+SET
+  name = p.name || ' - ' || array_to_string(v.option_values, ' / '),
+  description = p.description,
+  category_id = p.category_id,
+  tags = p.tags,
+  price = CASE WHEN v.price_overridden THEN v.price ELSE p.price END,
+  updated_at = NOW()
+FROM products p
+WHERE v.parent_id = p.id AND p.id = $1;
+
+-- name: ProductHasHistory :one
+-- Diz se o produto já foi vendido, comprado, contado ou movimentado além do
+-- saldo de abertura. Um produto assim não pode virar pai de variações: o
+-- estoque e os pedidos antigos continuam apontando para ele.
+SELECT (
+    EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = $1)
+    OR EXISTS (SELECT 1 FROM purchase_order_items pi WHERE pi.product_id = $1)
+    OR EXISTS (SELECT 1 FROM stock_count_items ci WHERE ci.product_id = $1)
+    OR EXISTS (
+        SELECT 1 FROM stock_movements m
+        WHERE m.product_id = $1
+          AND COALESCE(m.reference_type, '') NOT IN ('product', 'opening_balance')
+    )
+)::BOOLEAN AS has_history;
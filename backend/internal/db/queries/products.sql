@@ -1,31 +1,96 @@
 -- name: CreateProduct :one
 INSERT INTO products (
-  organization_id, name, description, price, stock_quantity, sku
+  organization_id, name, description, price, sku, min_stock, reorder_quantity, average_cost, last_cost,
+  category_id, tags
 ) VALUES (
-  $1, $2, $3, $4, $5, $6
+  $1, $2, $3, $4, $5, $6, $7, $8, $8, $9, $10
 ) RETURNING *;
 
--- name: ListProducts :many
+-- name: GetProduct :one
 SELECT * FROM products
-WHERE organization_id = $1
-ORDER BY created_at DESC;
+WHERE id = $1 AND organization_id = $2 LIMIT 1;
+
+-- name: GetProductForUpdate :one
+SELECT * FROM products
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE;
 
 -- name: GetProductMetrics :one
+-- Estoque baixo considera só produtos ativos, comparando com o min_stock do
+-- produto ou, na falta dele, com o padrão da organização. Variações não entram
+-- no total de produtos; produtos com variações não têm estoque próprio e ficam
+-- fora do estoque baixo.
 SELECT
-  COUNT(*) as total_products,
-  SUM(CASE WHEN stock_quantity < 5 THEN 1 ELSE 0 END)::BIGINT as low_stock_count
-FROM products
-WHERE organization_id = $1;
+  COUNT(*) FILTER (WHERE p.parent_id IS NULL) as total_products,
+  COUNT(*) FILTER (
+    WHERE p.is_active AND NOT p.has_variants
+      AND p.stock_quantity < COALESCE(p.min_stock, s.low_stock_threshold, 5)
+  )::BIGINT as low_stock_count
+FROM products p
+LEFT JOIN organization_settings s ON s.organization_id = p.organization_id
+WHERE p.organization_id = $1;
 
 -- name: UpdateProduct :one
+-- Variações são alteradas por UpdateProductVariant.
 UPDATE products
 SET
   name = $2,
   description = $3,
   price = $4,
-  stock_quantity = $5,
-  sku = $6,
-  is_active = $7,
+  sku = $5,
+  is_active = $6,
+  min_stock = $8,
+  reorder_quantity = $9,
+  category_id = $10,
+  tags = $11,
   updated_at = NOW()
-WHERE id = $1 AND organization_id = $8
-RETURNING *;
\ No newline at end of file
+WHERE id = $1 AND organization_id = $7 AND parent_id IS NULL
+RETURNING *;
+
+-- name: SetProductActive :one
+UPDATE products
+SET is_active = $3, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: DeleteProduct :execrows
+DELETE FROM products
+WHERE id = $1 AND organization_id = $2;
+
+
+-- name: GetProductCostForUpdate :one
+SELECT id, average_cost, stock_quantity FROM products
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateProductCost :exec
+UPDATE products
+SET average_cost = $2, last_cost = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetProductAverageCost :one
+UPDATE products
+SET average_cost = $3, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: NextSKUSequence :one
+-- Reserva o próximo sequencial do gerador de SKU, travando a linha de
+-- configurações até o fim da transação. Sem padrão configurado, não devolve
+-- linha.
+UPDATE organization_settings
+SET sku_next_seq = sku_next_seq + 1
+WHERE organization_id = $1 AND sku_pattern IS NOT NULL
+RETURNING sku_pattern::VARCHAR AS pattern, (sku_next_seq - 1)::BIGINT AS seq;
+
+-- name: SKUExists :one
+SELECT EXISTS (
+  SELECT 1 FROM products
+  WHERE organization_id = $1 AND LOWER(sku) = LOWER(sqlc.arg('sku'))
+)::BOOLEAN;
+
+-- name: FindProductsBySKU :many
+-- Produtos e variações com os SKUs informados (em minúsculas), para o upsert
+-- da importação.
+SELECT * FROM products
+WHERE organization_id = $1 AND LOWER(sku) = ANY(sqlc.arg('skus')::TEXT[]);
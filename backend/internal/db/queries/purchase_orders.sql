@@ -0,0 +1,107 @@
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+  organization_id, supplier_id, total_amount, note, expected_at, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: UpdatePurchaseOrder :one
+UPDATE purchase_orders
+SET supplier_id = $3, total_amount = $4, note = $5, expected_at = $6, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'draft'
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (
+  purchase_order_id, product_id, quantity_ordered, unit_cost
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: DeletePurchaseOrderItems :exec
+DELETE FROM purchase_order_items
+WHERE purchase_order_id = $1;
+
+-- name: GetPurchaseOrder :one
+SELECT po.*, s.name AS supplier_name
+FROM purchase_orders po
+JOIN suppliers s ON po.supplier_id = s.id
+WHERE po.id = $1 AND po.organization_id = $2 LIMIT 1;
+
+-- name: GetPurchaseOrderForUpdate :one
+SELECT * FROM purchase_orders
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE;
+
+-- name: ListPurchaseOrderItems :many
+SELECT i.*, p.name AS product_name, p.sku
+FROM purchase_order_items i
+JOIN products p ON i.product_id = p.id
+WHERE i.purchase_order_id = $1
+ORDER BY p.name, i.id;
+
+-- name: AddPurchaseOrderItemReceived :execrows
+-- Não deixa receber mais do que o pedido.
+UPDATE purchase_order_items
+SET quantity_received = quantity_received + sqlc.arg('quantity')::INTEGER
+WHERE id = sqlc.arg('id') AND purchase_order_id = sqlc.arg('purchase_order_id')
+  AND quantity_received + sqlc.arg('quantity')::INTEGER <= quantity_ordered;
+
+-- name: CountPendingPurchaseOrderItems :one
+SELECT COUNT(*) FROM purchase_order_items
+WHERE purchase_order_id = $1 AND quantity_received < quantity_ordered;
+
+-- name: SendPurchaseOrder :one
+UPDATE purchase_orders
+SET status = 'sent', sent_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'draft'
+RETURNING *;
+
+-- name: CancelPurchaseOrder :one
+UPDATE purchase_orders
+SET status = 'canceled', canceled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status IN ('draft', 'sent')
+RETURNING *;
+
+-- name: SetPurchaseOrderReceiptStatus :exec
+UPDATE purchase_orders
+SET status = sqlc.arg('status'),
+    received_at = CASE WHEN sqlc.arg('status') = 'received' THEN NOW() ELSE received_at END,
+    updated_at = NOW()
+WHERE id = sqlc.arg('id');
+
+-- name: CreatePurchaseReceipt :one
+INSERT INTO purchase_receipts (
+  organization_id, purchase_order_id, note, received_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CreatePurchaseReceiptItem :exec
+INSERT INTO purchase_receipt_items (
+  receipt_id, purchase_order_item_id, product_id, quantity, unit_cost
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ListPurchaseReceiptItems :many
+SELECT
+    r.id AS receipt_id,
+    r.created_at,
+    r.note,
+    r.received_by,
+    ri.purchase_order_item_id,
+    ri.product_id,
+    ri.quantity,
+    ri.unit_cost,
+    p.name AS product_name
+FROM purchase_receipts r
+JOIN purchase_receipt_items ri ON ri.receipt_id = r.id
+JOIN products p ON ri.product_id = p.id
+WHERE r.purchase_order_id = $1
+ORDER BY r.created_at, r.id, p.name;
+
+-- name: GetProductsByIDs :many
+SELECT id, name, is_active, has_variants
+FROM products
+WHERE organization_id = $1 AND id = ANY(sqlc.arg('ids')::uuid[]);
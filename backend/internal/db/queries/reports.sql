@@ -0,0 +1,38 @@
+-- name: GetProductMargins :many
+-- Vendas concluídas no intervalo [from_date, to_date], em datas do fuso da
+-- organização.
+SELECT
+    p.id AS product_id,
+    p.name AS product_name,
+    p.sku,
+    SUM(oi.quantity)::BIGINT AS quantity_sold,
+    SUM(oi.total_price)::NUMERIC AS revenue,
+    SUM(oi.unit_cost * oi.quantity)::NUMERIC AS cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.id
+JOIN products p ON oi.product_id = p.id
+LEFT JOIN organization_settings s ON s.organization_id = o.organization_id
+WHERE o.organization_id = sqlc.arg('organization_id')
+  AND o.status = 'completed'
+  AND (o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE
+      BETWEEN sqlc.arg('from_date')::DATE AND sqlc.arg('to_date')::DATE
+GROUP BY p.id, p.name, p.sku
+ORDER BY SUM(oi.total_price) - SUM(oi.unit_cost * oi.quantity) DESC, p.id
+LIMIT sqlc.arg('max_rows');
+
+-- name: GetMarginByPeriod :many
+-- bucket é day, week ou month (date_trunc).
+SELECT
+    date_trunc(sqlc.arg('bucket')::TEXT, o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE::TEXT AS period,
+    COUNT(DISTINCT o.id)::INT AS orders_count,
+    SUM(oi.total_price)::NUMERIC AS revenue,
+    SUM(oi.unit_cost * oi.quantity)::NUMERIC AS cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.id
+LEFT JOIN organization_settings s ON s.organization_id = o.organization_id
+WHERE o.organization_id = sqlc.arg('organization_id')
+  AND o.status = 'completed'
+  AND (o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE
+      BETWEEN sqlc.arg('from_date')::DATE AND sqlc.arg('to_date')::DATE
+GROUP BY 1
+ORDER BY 1;
@@ -0,0 +1,65 @@
+-- name: CreateStockCount :one
+INSERT INTO stock_counts (organization_id, note, opened_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetStockCount :one
+SELECT * FROM stock_counts
+WHERE id = $1 AND organization_id = $2 LIMIT 1;
+
+-- name: GetStockCountForUpdate :one
+SELECT * FROM stock_counts
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE;
+
+-- name: UpsertStockCountItem :execrows
+-- Só grava se o produto for da mesma organização da contagem e tiver estoque
+-- próprio (produtos com variações são contados pelas variações).
+INSERT INTO stock_count_items (count_id, product_id, counted_quantity, counted_by)
+SELECT sqlc.arg('count_id')::UUID, p.id, sqlc.arg('counted_quantity')::INTEGER, sqlc.narg('counted_by')::UUID
+FROM products p
+WHERE p.id = sqlc.arg('product_id') AND p.organization_id = sqlc.arg('organization_id')
+  AND NOT p.has_variants
+ON CONFLICT (count_id, product_id) DO UPDATE
+SET counted_quantity = EXCLUDED.counted_quantity,
+    counted_by = EXCLUDED.counted_by,
+    counted_at = NOW();
+
+-- name: ListStockCountItems :many
+SELECT
+    i.product_id,
+    i.counted_quantity,
+    i.system_quantity,
+    i.counted_at,
+    p.name AS product_name,
+    p.sku,
+    p.stock_quantity AS current_quantity
+FROM stock_count_items i
+JOIN products p ON i.product_id = p.id
+WHERE i.count_id = $1
+ORDER BY p.name, p.id;
+
+-- name: LockStockCountProducts :many
+SELECT p.id, p.stock_quantity
+FROM products p
+JOIN stock_count_items i ON i.product_id = p.id
+WHERE i.count_id = $1
+ORDER BY p.id
+FOR UPDATE OF p;
+
+-- name: SetStockCountItemSystemQuantity :exec
+UPDATE stock_count_items
+SET system_quantity = $3
+WHERE count_id = $1 AND product_id = $2;
+
+-- name: PostStockCount :one
+UPDATE stock_counts
+SET status = 'posted', posted_by = $3, posted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'open'
+RETURNING *;
+
+-- name: CancelStockCount :one
+UPDATE stock_counts
+SET status = 'canceled', canceled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'open'
+RETURNING *;
@@ -0,0 +1,21 @@
+-- name: RecordStockMovement :one
+-- Atualiza o saldo do produto e grava o movimento numa única instrução. Não
+-- devolve linha quando o produto não existe na organização, quando o saldo
+-- ficaria negativo ou quando o produto tem variações (o estoque fica nelas).
+WITH updated AS (
+  UPDATE products p
+  SET stock_quantity = p.stock_quantity + sqlc.arg('quantity')::INTEGER, updated_at = NOW()
+  WHERE p.id = sqlc.arg('product_id') AND p.organization_id = sqlc.arg('organization_id')
+    AND p.stock_quantity + sqlc.arg('quantity')::INTEGER >= 0
+    AND NOT p.has_variants
+  RETURNING p.id, p.organization_id, p.stock_quantity
+)
+INSERT INTO stock_movements (
+  organization_id, product_id, type, quantity, balance_after, reference_type, reference_id, reason_code, note, created_by
+)
+SELECT
+  u.organization_id, u.id, sqlc.arg('type')::stock_movement_type, sqlc.arg('quantity')::INTEGER, u.stock_quantity,
+  sqlc.narg('reference_type')::VARCHAR, sqlc.narg('reference_id')::UUID,
+  sqlc.narg('reason_code')::VARCHAR, sqlc.narg('note')::TEXT, sqlc.narg('created_by')::UUID
+FROM updated u
+RETURNING *;
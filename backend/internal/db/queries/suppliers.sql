@@ -0,0 +1,20 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (
+  organization_id, name, email, phone, document, type
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetSupplier :one
+SELECT * FROM suppliers
+WHERE id = $1 AND organization_id = $2 LIMIT 1;
+
+-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = $3, email = $4, phone = $5, document = $6, type = $7, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: DeleteSupplier :execrows
+DELETE FROM suppliers
+WHERE id = $1 AND organization_id = $2;
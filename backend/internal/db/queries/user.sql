@@ -12,10 +12,25 @@ SELECT * FROM users
 WHERE id = $1 LIMIT 1;
 
 -- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-VALUES ($1, $2, $3)
+INSERT INTO refresh_tokens (user_id, organization_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id;
 
+-- name: GetRefreshTokenByHash :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens
+SET is_revoked = true, revoked_at = NOW(), replaced_by = $2
+WHERE id = $1;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET is_revoked = true, revoked_at = NOW()
+WHERE family_id = $1 AND is_revoked = false;
+
 -- name: UpdateUserName :one
 UPDATE users SET full_name = $2, updated_at = NOW() WHERE id = $1 RETURNING id, email, full_name, created_at;
 
@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reports.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getMarginByPeriod = `-- name: GetMarginByPeriod :many
+SELECT
+    date_trunc($1::TEXT, o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE::TEXT AS period,
+    COUNT(DISTINCT o.id)::INT AS orders_count,
+    SUM(oi.total_price)::NUMERIC AS revenue,
+    SUM(oi.unit_cost * oi.quantity)::NUMERIC AS cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.id
+LEFT JOIN organization_settings s ON s.organization_id = o.organization_id
+WHERE o.organization_id = $2
+  AND o.status = 'completed'
+  AND (o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE
+      BETWEEN $3::DATE AND $4::DATE
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetMarginByPeriodParams struct {
+	Bucket         string      `json:"bucket"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	FromDate       pgtype.Date `json:"from_date"`
+	ToDate         pgtype.Date `json:"to_date"`
+}
+
+type GetMarginByPeriodRow struct {
+	Period      string      `json:"period"`
+	OrdersCount int32       `json:"orders_count"`
+	Revenue     money.Money `json:"revenue"`
+	Cost        money.Money `json:"cost"`
+}
+
+// bucket é day, week ou month (date_trunc).
+func (q *Queries) GetMarginByPeriod(ctx context.Context, arg GetMarginByPeriodParams) ([]GetMarginByPeriodRow, error) {
+	rows, err := q.db.Query(ctx, getMarginByPeriod,
+		arg.Bucket,
+		arg.OrganizationID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMarginByPeriodRow
+	for rows.Next() {
+		var i GetMarginByPeriodRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.OrdersCount,
+			&i.Revenue,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProductMargins = `-- name: GetProductMargins :many
+SELECT
+    p.id AS product_id,
+    p.name AS product_name,
+    p.sku,
+    SUM(oi.quantity)::BIGINT AS quantity_sold,
+    SUM(oi.total_price)::NUMERIC AS revenue,
+    SUM(oi.unit_cost * oi.quantity)::NUMERIC AS cost
+FROM order_items oi
+JOIN orders o ON oi.order_id = o.id
+JOIN products p ON oi.product_id = p.id
+LEFT JOIN organization_settings s ON s.organization_id = o.organization_id
+WHERE o.organization_id = $1
+  AND o.status = 'completed'
+  AND (o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE
+      BETWEEN $2::DATE AND $3::DATE
+GROUP BY p.id, p.name, p.sku
+ORDER BY SUM(oi.total_price) - SUM(oi.unit_cost * oi.quantity) DESC, p.id
+LIMIT $4
+`
+
+type GetProductMarginsParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	FromDate       pgtype.Date `json:"from_date"`
+	ToDate         pgtype.Date `json:"to_date"`
+	MaxRows        int32       `json:"max_rows"`
+}
+
+type GetProductMarginsRow struct {
+	ProductID    pgtype.UUID `json:"product_id"`
+	ProductName  string      `json:"product_name"`
+	Sku          pgtype.Text `json:"sku"`
+	QuantitySold int64       `json:"quantity_sold"`
+	Revenue      money.Money `json:"revenue"`
+	Cost         money.Money `json:"cost"`
+}
+
+// Vendas concluídas no intervalo [from_date, to_date], em datas do fuso da
+// organização.
+func (q *Queries) GetProductMargins(ctx context.Context, arg GetProductMarginsParams) ([]GetProductMarginsRow, error) {
+	rows, err := q.db.Query(ctx, getProductMargins,
+		arg.OrganizationID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProductMarginsRow
+	for rows.Next() {
+		var i GetProductMarginsRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.ProductName,
+			&i.Sku,
+			&i.QuantitySold,
+			&i.Revenue,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
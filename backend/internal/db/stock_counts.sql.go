@@ -0,0 +1,300 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: stock_counts.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelStockCount = `-- name: CancelStockCount :one
+UPDATE stock_counts
+SET status = 'canceled', canceled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'open'
+RETURNING id, organization_id, status, note, opened_by, posted_by, posted_at, canceled_at, created_at, updated_at
+`
+
+type CancelStockCountParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) CancelStockCount(ctx context.Context, arg CancelStockCountParams) (StockCount, error) {
+	row := q.db.QueryRow(ctx, cancelStockCount, arg.ID, arg.OrganizationID)
+	var i StockCount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Status,
+		&i.Note,
+		&i.OpenedBy,
+		&i.PostedBy,
+		&i.PostedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStockCount = `-- name: CreateStockCount :one
+INSERT INTO stock_counts (organization_id, note, opened_by)
+VALUES ($1, $2, $3)
+RETURNING id, organization_id, status, note, opened_by, posted_by, posted_at, canceled_at, created_at, updated_at
+`
+
+type CreateStockCountParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Note           pgtype.Text `json:"note"`
+	OpenedBy       pgtype.UUID `json:"opened_by"`
+}
+
+func (q *Queries) CreateStockCount(ctx context.Context, arg CreateStockCountParams) (StockCount, error) {
+	row := q.db.QueryRow(ctx, createStockCount, arg.OrganizationID, arg.Note, arg.OpenedBy)
+	var i StockCount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Status,
+		&i.Note,
+		&i.OpenedBy,
+		&i.PostedBy,
+		&i.PostedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockCount = `-- name: GetStockCount :one
+SELECT id, organization_id, status, note, opened_by, posted_by, posted_at, canceled_at, created_at, updated_at FROM stock_counts
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+`
+
+type GetStockCountParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetStockCount(ctx context.Context, arg GetStockCountParams) (StockCount, error) {
+	row := q.db.QueryRow(ctx, getStockCount, arg.ID, arg.OrganizationID)
+	var i StockCount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Status,
+		&i.Note,
+		&i.OpenedBy,
+		&i.PostedBy,
+		&i.PostedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockCountForUpdate = `-- name: GetStockCountForUpdate :one
+SELECT id, organization_id, status, note, opened_by, posted_by, posted_at, canceled_at, created_at, updated_at FROM stock_counts
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+FOR UPDATE
+`
+
+type GetStockCountForUpdateParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetStockCountForUpdate(ctx context.Context, arg GetStockCountForUpdateParams) (StockCount, error) {
+	row := q.db.QueryRow(ctx, getStockCountForUpdate, arg.ID, arg.OrganizationID)
+	var i StockCount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Status,
+		&i.Note,
+		&i.OpenedBy,
+		&i.PostedBy,
+		&i.PostedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listStockCountItems = `-- name: ListStockCountItems :many
+SELECT
+    i.product_id,
+    i.counted_quantity,
+    i.system_quantity,
+    i.counted_at,
+    p.name AS product_name,
+    p.sku,
+    p.stock_quantity AS current_quantity
+FROM stock_count_items i
+JOIN products p ON i.product_id = p.id
+WHERE i.count_id = $1
+ORDER BY p.name, p.id
+`
+
+type ListStockCountItemsRow struct {
+	ProductID       pgtype.UUID        `json:"product_id"`
+	CountedQuantity int32              `json:"counted_quantity"`
+	SystemQuantity  pgtype.Int4        `json:"system_quantity"`
+	CountedAt       pgtype.Timestamptz `json:"counted_at"`
+	ProductName     string             `json:"product_name"`
+	Sku             pgtype.Text        `json:"sku"`
+	CurrentQuantity int32              `json:"current_quantity"`
+}
+
+func (q *Queries) ListStockCountItems(ctx context.Context, countID pgtype.UUID) ([]ListStockCountItemsRow, error) {
+	rows, err := q.db.Query(ctx, listStockCountItems, countID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStockCountItemsRow
+	for rows.Next() {
+		var i ListStockCountItemsRow
+		if err := rows.Scan(
+			&i.ProductID,
+			&i.CountedQuantity,
+			&i.SystemQuantity,
+			&i.CountedAt,
+			&i.ProductName,
+			&i.Sku,
+			&i.CurrentQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockStockCountProducts = `-- name: LockStockCountProducts :many
+SELECT p.id, p.stock_quantity
+FROM products p
+JOIN stock_count_items i ON i.product_id = p.id
+WHERE i.count_id = $1
+ORDER BY p.id
+FOR UPDATE OF p
+`
+
+type LockStockCountProductsRow struct {
+	ID            pgtype.UUID `json:"id"`
+	StockQuantity int32       `json:"stock_quantity"`
+}
+
+func (q *Queries) LockStockCountProducts(ctx context.Context, countID pgtype.UUID) ([]LockStockCountProductsRow, error) {
+	rows, err := q.db.Query(ctx, lockStockCountProducts, countID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockStockCountProductsRow
+	for rows.Next() {
+		var i LockStockCountProductsRow
+		if err := rows.Scan(&i.ID, &i.StockQuantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const postStockCount = `-- name: PostStockCount :one
+UPDATE stock_counts
+SET status = 'posted', posted_by = $3, posted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND organization_id = $2 AND status = 'open'
+RETURNING id, organization_id, status, note, opened_by, posted_by, posted_at, canceled_at, created_at, updated_at
+`
+
+type PostStockCountParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	PostedBy       pgtype.UUID `json:"posted_by"`
+}
+
+func (q *Queries) PostStockCount(ctx context.Context, arg PostStockCountParams) (StockCount, error) {
+	row := q.db.QueryRow(ctx, postStockCount, arg.ID, arg.OrganizationID, arg.PostedBy)
+	var i StockCount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Status,
+		&i.Note,
+		&i.OpenedBy,
+		&i.PostedBy,
+		&i.PostedAt,
+		&i.CanceledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setStockCountItemSystemQuantity = `-- name: SetStockCountItemSystemQuantity :exec
+UPDATE stock_count_items
+SET system_quantity = $3
+WHERE count_id = $1 AND product_id = $2
+`
+
+type SetStockCountItemSystemQuantityParams struct {
+	CountID        pgtype.UUID `json:"count_id"`
+	ProductID      pgtype.UUID `json:"product_id"`
+	SystemQuantity pgtype.Int4 `json:"system_quantity"`
+}
+
+func (q *Queries) SetStockCountItemSystemQuantity(ctx context.Context, arg SetStockCountItemSystemQuantityParams) error {
+	_, err := q.db.Exec(ctx, setStockCountItemSystemQuantity, arg.CountID, arg.ProductID, arg.SystemQuantity)
+	return err
+}
+
+const upsertStockCountItem = `-- name: UpsertStockCountItem :execrows
+INSERT INTO stock_count_items (count_id, product_id, counted_quantity, counted_by)
+SELECT $1::UUID, p.id, $2::INTEGER, $3::UUID
+FROM products p
+WHERE p.id = $4 AND p.organization_id = $5
+  AND NOT p.has_variants
+ON CONFLICT (count_id, product_id) DO UPDATE
+SET counted_quantity = EXCLUDED.counted_quantity,
+    counted_by = EXCLUDED.counted_by,
+    counted_at = NOW()
+`
+
+type UpsertStockCountItemParams struct {
+	CountID         pgtype.UUID `json:"count_id"`
+	CountedQuantity int32       `json:"counted_quantity"`
+	CountedBy       pgtype.UUID `json:"counted_by"`
+	ProductID       pgtype.UUID `json:"product_id"`
+	OrganizationID  pgtype.UUID `json:"organization_id"`
+}
+
+// Só grava se o produto for da mesma organização da contagem e tiver estoque
+// próprio (produtos com variações são contados pelas variações).
+func (q *Queries) UpsertStockCountItem(ctx context.Context, arg UpsertStockCountItemParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertStockCountItem,
+		arg.CountID,
+		arg.CountedQuantity,
+		arg.CountedBy,
+		arg.ProductID,
+		arg.OrganizationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
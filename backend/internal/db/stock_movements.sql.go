@@ -0,0 +1,77 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: stock_movements.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const recordStockMovement = `-- name: RecordStockMovement :one
+WITH updated AS (
+  UPDATE products p
+  SET stock_quantity = p.stock_quantity + $2::INTEGER, updated_at = NOW()
+  WHERE p.id = $8 AND p.organization_id = $9
+    AND p.stock_quantity + $2::INTEGER >= 0
+    AND NOT p.has_variants
+  RETURNING p.id, p.organization_id, p.stock_quantity
+)
+INSERT INTO stock_movements (
+  organization_id, product_id, type, quantity, balance_after, reference_type, reference_id, reason_code, note, created_by
+)
+SELECT
+  u.organization_id, u.id, $1::stock_movement_type, $2::INTEGER, u.stock_quantity,
+  $3::VARCHAR, $4::UUID,
+  $5::VARCHAR, $6::TEXT, $7::UUID
+FROM updated u
+RETURNING id, organization_id, product_id, type, quantity, balance_after, reference_type, reference_id, note, created_by, created_at, reason_code
+`
+
+type RecordStockMovementParams struct {
+	Type           StockMovementType `json:"type"`
+	Quantity       int32             `json:"quantity"`
+	ReferenceType  pgtype.Text       `json:"reference_type"`
+	ReferenceID    pgtype.UUID       `json:"reference_id"`
+	ReasonCode     pgtype.Text       `json:"reason_code"`
+	Note           pgtype.Text       `json:"note"`
+	CreatedBy      pgtype.UUID       `json:"created_by"`
+	ProductID      pgtype.UUID       `json:"product_id"`
+	OrganizationID pgtype.UUID       `json:"organization_id"`
+}
+
+// Atualiza o saldo do produto e grava o movimento numa única instrução. Não
+// devolve linha quando o produto não existe na organização, quando o saldo
+// ficaria negativo ou quando o produto tem variações (o estoque fica nelas).
+func (q *Queries) RecordStockMovement(ctx context.Context, arg RecordStockMovementParams) (StockMovement, error) {
+	row := q.db.QueryRow(ctx, recordStockMovement,
+		arg.Type,
+		arg.Quantity,
+		arg.ReferenceType,
+		arg.ReferenceID,
+		arg.ReasonCode,
+		arg.Note,
+		arg.CreatedBy,
+		arg.ProductID,
+		arg.OrganizationID,
+	)
+	var i StockMovement
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ProductID,
+		&i.Type,
+		&i.Quantity,
+		&i.BalanceAfter,
+		&i.ReferenceType,
+		&i.ReferenceID,
+		&i.Note,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ReasonCode,
+	)
+	return i, err
+}
@@ -0,0 +1,140 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: suppliers.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createSupplier = `-- name: CreateSupplier :one
+INSERT INTO suppliers (
+  organization_id, name, email, phone, document, type
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at
+`
+
+type CreateSupplierParams struct {
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Name           string      `json:"name"`
+	Email          pgtype.Text `json:"email"`
+	Phone          pgtype.Text `json:"phone"`
+	Document       pgtype.Text `json:"document"`
+	Type           pgtype.Text `json:"type"`
+}
+
+func (q *Queries) CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error) {
+	row := q.db.QueryRow(ctx, createSupplier,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Document,
+		arg.Type,
+	)
+	var i Supplier
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteSupplier = `-- name: DeleteSupplier :execrows
+DELETE FROM suppliers
+WHERE id = $1 AND organization_id = $2
+`
+
+type DeleteSupplierParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) DeleteSupplier(ctx context.Context, arg DeleteSupplierParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSupplier, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getSupplier = `-- name: GetSupplier :one
+SELECT id, organization_id, name, email, phone, document, type, created_at, updated_at FROM suppliers
+WHERE id = $1 AND organization_id = $2 LIMIT 1
+`
+
+type GetSupplierParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+}
+
+func (q *Queries) GetSupplier(ctx context.Context, arg GetSupplierParams) (Supplier, error) {
+	row := q.db.QueryRow(ctx, getSupplier, arg.ID, arg.OrganizationID)
+	var i Supplier
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateSupplier = `-- name: UpdateSupplier :one
+UPDATE suppliers
+SET name = $3, email = $4, phone = $5, document = $6, type = $7, updated_at = NOW()
+WHERE id = $1 AND organization_id = $2
+RETURNING id, organization_id, name, email, phone, document, type, created_at, updated_at
+`
+
+type UpdateSupplierParams struct {
+	ID             pgtype.UUID `json:"id"`
+	OrganizationID pgtype.UUID `json:"organization_id"`
+	Name           string      `json:"name"`
+	Email          pgtype.Text `json:"email"`
+	Phone          pgtype.Text `json:"phone"`
+	Document       pgtype.Text `json:"document"`
+	Type           pgtype.Text `json:"type"`
+}
+
+func (q *Queries) UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error) {
+	row := q.db.QueryRow(ctx, updateSupplier,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Email,
+		arg.Phone,
+		arg.Document,
+		arg.Type,
+	)
+	var i Supplier
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Email,
+		&i.Phone,
+		&i.Document,
+		&i.Type,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -12,19 +12,27 @@ import (
 )
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-VALUES ($1, $2, $3)
+INSERT INTO refresh_tokens (user_id, organization_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id
 `
 
 type CreateRefreshTokenParams struct {
-	UserID    pgtype.UUID        `json:"user_id"`
-	TokenHash string             `json:"token_hash"`
-	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UserID         pgtype.UUID        `json:"user_id"`
+	OrganizationID pgtype.UUID        `json:"organization_id"`
+	FamilyID       pgtype.UUID        `json:"family_id"`
+	TokenHash      string             `json:"token_hash"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, createRefreshToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	row := q.db.QueryRow(ctx, createRefreshToken,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.FamilyID,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
 	return id, err
@@ -61,6 +69,30 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 	return i, err
 }
 
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, token_hash, is_revoked, expires_at, created_at, organization_id, family_id, replaced_by, revoked_at FROM refresh_tokens
+WHERE token_hash = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByHash, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.IsRevoked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.FamilyID,
+		&i.ReplacedBy,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT id, email, password_hash, full_name, is_active, created_at, updated_at FROM users
 WHERE email = $1 LIMIT 1
@@ -101,6 +133,33 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 	return i, err
 }
 
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET is_revoked = true, revoked_at = NOW()
+WHERE family_id = $1 AND is_revoked = false
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	return err
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens
+SET is_revoked = true, revoked_at = NOW(), replaced_by = $2
+WHERE id = $1
+`
+
+type RotateRefreshTokenParams struct {
+	ID         pgtype.UUID `json:"id"`
+	ReplacedBy pgtype.UUID `json:"replaced_by"`
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, rotateRefreshToken, arg.ID, arg.ReplacedBy)
+	return err
+}
+
 const updateUserName = `-- name: UpdateUserName :one
 UPDATE users SET full_name = $2, updated_at = NOW() WHERE id = $1 RETURNING id, email, full_name, created_at
 `
@@ -0,0 +1,120 @@
+// Package document valida e formata documentos fiscais brasileiros.
+package document
+
+import "strings"
+
+// Normalize remove pontuação e espaços, mantendo só letras e dígitos em
+// maiúsculas.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Tipos de documento devolvidos por Kind.
+const (
+	CPF  = "cpf"
+	CNPJ = "cnpj"
+)
+
+// Kind diz se o documento é um CPF ou um CNPJ válido; para qualquer outro
+// valor devolve "".
+func Kind(s string) string {
+	switch {
+	case ValidCPF(s):
+		return CPF
+	case ValidCNPJ(s):
+		return CNPJ
+	}
+	return ""
+}
+
+// ValidCPF confere tamanho e dígitos verificadores de um CPF, formatado ou não.
+func ValidCPF(s string) bool {
+	cpf := Normalize(s)
+	if len(cpf) != 11 || !allDigits(cpf) || repeated(cpf) {
+		return false
+	}
+
+	weights := []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}
+	d1 := mod11(cpf[:9], weights[1:])
+	d2 := mod11(cpf[:9]+string(rune('0'+d1)), weights)
+
+	return int(cpf[9]-'0') == d1 && int(cpf[10]-'0') == d2
+}
+
+// ValidCNPJ confere tamanho e dígitos verificadores de um CNPJ, formatado ou
+// não. Aceita também o CNPJ alfanumérico, em que os 12 primeiros caracteres
+// podem ser letras; os dígitos verificadores continuam numéricos e cada letra
+// vale seu código ASCII menos 48 no cálculo.
+func ValidCNPJ(s string) bool {
+	cnpj := Normalize(s)
+	if len(cnpj) != 14 || !allDigits(cnpj[12:]) || repeated(cnpj) {
+		return false
+	}
+
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	d1 := mod11(cnpj[:12], weights[1:])
+	d2 := mod11(cnpj[:12]+string(rune('0'+d1)), weights)
+
+	return int(cnpj[12]-'0') == d1 && int(cnpj[13]-'0') == d2
+}
+
+// Format aplica a máscara de CPF ou CNPJ conforme o tamanho. Outros valores
+// são devolvidos normalizados, sem máscara.
+func Format(s string) string {
+	doc := Normalize(s)
+	if len(doc) == 11 {
+		return FormatCPF(doc)
+	}
+	return FormatCNPJ(doc)
+}
+
+// FormatCPF devolve o CPF no formato 000.000.000-00. Valores que não têm 11
+// caracteres são devolvidos normalizados, sem máscara.
+func FormatCPF(s string) string {
+	cpf := Normalize(s)
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+// FormatCNPJ devolve o CNPJ no formato 00.000.000/0000-00. Valores que não
+// têm 14 caracteres são devolvidos normalizados, sem máscara.
+func FormatCNPJ(s string) string {
+	cnpj := Normalize(s)
+	if len(cnpj) != 14 {
+		return cnpj
+	}
+	return cnpj[:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:]
+}
+
+func mod11(base string, weights []int) int {
+	sum := 0
+	for i, w := range weights {
+		sum += int(base[i]-'0') * w
+	}
+	rest := sum % 11
+	if rest < 2 {
+		return 0
+	}
+	return 11 - rest
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func repeated(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
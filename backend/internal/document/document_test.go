@@ -0,0 +1,83 @@
+package document
+
+import "testing"
+
+func TestValidCNPJ(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"11.222.333/0001-81", true},
+		{"11222333000181", true},
+		{"11.444.777/0001-61", true},
+		{"11.222.333/0001-82", false},
+		{"11.222.333/0001", false},
+		{"00.000.000/0000-00", false},
+		{"12.ABC.345/01DE-35", true},
+		{"12abc34501de35", true},
+		{"12.ABC.345/01DE-36", false},
+		{"12.ABC.345/01DE-3A", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidCNPJ(tt.in); got != tt.want {
+			t.Errorf("ValidCNPJ(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidCPF(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"529.982.247-25", true},
+		{"52998224725", true},
+		{"111.444.777-35", true},
+		{"529.982.247-26", false},
+		{"529.982.247", false},
+		{"111.111.111-11", false},
+		{"5299822472A", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidCPF(tt.in); got != tt.want {
+			t.Errorf("ValidCPF(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := map[string]string{
+		"529.982.247-25":     CPF,
+		"11.222.333/0001-81": CNPJ,
+		"12.ABC.345/01DE-35": CNPJ,
+		"123":                "",
+	}
+	for in, want := range tests {
+		if got := Kind(in); got != want {
+			t.Errorf("Kind(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[string]string{
+		"52998224725":    "529.982.247-25",
+		"12ABC34501DE35": "12.ABC.345/01DE-35",
+		"12-3":           "123",
+	}
+	for in, want := range tests {
+		if got := Format(in); got != want {
+			t.Errorf("Format(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatCNPJ(t *testing.T) {
+	if got := FormatCNPJ("11222333000181"); got != "11.222.333/0001-81" {
+		t.Errorf("FormatCNPJ = %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+// Package export responde downloads gerados em streaming: o corpo é escrito
+// enquanto as linhas saem do banco, sem montar o arquivo em memória.
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// SheetFormat lê ?format= para exportações em planilha; sem ele, CSV.
+func SheetFormat(c *fiber.Ctx) (string, error) {
+	format := c.Query("format", sheet.FormatCSV)
+	if format != sheet.FormatCSV && format != sheet.FormatXLSX {
+		return "", sheet.ErrUnsupportedExport
+	}
+	return format, nil
+}
+
+// Stream define os cabeçalhos do download e agenda write para quando a
+// resposta for enviada. O status já terá saído nesse momento, então um erro
+// no meio do caminho só vai para o log e o arquivo chega truncado.
+func Stream(c *fiber.Ctx, filename, contentType string, write func(ctx context.Context, w io.Writer) error) error {
+	ctx := c.Context()
+	path := c.Path()
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(ctx, w); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("export failed")
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// Sheet exporta uma planilha no formato pedido; fill grava as linhas.
+func Sheet(c *fiber.Ctx, name, format string, fill func(ctx context.Context, w sheet.Writer) error) error {
+	return Stream(c, Filename(name, format), sheet.ContentType(format), func(ctx context.Context, out io.Writer) error {
+		w, err := sheet.NewWriter(format, out, name)
+		if err != nil {
+			return err
+		}
+		if err := fill(ctx, w); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// Filename monta o nome do arquivo com a data, como produtos_2026-10-16.csv.
+func Filename(name, ext string) string {
+	return fmt.Sprintf("%s_%s.%s", name, time.Now().Format("2006-01-02"), ext)
+}
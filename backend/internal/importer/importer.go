@@ -0,0 +1,252 @@
+// Package importer reúne o que as importações de planilha têm em comum:
+// mapeamento de colunas, conversão de valores, validação por linha, gravação
+// atômica ou em lotes e o relatório devolvido ao cliente.
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// ChunkSize é quantas linhas cada transação grava no modo em lotes.
+	ChunkSize = 500
+	// MaxRows limita o tamanho de uma importação. Linhas em branco não viram
+	// registros, mas contam num limite próprio de mesmo tamanho.
+	MaxRows = 50000
+)
+
+type Mode string
+
+const (
+	ModeAtomic  Mode = "atomic"
+	ModeChunked Mode = "chunked"
+)
+
+var (
+	ErrMissingFile    = errors.New("file is required")
+	ErrInvalidMode    = errors.New("mode must be atomic or chunked")
+	ErrInvalidMapping = errors.New("mapping must be a JSON object of field to column")
+	ErrUnknownField   = errors.New("unknown field in mapping")
+	ErrMissingColumn  = errors.New("column not found in header")
+	ErrEmptyFile      = errors.New("file has no header row")
+	ErrTooManyRows    = fmt.Errorf("file exceeds %d rows", MaxRows)
+)
+
+// Options vêm do formulário da requisição. Mapping liga cada campo ao título
+// da coluna na planilha; campos sem mapeamento procuram uma coluna com o
+// próprio nome.
+type Options struct {
+	DryRun  bool
+	Mode    Mode
+	Mapping map[string]string
+}
+
+// Spec descreve os campos aceitos por uma importação.
+type Spec struct {
+	Fields   []string
+	Required []string
+}
+
+// Record é uma linha de dados já indexada pelos campos. Row é o número da
+// linha na planilha, contando o cabeçalho; no CSV, linhas totalmente vazias
+// não entram na contagem.
+type Record struct {
+	Row    int
+	values map[string]string
+}
+
+// Get devolve o valor do campo sem espaços nas pontas.
+func (r Record) Get(field string) string {
+	return strings.TrimSpace(r.values[field])
+}
+
+// Has informa se o campo tem coluna na planilha. Num upsert, campos sem
+// coluna mantêm o valor atual do registro.
+func (r Record) Has(field string) bool {
+	_, ok := r.values[field]
+	return ok
+}
+
+// ReadRequest lê o multipart de uma importação: o arquivo em "file" (CSV ou
+// XLSX) e, opcionais, "mapping" (JSON), "dry_run" e "mode".
+func ReadRequest(c *fiber.Ctx, spec Spec) ([]Record, Options, error) {
+	opts := Options{
+		DryRun: c.FormValue("dry_run") == "true" || c.FormValue("dry_run") == "1",
+		Mode:   Mode(c.FormValue("mode", string(ModeAtomic))),
+	}
+	if opts.Mode != ModeAtomic && opts.Mode != ModeChunked {
+		return nil, opts, ErrInvalidMode
+	}
+	if v := c.FormValue("mapping"); v != "" {
+		if err := json.Unmarshal([]byte(v), &opts.Mapping); err != nil {
+			return nil, opts, ErrInvalidMapping
+		}
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, opts, ErrMissingFile
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, opts, err
+	}
+	defer f.Close()
+
+	r, err := sheet.Open(fh.Filename, f, fh.Size)
+	if err != nil {
+		return nil, opts, err
+	}
+
+	records, err := Read(r, spec, opts.Mapping)
+	return records, opts, err
+}
+
+// Read usa a primeira linha não vazia como cabeçalho e devolve as demais,
+// pulando linhas em branco até o limite de MaxRows. Títulos são comparados sem diferenciar maiúsculas.
+func Read(r sheet.Reader, spec Spec, mapping map[string]string) ([]Record, error) {
+	known := make(map[string]bool, len(spec.Fields))
+	for _, f := range spec.Fields {
+		known[f] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, field)
+		}
+	}
+
+	row, blanks := 0, 0
+	var header []string
+	for header == nil {
+		cells, err := r.Read()
+		if err == io.EOF {
+			return nil, ErrEmptyFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		row++
+		if !blank(cells) {
+			header = cells
+			continue
+		}
+		if blanks++; blanks > MaxRows {
+			return nil, ErrTooManyRows
+		}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if _, dup := columns[h]; !dup && h != "" {
+			columns[h] = i
+		}
+	}
+
+	required := make(map[string]bool, len(spec.Required))
+	for _, f := range spec.Required {
+		required[f] = true
+	}
+
+	index := make(map[string]int, len(spec.Fields))
+	for _, field := range spec.Fields {
+		column, mapped := mapping[field]
+		if !mapped {
+			column = field
+		}
+		i, ok := columns[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			if mapped || required[field] {
+				return nil, fmt.Errorf("%w: %s", ErrMissingColumn, column)
+			}
+			continue
+		}
+		index[field] = i
+	}
+
+	var records []Record
+	for {
+		cells, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row++
+		if blank(cells) {
+			if blanks++; blanks > MaxRows {
+				return nil, ErrTooManyRows
+			}
+			continue
+		}
+		if len(records) == MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		values := make(map[string]string, len(index))
+		for field, i := range index {
+			if i < len(cells) {
+				values[field] = cells[i]
+			} else {
+				values[field] = ""
+			}
+		}
+		records = append(records, Record{Row: row, values: values})
+	}
+}
+
+func blank(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseMoney aceita valores como "1234.56", "1.234,56" e "R$ 12,50". Com
+// vírgula presente, ela é o separador decimal e os pontos são de milhar.
+// Casas além dos centavos, comuns em células numéricas do Excel, são
+// arredondadas.
+func ParseMoney(s string) (money.Money, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "R$"))
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return money.ParseRounded(s)
+}
+
+// ParseInt aceita inteiros, inclusive os que o Excel grava como "10.0".
+func ParseInt(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if whole, frac, ok := strings.Cut(s, "."); ok && strings.Trim(frac, "0") == "" {
+		s = whole
+	}
+	return strconv.Atoi(s)
+}
+
+// SplitList separa listas numa célula por vírgula, ponto e vírgula ou barra
+// vertical, descartando itens vazios.
+func SplitList(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+)
+
+var spec = Spec{
+	Fields:   []string{"name", "price", "sku"},
+	Required: []string{"name"},
+}
+
+func csvReader(t *testing.T, s string) sheet.Reader {
+	t.Helper()
+	r, err := sheet.NewCSVReader(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRead(t *testing.T) {
+	input := "\nNome;Preço;Extra\nCaneta;1,50;x\n;;\nLápis\n"
+	records, err := Read(csvReader(t, input), spec, map[string]string{"name": "nome", "price": "PREÇO"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if r := records[0]; r.Row != 2 || r.Get("name") != "Caneta" || r.Get("price") != "1,50" {
+		t.Errorf("first record = %+v", r)
+	}
+	if r := records[1]; r.Row != 4 || r.Get("name") != "Lápis" || !r.Has("price") || r.Get("price") != "" {
+		t.Errorf("second record = %+v", r)
+	}
+	if records[0].Has("sku") {
+		t.Error("sku has no column and should not be reported as present")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		mapping map[string]string
+		want    error
+	}{
+		{"empty file", "\n\n", nil, ErrEmptyFile},
+		{"required column missing", "price\n1\n", nil, ErrMissingColumn},
+		{"mapped column missing", "name\nx\n", map[string]string{"price": "valor"}, ErrMissingColumn},
+		{"unknown field", "name\nx\n", map[string]string{"color": "cor"}, ErrUnknownField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(csvReader(t, tt.input), spec, tt.mapping); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// blankRows devolve linhas vazias sem fim.
+type blankRows struct{}
+
+func (blankRows) Read() ([]string, error) { return []string{}, nil }
+
+func TestReadTooManyBlankRows(t *testing.T) {
+	if _, err := Read(blankRows{}, spec, nil); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("got %v, want ErrTooManyRows", err)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := map[string]money.Money{
+		"12":          1200,
+		"1234.56":     123456,
+		"1.234,56":    123456,
+		"R$ 12,50":    1250,
+		" 0,005 ":     1,
+		"1.234.567,8": 123456780,
+	}
+	for in, want := range tests {
+		got, err := ParseMoney(in)
+		if err != nil || got != want {
+			t.Errorf("ParseMoney(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseMoney("abc"); err == nil {
+		t.Error("ParseMoney(abc) should fail")
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	for in, want := range map[string]int{"10": 10, "10.0": 10, " -3 ": -3} {
+		if got, err := ParseInt(in); err != nil || got != want {
+			t.Errorf("ParseInt(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseInt("10.5"); err == nil {
+		t.Error("ParseInt(10.5) should fail")
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" a, b;c | ;")
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type request struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"omitempty,email"`
+	}
+
+	res := NewResult(Options{Mode: ModeAtomic}, 2)
+	res.Validate(2, request{Email: "nope"})
+	res.Validate(3, request{Name: "ok"})
+
+	want := []RowError{
+		{Row: 2, Field: "name", Message: "is required"},
+		{Row: 2, Field: "email", Message: "must be a valid email"},
+	}
+	if !reflect.DeepEqual(res.Errors, want) {
+		t.Errorf("errors = %+v, want %+v", res.Errors, want)
+	}
+	if res.Failed != 1 || !res.Failing(2) || res.Failing(3) {
+		t.Errorf("failed = %d, want 1 row (2)", res.Failed)
+	}
+}
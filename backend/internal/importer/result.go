@@ -0,0 +1,232 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+// RowError é um problema numa linha; Field fica vazio quando a falha não é de
+// um campo específico.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result é o relatório da importação. Em dry run, Created e Updated dizem o
+// que aconteceria; no modo atômico com qualquer falha, nada é gravado e ambos
+// ficam em zero.
+type Result struct {
+	DryRun  bool       `json:"dry_run"`
+	Mode    Mode       `json:"mode"`
+	Total   int        `json:"total"`
+	Created int        `json:"created"`
+	Updated int        `json:"updated"`
+	Failed  int        `json:"failed"`
+	Errors  []RowError `json:"errors"`
+
+	failed map[int]bool
+}
+
+func NewResult(opts Options, total int) *Result {
+	return &Result{
+		DryRun: opts.DryRun,
+		Mode:   opts.Mode,
+		Total:  total,
+		Errors: []RowError{},
+		failed: make(map[int]bool),
+	}
+}
+
+// Fail registra um erro na linha; Failed conta linhas, não erros.
+func (r *Result) Fail(row int, field, message string) {
+	r.Errors = append(r.Errors, RowError{Row: row, Field: field, Message: message})
+	if !r.failed[row] {
+		r.failed[row] = true
+		r.Failed++
+	}
+}
+
+// Failing informa se a linha já tem algum erro.
+func (r *Result) Failing(row int) bool {
+	return r.failed[row]
+}
+
+var validate = newValidator()
+
+// newValidator nomeia os campos pela tag json, que é o nome usado nas colunas.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// Validate aplica as regras de validação da requisição de criação e registra
+// cada campo reprovado na linha.
+func (r *Result) Validate(row int, req any) {
+	err := validate.Struct(req)
+	if err == nil {
+		return
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		r.Fail(row, "", err.Error())
+		return
+	}
+	for _, fe := range fieldErrs {
+		r.Fail(row, fe.Field(), message(fe))
+	}
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	}
+	return "failed " + fe.Tag() + " " + fe.Param()
+}
+
+// Job é uma linha validada, pronta para gravar. Existing indica que o upsert
+// atualiza um registro que já existe.
+type Job[T any] struct {
+	Row      int
+	Existing bool
+	Data     T
+}
+
+// WriteFunc grava uma linha com as queries da transação recebida.
+type WriteFunc[T any] func(ctx context.Context, q *db.Queries, job Job[T]) error
+
+// Commit grava as linhas validadas. Em dry run só conta o que seria criado ou
+// atualizado. No modo atômico tudo entra numa transação: se alguma linha
+// falhou na validação nada é gravado, e a primeira falha na gravação desfaz a
+// importação inteira. No modo em lotes cada ChunkSize linhas formam uma
+// transação e cada linha ganha um savepoint, então uma falha descarta só a
+// própria linha. public diz quais erros podem ser mostrados ao cliente; os
+// demais vão para o log.
+func Commit[T any](ctx context.Context, pool *pgxpool.Pool, res *Result, jobs []Job[T], write WriteFunc[T], public func(error) bool) error {
+	if res.DryRun {
+		for _, job := range jobs {
+			res.count(job.Existing)
+		}
+		return nil
+	}
+	if res.Mode == ModeAtomic {
+		if res.Failed > 0 {
+			return nil
+		}
+		return commitAtomic(ctx, pool, res, jobs, write, public)
+	}
+
+	for start := 0; start < len(jobs); start += ChunkSize {
+		end := min(start+ChunkSize, len(jobs))
+		if err := commitChunk(ctx, pool, res, jobs[start:end], write, public); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commitAtomic[T any](ctx context.Context, pool *pgxpool.Pool, res *Result, jobs []Job[T], write WriteFunc[T], public func(error) bool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	q := db.New(tx)
+	for _, job := range jobs {
+		if err := write(ctx, q, job); err != nil {
+			res.Fail(job.Row, "", describe(err, public))
+			return nil
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		res.count(job.Existing)
+	}
+	return nil
+}
+
+func commitChunk[T any](ctx context.Context, pool *pgxpool.Pool, res *Result, jobs []Job[T], write WriteFunc[T], public func(error) bool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var created, updated int
+	for _, job := range jobs {
+		if err := writeSavepoint(ctx, tx, job, write); err != nil {
+			res.Fail(job.Row, "", describe(err, public))
+			continue
+		}
+		if job.Existing {
+			updated++
+		} else {
+			created++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	res.Created += created
+	res.Updated += updated
+	return nil
+}
+
+func writeSavepoint[T any](ctx context.Context, tx pgx.Tx, job Job[T], write WriteFunc[T]) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sp.Rollback(ctx)
+
+	if err := write(ctx, db.New(sp), job); err != nil {
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+func (r *Result) count(existing bool) {
+	if existing {
+		r.Updated++
+	} else {
+		r.Created++
+	}
+}
+
+func describe(err error, public func(error) bool) string {
+	if public(err) {
+		return err.Error()
+	}
+	log.Error().Err(err).Msg("import row failed")
+	return "could not save row"
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/gofiber/fiber/v2"
+)
+
+// roleRank ordena os papéis de user_role do menor para o maior privilégio.
+// Um papel ausente ou desconhecido tem rank zero e não passa em nenhuma checagem.
+var roleRank = map[db.UserRole]int{
+	db.UserRoleViewer: 1,
+	db.UserRoleEditor: 2,
+	db.UserRoleAdmin:  3,
+	db.UserRoleOwner:  4,
+}
+
+// HasRole informa se role tem pelo menos o privilégio de min.
+func HasRole(role string, min db.UserRole) bool {
+	rank, ok := roleRank[db.UserRole(role)]
+	return ok && rank >= roleRank[min]
+}
+
+// RequireRole bloqueia com 403 quem não tiver pelo menos o papel min na
+// organização do token. Deve rodar depois de ExtractOrgClaims.
+func RequireRole(min db.UserRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := GetClaims(c)
+		if claims == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "missing claims"})
+		}
+
+		if !HasRole(claims.Role, min) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":         "insufficient permissions",
+				"required_role": string(min),
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		required db.UserRole
+		want     int
+	}{
+		{"owner reads", "owner", db.UserRoleViewer, fiber.StatusOK},
+		{"owner writes", "owner", db.UserRoleEditor, fiber.StatusOK},
+		{"owner manages", "owner", db.UserRoleAdmin, fiber.StatusOK},
+		{"owner owns", "owner", db.UserRoleOwner, fiber.StatusOK},
+		{"admin reads", "admin", db.UserRoleViewer, fiber.StatusOK},
+		{"admin writes", "admin", db.UserRoleEditor, fiber.StatusOK},
+		{"admin manages", "admin", db.UserRoleAdmin, fiber.StatusOK},
+		{"admin cannot own", "admin", db.UserRoleOwner, fiber.StatusForbidden},
+		{"editor reads", "editor", db.UserRoleViewer, fiber.StatusOK},
+		{"editor writes", "editor", db.UserRoleEditor, fiber.StatusOK},
+		{"editor cannot manage", "editor", db.UserRoleAdmin, fiber.StatusForbidden},
+		{"editor cannot own", "editor", db.UserRoleOwner, fiber.StatusForbidden},
+		{"viewer reads", "viewer", db.UserRoleViewer, fiber.StatusOK},
+		{"viewer cannot write", "viewer", db.UserRoleEditor, fiber.StatusForbidden},
+		{"viewer cannot manage", "viewer", db.UserRoleAdmin, fiber.StatusForbidden},
+		{"viewer cannot own", "viewer", db.UserRoleOwner, fiber.StatusForbidden},
+		{"empty role", "", db.UserRoleViewer, fiber.StatusForbidden},
+		{"unknown role", "superuser", db.UserRoleViewer, fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/",
+				func(c *fiber.Ctx) error {
+					c.Locals("claims", &OrgClaims{UserID: uuid.New(), OrgID: uuid.New(), Role: tt.role})
+					return c.Next()
+				},
+				RequireRole(tt.required),
+				func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+			)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("role %q requiring %q: got status %d, want %d", tt.role, tt.required, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireRoleWithoutClaims(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", RequireRole(db.UserRoleViewer), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+	}
+}
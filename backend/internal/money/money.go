@@ -0,0 +1,187 @@
+// Package money representa valores monetários como um número inteiro de
+// centavos, evitando os erros de arredondamento de float64.
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Money é um valor em centavos. Serializa em JSON como string decimal
+// ("12.34") e é lido e gravado diretamente em colunas NUMERIC pelo pgx.
+type Money int64
+
+var ErrInvalid = errors.New("invalid monetary value")
+
+var hundred = big.NewInt(100)
+
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul multiplica por uma quantidade inteira; o resultado é exato.
+func (m Money) Mul(qty int64) Money {
+	return m * Money(qty)
+}
+
+// MulRatio devolve m * num / den arredondado para o centavo mais próximo,
+// com empates afastando do zero.
+func (m Money) MulRatio(num, den int64) Money {
+	if den == 0 {
+		return 0
+	}
+	r := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	return Money(roundDiv(r, big.NewInt(den)).Int64())
+}
+
+// WeightedAverage devolve o custo médio ponderado de dois lotes, (a*qa + b*qb)
+// / (qa + qb), arredondado como em MulRatio. Um lote sem saldo (qa <= 0) não
+// pesa: o resultado é b.
+func WeightedAverage(a Money, qa int64, b Money, qb int64) Money {
+	if qa <= 0 {
+		return b
+	}
+	if qb <= 0 {
+		return a
+	}
+	return a.Mul(qa).Add(b.Mul(qb)).MulRatio(1, qa+qb)
+}
+
+func (m Money) String() string {
+	cents := int64(m)
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+var (
+	centsDecimal = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,2})?$`)
+	plainDecimal = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// Parse lê um decimal simples como "12", "12.5" ou "-0.34". Frações,
+// expoentes e mais de duas casas decimais são recusados com ErrInvalid, em
+// vez de arredondados.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !centsDecimal.MatchString(s) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return parseDecimal(s)
+}
+
+// ParseRounded aceita decimais simples com qualquer número de casas e
+// arredonda para o centavo, com empates afastando do zero. Serve para
+// planilhas, em que o Excel grava valores como "12.300000000000001".
+func ParseRounded(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !plainDecimal.MatchString(s) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return parseDecimal(s)
+}
+
+func parseDecimal(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+
+	n := new(big.Int).Mul(r.Num(), hundred)
+	cents := roundDiv(n, r.Denom())
+	if !cents.IsInt64() {
+		return 0, fmt.Errorf("%w: %q out of range", ErrInvalid, s)
+	}
+	return Money(cents.Int64()), nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON aceita tanto número quanto string, sem passar por float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalid, data)
+		}
+		data = []byte(s)
+	}
+
+	v, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// ScanNumeric implementa pgtype.NumericScanner. NULL vira zero.
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		*m = 0
+		return nil
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("%w: non-finite numeric", ErrInvalid)
+	}
+
+	n := new(big.Int).Set(v.Int)
+	exp := v.Exp + 2
+	if exp >= 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	} else {
+		n = roundDiv(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+	}
+
+	if !n.IsInt64() {
+		return fmt.Errorf("%w: numeric out of range", ErrInvalid)
+	}
+	*m = Money(n.Int64())
+	return nil
+}
+
+// NumericValue implementa pgtype.NumericValuer.
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(m)), Exp: -2, Valid: true}, nil
+}
+
+// roundDiv divide n por d (d > 0) arredondando o empate para longe do zero.
+func roundDiv(n, d *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(d) >= 0 {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,167 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"0", 0},
+		{"12", 1200},
+		{"12.3", 1230},
+		{"12.34", 1234},
+		{"0.1", 10},
+		{" 7.50 ", 750},
+		{"-0.05", -5},
+		{"-1.25", -125},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"", "abc", "1.2.3", "1/3", "1e6", "1e1000000", "10.005", "0.001",
+		"+1", ".5", "5.", "1,50", "99999999999999999999",
+	} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) err = %v, want ErrInvalid", in, err)
+		}
+	}
+}
+
+func TestParseRounded(t *testing.T) {
+	tests := map[string]Money{
+		"10.005":             1001,
+		"10.004":             1000,
+		"-0.005":             -1,
+		"12.300000000000001": 1230,
+	}
+	for in, want := range tests {
+		if got, err := ParseRounded(in); err != nil || got != want {
+			t.Errorf("ParseRounded(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"1/3", "1e6"} {
+		if _, err := ParseRounded(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParseRounded(%q) err = %v, want ErrInvalid", in, err)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Money]string{
+		0:     "0.00",
+		5:     "0.05",
+		1234:  "12.34",
+		-50:   "-0.50",
+		-1234: "-12.34",
+	}
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	// 0.1 * 3 em float64 dá 0.30000000000000004; em centavos é exato.
+	if got := Money(10).Mul(3); got != 30 {
+		t.Errorf("Mul = %d, want 30", got)
+	}
+	if got := Money(1000).MulRatio(1, 3); got != 333 {
+		t.Errorf("MulRatio(1, 3) = %d, want 333", got)
+	}
+	if got := Money(1000).MulRatio(2, 3); got != 667 {
+		t.Errorf("MulRatio(2, 3) = %d, want 667", got)
+	}
+	if got := Money(-1000).MulRatio(2, 3); got != -667 {
+		t.Errorf("MulRatio(2, 3) negative = %d, want -667", got)
+	}
+}
+
+func TestWeightedAverage(t *testing.T) {
+	tests := []struct {
+		a    Money
+		qa   int64
+		b    Money
+		qb   int64
+		want Money
+	}{
+		{1000, 10, 1000, 5, 1000},
+		{1000, 10, 1300, 10, 1150},
+		{1000, 2, 1001, 1, 1000}, // 1000.33 -> 1000
+		{1000, 1, 1001, 1, 1001}, // 1000.5 -> 1001
+		{0, 0, 750, 4, 750},      // sem saldo anterior
+		{500, -3, 750, 4, 750},   // saldo negativo não pesa
+		{500, 3, 750, 0, 500},
+	}
+
+	for _, tt := range tests {
+		got := WeightedAverage(tt.a, tt.qa, tt.b, tt.qb)
+		if got != tt.want {
+			t.Errorf("WeightedAverage(%d, %d, %d, %d) = %d, want %d", tt.a, tt.qa, tt.b, tt.qb, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		A Money `json:"a"`
+		B Money `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a": 19.9, "b": "0.07"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 1990 || v.B != 7 {
+		t.Fatalf("got %d, %d", v.A, v.B)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"a":"19.90","b":"0.07"}` {
+		t.Errorf("Marshal = %s", out)
+	}
+}
+
+func TestNumericRoundTrip(t *testing.T) {
+	m := pgtype.NewMap()
+
+	for _, want := range []Money{0, 1, 1999, -250, 123456789} {
+		buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, want, nil)
+		if err != nil {
+			t.Fatalf("encode %d: %v", want, err)
+		}
+
+		var got Money
+		if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+			t.Fatalf("scan %d: %v", want, err)
+		}
+		if got != want {
+			t.Errorf("round trip: got %d, want %d", got, want)
+		}
+	}
+
+	var got Money
+	if err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("42.5"), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != 4250 {
+		t.Errorf("text scan = %d, want 4250", got)
+	}
+}
@@ -1,17 +1,31 @@
 package orders
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/export"
 	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *validator.Validate
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
 }
 
 func (h *Handler) Create(c *fiber.Ctx) error {
@@ -22,17 +36,56 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
 	}
 
-	if err := h.service.Create(c.Context(), claims.OrgID, req); err != nil {
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	orderID, err := h.service.Create(c.Context(), claims, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "order created", "id": orderID})
+}
+
+func (h *Handler) Cancel(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req CancelOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "order created"})
+	if err := h.service.Cancel(c.Context(), claims.OrgID, claims.UserID, orderID, req); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"message": "order canceled"})
 }
 
 func (h *Handler) List(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
-	orders, err := h.service.List(c.Context(), claims.OrgID)
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	orders, err := h.service.List(c.Context(), claims.OrgID, filter, page)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -40,16 +93,93 @@ func (h *Handler) List(c *fiber.Ctx) error {
 	return c.JSON(orders)
 }
 
+// Export baixa os pedidos com os filtros e a ordenação de List, uma linha por
+// item, em CSV ou XLSX (?format=).
+func (h *Handler) Export(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	format, err := export.SheetFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return export.Sheet(c, "pedidos", format, func(ctx context.Context, w sheet.Writer) error {
+		return h.service.Export(ctx, claims.OrgID, filter, page, w)
+	})
+}
+
+// listFilter lê from e to (YYYY-MM-DD) de GET /orders.
+func listFilter(c *fiber.Ctx) (ListFilter, error) {
+	var filter ListFilter
+	for _, f := range []struct {
+		name string
+		dst  *pgtype.Date
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		v := c.Query(f.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return ListFilter{}, errors.New("invalid " + f.name + " date")
+		}
+		*f.dst = pgtype.Date{Time: t, Valid: true}
+	}
+	return filter, nil
+}
+
 func (h *Handler) GetDetails(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
 	orderID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
 	}
 
-	details, err := h.service.GetDetails(c.Context(), orderID)
+	details, err := h.service.GetDetails(c.Context(), claims.OrgID, orderID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		if errors.Is(err, ErrOrderNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "order not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(details)
-}
\ No newline at end of file
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrOrderNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrPriceOverrideForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrOrderAlreadyCanceled):
+		return fiber.StatusConflict
+	case errors.Is(err, ErrCustomerNotFound), errors.Is(err, ErrCustomerArchived),
+		errors.Is(err, ErrProductNotFound), errors.Is(err, ErrProductInactive),
+		errors.Is(err, ErrProductHasVariants):
+		return fiber.StatusUnprocessableEntity
+	}
+	return fiber.StatusInternalServerError
+}
+
+// errorResponse responde com o status de errorStatus. Erros inesperados vão
+// para o log e o cliente recebe só uma mensagem genérica, sem o texto do banco.
+func errorResponse(c *fiber.Ctx, err error) error {
+	status := errorStatus(err)
+	if status == fiber.StatusInternalServerError {
+		log.Error().Err(err).Str("path", c.Path()).Msg("order request failed")
+		return c.Status(status).JSON(fiber.Map{"error": "internal server error"})
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+}
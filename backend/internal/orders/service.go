@@ -4,39 +4,50 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type CreateOrderItemDTO struct {
-	ProductID uuid.UUID `json:"product_id" validate:"required"`
-	Quantity  int       `json:"quantity" validate:"required,min=1"`
-	UnitPrice float64   `json:"unit_price" validate:"required,min=0"`
+	ProductID     uuid.UUID    `json:"product_id" validate:"required"`
+	Quantity      int          `json:"quantity" validate:"required,min=1"`
+	OverridePrice *money.Money `json:"override_price" validate:"omitempty,min=0"`
 }
 
 type CreateOrderRequest struct {
 	CustomerID    uuid.UUID            `json:"customer_id" validate:"required"`
 	PaymentMethod string               `json:"payment_method" validate:"required"`
-	Items         []CreateOrderItemDTO `json:"items" validate:"required,min=1"`
+	Items         []CreateOrderItemDTO `json:"items" validate:"required,min=1,dive"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
 }
 
 type OrderResponse struct {
-	ID            uuid.UUID `json:"id"`
-	CustomerName  string    `json:"customer_name"`
-	TotalAmount   string    `json:"total_amount"`
-	Status        string    `json:"status"`
-	PaymentMethod string    `json:"payment_method"`
-	CreatedAt     string    `json:"created_at"`
+	ID            uuid.UUID   `json:"id"`
+	CustomerName  string      `json:"customer_name"`
+	TotalAmount   money.Money `json:"total_amount"`
+	Status        string      `json:"status"`
+	PaymentMethod string      `json:"payment_method"`
+	CreatedAt     string      `json:"created_at"`
+	CanceledAt    *time.Time  `json:"canceled_at,omitempty"`
+	CancelReason  string      `json:"cancel_reason,omitempty"`
 }
 
 type OrderItemResponse struct {
-	ProductName string  `json:"product_name"`
-	Quantity    int     `json:"quantity"`
-	UnitPrice   float64 `json:"unit_price"`
-	TotalPrice  float64 `json:"total_price"`
+	ProductName string      `json:"product_name"`
+	Quantity    int         `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"`
+	TotalPrice  money.Money `json:"total_price"`
 }
 
 type OrderDetailsResponse struct {
@@ -44,6 +55,18 @@ type OrderDetailsResponse struct {
 	Items []OrderItemResponse `json:"items"`
 }
 
+var (
+	ErrOrderNotFound          = errors.New("pedido não encontrado")
+	ErrOrderAlreadyCanceled   = errors.New("pedido já está cancelado")
+	ErrCustomerNotFound       = errors.New("cliente não encontrado")
+	ErrCustomerArchived       = errors.New("cliente arquivado")
+	ErrProductNotFound        = errors.New("produto não encontrado")
+	ErrProductInactive        = errors.New("produto inativo")
+	ErrProductHasVariants     = errors.New("produto possui variações; selecione uma variação")
+	ErrInsufficientStock      = errors.New("estoque insuficiente")
+	ErrPriceOverrideForbidden = errors.New("alterar o preço de venda exige papel admin")
+)
+
 type Service struct {
 	db *pgxpool.Pool
 }
@@ -54,114 +77,355 @@ func NewService(pool *pgxpool.Pool) *Service {
 	}
 }
 
-func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CreateOrderRequest) error {
+// Create registra uma venda concluída. Preços vêm do cadastro do produto; o
+// cliente só pode mandar override_price com papel admin ou superior. Cliente e
+// produtos precisam pertencer à organização do token, e cada item gera um
+// movimento de venda no razão de estoque. Produtos com variações não são
+// vendidos diretamente: o item aponta para a variação escolhida.
+func (s *Service) Create(ctx context.Context, claims *middleware.OrgClaims, req CreateOrderRequest) (uuid.UUID, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 	defer tx.Rollback(ctx)
 
 	qtx := db.New(s.db).WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: claims.OrgID, Valid: true}
 
-	var totalAmount float64
+	customer, err := qtx.GetCustomer(ctx, db.GetCustomerParams{
+		ID:             pgtype.UUID{Bytes: req.CustomerID, Valid: true},
+		OrganizationID: pgOrgID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrCustomerNotFound
+		}
+		return uuid.Nil, err
+	}
+	if !customer.IsActive {
+		return uuid.Nil, ErrCustomerArchived
+	}
+
+	ids := make([]pgtype.UUID, 0, len(req.Items))
 	for _, item := range req.Items {
-		totalAmount += item.UnitPrice * float64(item.Quantity)
+		ids = append(ids, pgtype.UUID{Bytes: item.ProductID, Valid: true})
+	}
+
+	// Trava as linhas dos produtos (em ordem de id) até o fim da transação
+	rows, err := qtx.GetProductsForOrder(ctx, db.GetProductsForOrderParams{
+		OrganizationID: pgOrgID,
+		Ids:            ids,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	catalog := make(map[uuid.UUID]db.GetProductsForOrderRow, len(rows))
+	for _, r := range rows {
+		catalog[uuid.UUID(r.ID.Bytes)] = r
+	}
+
+	type line struct {
+		item      CreateOrderItemDTO
+		product   db.GetProductsForOrderRow
+		unitPrice money.Money
+		total     money.Money
 	}
 
-	totalNumeric := pgtype.Numeric{}
-	totalNumeric.Scan(fmt.Sprintf("%.2f", totalAmount))
+	// Cada linha é preço unitário (em centavos) vezes a quantidade, sem
+	// arredondamento; o total do pedido é a soma exata das linhas.
+	lines := make([]line, 0, len(req.Items))
+	var totalAmount money.Money
+	for _, item := range req.Items {
+		product, ok := catalog[item.ProductID]
+		if !ok {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if product.HasVariants {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrProductHasVariants, product.Name)
+		}
+		if !product.IsActive {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrProductInactive, product.Name)
+		}
+
+		unitPrice := product.Price
+		if item.OverridePrice != nil {
+			if !middleware.HasRole(claims.Role, db.UserRoleAdmin) {
+				return uuid.Nil, ErrPriceOverrideForbidden
+			}
+			unitPrice = *item.OverridePrice
+		}
+
+		lineTotal := unitPrice.Mul(int64(item.Quantity))
+		lines = append(lines, line{item: item, product: product, unitPrice: unitPrice, total: lineTotal})
+		totalAmount = totalAmount.Add(lineTotal)
+	}
 
 	orderID, err := qtx.CreateOrder(ctx, db.CreateOrderParams{
-		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		OrganizationID: pgOrgID,
 		CustomerID:     pgtype.UUID{Bytes: req.CustomerID, Valid: true},
-		TotalAmount:    totalNumeric,
+		TotalAmount:    totalAmount,
 		Status:         "completed",
 		PaymentMethod:  req.PaymentMethod,
 	})
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
-	for _, item := range req.Items {
-		err := qtx.UpdateProductStock(ctx, db.UpdateProductStockParams{
-			ID:            pgtype.UUID{Bytes: item.ProductID, Valid: true},
-			StockQuantity: int32(item.Quantity),
+	for _, l := range lines {
+		// Sem linha de volta: o saldo ficaria negativo
+		_, err := qtx.RecordStockMovement(ctx, db.RecordStockMovementParams{
+			Type:           db.StockMovementTypeSale,
+			Quantity:       -int32(l.item.Quantity),
+			ReferenceType:  pgtype.Text{String: "order", Valid: true},
+			ReferenceID:    orderID,
+			CreatedBy:      pgtype.UUID{Bytes: claims.UserID, Valid: true},
+			ProductID:      l.product.ID,
+			OrganizationID: pgOrgID,
 		})
 		if err != nil {
-			return errors.New("estoque insuficiente ou produto não encontrado")
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, fmt.Errorf("%w: %s", ErrInsufficientStock, l.product.Name)
+			}
+			return uuid.Nil, err
 		}
 
-		itemTotal := item.UnitPrice * float64(item.Quantity)
-		itemTotalNumeric := pgtype.Numeric{}
-		itemTotalNumeric.Scan(fmt.Sprintf("%.2f", itemTotal))
-
-		unitPriceNumeric := pgtype.Numeric{}
-		unitPriceNumeric.Scan(fmt.Sprintf("%.2f", item.UnitPrice))
-
+		// O custo médio do momento fica gravado no item para o cálculo de margem
 		_, err = qtx.CreateOrderItem(ctx, db.CreateOrderItemParams{
 			OrderID:    orderID,
-			ProductID:  pgtype.UUID{Bytes: item.ProductID, Valid: true},
-			Quantity:   int32(item.Quantity),
-			UnitPrice:  unitPriceNumeric,
-			TotalPrice: itemTotalNumeric,
+			ProductID:  l.product.ID,
+			Quantity:   int32(l.item.Quantity),
+			UnitPrice:  l.unitPrice,
+			TotalPrice: l.total,
+			UnitCost:   l.product.AverageCost,
 		})
 		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.UUID(orderID.Bytes), nil
+}
+
+// Cancel cancela o pedido e devolve ao estoque a quantidade de cada item,
+// tudo na mesma transação. O pedido fica travado durante a operação para que
+// dois cancelamentos simultâneos não devolvam o estoque duas vezes.
+func (s *Service) Cancel(ctx context.Context, orgID, userID, orderID uuid.UUID, req CancelOrderRequest) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := db.New(s.db).WithTx(tx)
+	pgOrderID := pgtype.UUID{Bytes: orderID, Valid: true}
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	order, err := qtx.GetOrderForUpdate(ctx, db.GetOrderForUpdateParams{
+		ID:             pgOrderID,
+		OrganizationID: pgOrgID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+
+	if order.Status == "canceled" {
+		return ErrOrderAlreadyCanceled
+	}
+
+	items, err := qtx.GetOrderItems(ctx, pgOrderID)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if _, err := qtx.RecordStockMovement(ctx, db.RecordStockMovementParams{
+			Type:           db.StockMovementTypeReturn,
+			Quantity:       item.Quantity,
+			ReferenceType:  pgtype.Text{String: "order_cancellation", Valid: true},
+			ReferenceID:    pgOrderID,
+			Note:           pgtype.Text{String: req.Reason, Valid: true},
+			CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+			ProductID:      item.ProductID,
+			OrganizationID: pgOrgID,
+		}); err != nil {
 			return err
 		}
 	}
 
+	if _, err := qtx.CancelOrder(ctx, db.CancelOrderParams{
+		ID:             pgOrderID,
+		OrganizationID: pgOrgID,
+		CanceledBy:     pgtype.UUID{Bytes: userID, Valid: true},
+		CancelReason:   pgtype.Text{String: req.Reason, Valid: true},
+	}); err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
-func (s *Service) List(ctx context.Context, orgID uuid.UUID) ([]OrderResponse, error) {
-	q := db.New(s.db)
-	rows, err := q.ListOrders(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+// SortFields são as ordenações aceitas em GET /orders.
+var SortFields = []pagination.SortField{
+	{Name: "created_at", Column: "o.created_at", Type: "timestamptz"},
+	{Name: "total_amount", Column: "o.total_amount", Type: "numeric"},
+	{Name: "customer_name", Column: "c.name", Type: "text"},
+	{Name: "status", Column: "o.status", Type: "text"},
+}
+
+// ListFilter restringe GET /orders ao período, em dias no fuso da
+// organização. Datas nulas deixam o período aberto.
+type ListFilter struct {
+	From pgtype.Date
+	To   pgtype.Date
+}
+
+const listFrom = ` FROM orders o
+	JOIN customers c ON o.customer_id = c.id
+	LEFT JOIN organization_settings s ON s.organization_id = o.organization_id`
+
+// List devolve uma página dos pedidos da organização. A busca procura no nome
+// do cliente.
+func (s *Service) List(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params) (pagination.Page[OrderResponse], error) {
+	var b pagination.Builder
+	listWhere(&b, orgID, filter, page.Search)
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*)"+listFrom+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[OrderResponse]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT o.id, o.total_amount, o.status, o.created_at, o.payment_method,
+		o.canceled_at, o.cancel_reason, c.name AS customer_name`+listFrom+page.Keyset(&b, "o.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[OrderResponse]{}, err
+	}
+	list, err := pgx.CollectRows(rows, pgx.RowToStructByName[orderListRow])
 	if err != nil {
-		return nil, err
+		return pagination.Page[OrderResponse]{}, err
 	}
 
-	var orders []OrderResponse
-	for _, r := range rows {
-		val, _ := r.TotalAmount.Float64Value()
-		orders = append(orders, OrderResponse{
+	rowsPage := pagination.NewPage(list, page, total, func(r orderListRow) (string, uuid.UUID) {
+		return orderSortValue(r, page.Sort.Name), uuid.UUID(r.ID.Bytes)
+	})
+
+	orders := make([]OrderResponse, 0, len(rowsPage.Data))
+	for _, r := range rowsPage.Data {
+		order := OrderResponse{
 			ID:            uuid.UUID(r.ID.Bytes),
 			CustomerName:  r.CustomerName,
-			TotalAmount:   fmt.Sprintf("%.2f", val.Float64),
+			TotalAmount:   r.TotalAmount,
 			Status:        r.Status,
 			PaymentMethod: r.PaymentMethod,
 			CreatedAt:     r.CreatedAt.Time.Format("2006-01-02"),
-		})
+			CancelReason:  r.CancelReason.String,
+		}
+		if r.CanceledAt.Valid {
+			order.CanceledAt = &r.CanceledAt.Time
+		}
+		orders = append(orders, order)
 	}
 
-	return orders, nil
+	return pagination.Page[OrderResponse]{
+		Data:       orders,
+		NextCursor: rowsPage.NextCursor,
+		Total:      rowsPage.Total,
+		Limit:      rowsPage.Limit,
+	}, nil
 }
 
-func (s *Service) GetDetails(ctx context.Context, orderID uuid.UUID) (OrderDetailsResponse, error) {
+// listWhere monta os filtros de List, reaproveitados por Export.
+func listWhere(b *pagination.Builder, orgID uuid.UUID, filter ListFilter, search string) {
+	const localDate = "(o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE"
+
+	b.Where("o.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	if filter.From.Valid {
+		b.Where(localDate + " >= " + b.Arg(filter.From) + "::DATE")
+	}
+	if filter.To.Valid {
+		b.Where(localDate + " <= " + b.Arg(filter.To) + "::DATE")
+	}
+	if search != "" {
+		b.Where("c.name ILIKE " + b.Arg(pagination.ContainsPattern(search)))
+	}
+}
+
+// orderListRow é a linha da listagem de pedidos, com o nome do cliente.
+type orderListRow struct {
+	ID            pgtype.UUID
+	TotalAmount   money.Money
+	Status        string
+	CreatedAt     pgtype.Timestamptz
+	PaymentMethod string
+	CanceledAt    pgtype.Timestamptz
+	CancelReason  pgtype.Text
+	CustomerName  string
+}
+
+func orderSortValue(r orderListRow, sort string) string {
+	switch sort {
+	case "total_amount":
+		return r.TotalAmount.String()
+	case "customer_name":
+		return r.CustomerName
+	case "status":
+		return r.Status
+	default:
+		return r.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+}
+
+func (s *Service) GetDetails(ctx context.Context, orgID, orderID uuid.UUID) (OrderDetailsResponse, error) {
 	q := db.New(s.db)
 
-	rows, err := q.GetOrderItems(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
+	order, err := q.GetOrder(ctx, db.GetOrderParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OrderDetailsResponse{}, ErrOrderNotFound
+		}
 		return OrderDetailsResponse{}, err
 	}
 
-	if len(rows) == 0 {
-		return OrderDetailsResponse{}, errors.New("pedido não encontrado ou sem itens")
+	rows, err := q.GetOrderItems(ctx, order.ID)
+	if err != nil {
+		return OrderDetailsResponse{}, err
 	}
 
 	var items []OrderItemResponse
 	for _, r := range rows {
-		unitPrice, _ := r.UnitPrice.Float64Value()
-		totalPrice, _ := r.TotalPrice.Float64Value()
-
 		items = append(items, OrderItemResponse{
 			ProductName: r.ProductName,
 			Quantity:    int(r.Quantity),
-			UnitPrice:   unitPrice.Float64,
-			TotalPrice:  totalPrice.Float64,
+			UnitPrice:   r.UnitPrice,
+			TotalPrice:  r.TotalPrice,
 		})
 	}
 
+	header := OrderResponse{
+		ID:            uuid.UUID(order.ID.Bytes),
+		CustomerName:  order.CustomerName,
+		TotalAmount:   order.TotalAmount,
+		Status:        order.Status,
+		PaymentMethod: order.PaymentMethod,
+		CreatedAt:     order.CreatedAt.Time.Format("2006-01-02"),
+		CancelReason:  order.CancelReason.String,
+	}
+	if order.CanceledAt.Valid {
+		header.CanceledAt = &order.CanceledAt.Time
+	}
+
 	return OrderDetailsResponse{
-		Items: items,
+		OrderResponse: header,
+		Items:         items,
 	}, nil
-}
\ No newline at end of file
+}
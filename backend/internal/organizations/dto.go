@@ -0,0 +1,77 @@
+package organizations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationSettings struct {
+	Currency             string `json:"currency" validate:"required,len=3,uppercase"`
+	Timezone             string `json:"timezone" validate:"required,timezone"`
+	LowStockThreshold    int    `json:"low_stock_threshold" validate:"gte=0"`
+	DefaultPaymentMethod string `json:"default_payment_method" validate:"required,oneof=dinheiro pix credito debito"`
+	// SKUPattern vazio desliga o gerador de SKU (ver pacote sku)
+	SKUPattern string `json:"sku_pattern" validate:"max=50"`
+}
+
+type OrganizationResponse struct {
+	ID             uuid.UUID            `json:"id"`
+	Name           string               `json:"name"`
+	Slug           string               `json:"slug"`
+	DocumentNumber string               `json:"document_number"`
+	IsActive       bool                 `json:"is_active"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	Settings       OrganizationSettings `json:"settings"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name           string               `json:"name" validate:"required,min=2,max=100"`
+	Slug           string               `json:"slug" validate:"required,max=50"`
+	DocumentNumber string               `json:"document_number"`
+	Settings       OrganizationSettings `json:"settings"`
+}
+
+type MemberResponse struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	FullName string    `json:"full_name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin editor viewer"`
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin editor viewer"`
+}
+
+type InvitationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	// Token só é devolvido na criação; depois disso apenas o hash fica salvo.
+	Token string `json:"token,omitempty"`
+}
+
+type InvitationPreviewResponse struct {
+	OrganizationName string    `json:"organization_name"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	UserExists       bool      `json:"user_exists"`
+}
+
+// AcceptInvitationRequest serve tanto para quem já tem conta (basta a senha)
+// quanto para quem ainda não tem (nome e senha criam o usuário).
+type AcceptInvitationRequest struct {
+	Password string `json:"password" validate:"required,min=8"`
+	FullName string `json:"full_name" validate:"omitempty,min=3"`
+}
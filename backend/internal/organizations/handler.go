@@ -0,0 +1,205 @@
+package organizations
+
+import (
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/sku"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+func (h *Handler) GetOrganization(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	org, err := h.service.GetOrganization(c.Context(), claims.OrgID)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(org)
+}
+
+func (h *Handler) UpdateOrganization(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req UpdateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	org, err := h.service.UpdateOrganization(c.Context(), claims.OrgID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(org)
+}
+
+func (h *Handler) ListMembers(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	members, err := h.service.ListMembers(c.Context(), claims.OrgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(members)
+}
+
+func (h *Handler) UpdateMemberRole(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req UpdateMemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	member, err := h.service.UpdateMemberRole(c.Context(), claims, userID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(member)
+}
+
+func (h *Handler) RemoveMember(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.RemoveMember(c.Context(), claims, userID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) CreateInvitation(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	invitation, err := h.service.CreateInvitation(c.Context(), claims, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+func (h *Handler) ListInvitations(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	invitations, err := h.service.ListInvitations(c.Context(), claims.OrgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(invitations)
+}
+
+func (h *Handler) RevokeInvitation(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	invitationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.RevokeInvitation(c.Context(), claims.OrgID, invitationID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) PreviewInvitation(c *fiber.Ctx) error {
+	preview, err := h.service.PreviewInvitation(c.Context(), c.Params("token"))
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(preview)
+}
+
+func (h *Handler) AcceptInvitation(c *fiber.Ctx) error {
+	var req AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.AcceptInvitation(c.Context(), c.Params("token"), req); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "invitation accepted"})
+}
+
+func (h *Handler) DeclineInvitation(c *fiber.Ctx) error {
+	if err := h.service.DeclineInvitation(c.Context(), c.Params("token")); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrMemberNotFound), errors.Is(err, ErrInvitationNotFound), errors.Is(err, ErrOrganizationNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrOwnerRequired), errors.Is(err, ErrInsufficientRole):
+		return fiber.StatusForbidden
+	case errors.Is(err, ErrLastOwner), errors.Is(err, ErrAlreadyMember), errors.Is(err, ErrSlugTaken):
+		return fiber.StatusConflict
+	case errors.Is(err, ErrInvitationInvalid):
+		return fiber.StatusGone
+	case errors.Is(err, ErrInvalidCredentials):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, ErrFullNameRequired), errors.Is(err, ErrInvalidSlug), errors.Is(err, ErrInvalidDocument),
+		errors.Is(err, sku.ErrInvalidPattern), errors.Is(err, sku.ErrTooLong):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,579 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/document"
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/sku"
+	"github.com/dcastro0/aether-backend/internal/token"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+// defaultSettings vale para organizações que ainda não salvaram configurações.
+var defaultSettings = OrganizationSettings{
+	Currency:             "BRL",
+	Timezone:             "America/Sao_Paulo",
+	LowStockThreshold:    5,
+	DefaultPaymentMethod: "dinheiro",
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+var (
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrAlreadyMember        = errors.New("user is already a member of this organization")
+	ErrLastOwner            = errors.New("organization must keep at least one owner")
+	ErrOwnerRequired        = errors.New("only an owner can grant, change or remove the owner role")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationInvalid    = errors.New("invitation is no longer valid")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrFullNameRequired     = errors.New("full_name is required to create a new account")
+	ErrInsufficientRole     = errors.New("insufficient permissions")
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidSlug          = errors.New("slug must contain only lowercase letters, digits and hyphens")
+	ErrSlugTaken            = errors.New("slug is already in use")
+	ErrInvalidDocument      = errors.New("invalid CNPJ")
+)
+
+type Service struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+func (s *Service) GetOrganization(ctx context.Context, orgID uuid.UUID) (OrganizationResponse, error) {
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	org, err := s.q.GetOrganizationByID(ctx, pgOrgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OrganizationResponse{}, ErrOrganizationNotFound
+		}
+		return OrganizationResponse{}, err
+	}
+
+	settings := defaultSettings
+	row, err := s.q.GetOrganizationSettings(ctx, pgOrgID)
+	if err == nil {
+		settings = toSettings(row)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationResponse{}, err
+	}
+
+	return toOrganizationResponse(org, settings), nil
+}
+
+// UpdateOrganization grava o perfil e as configurações juntos. O documento é
+// opcional, mas quando informado precisa ser um CNPJ válido e é salvo só com
+// os dígitos. O padrão de SKU, quando informado, precisa ter {SEQ}.
+func (s *Service) UpdateOrganization(ctx context.Context, orgID uuid.UUID, req UpdateOrganizationRequest) (OrganizationResponse, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return OrganizationResponse{}, ErrInvalidSlug
+	}
+
+	doc := document.Normalize(req.DocumentNumber)
+	if doc != "" && !document.ValidCNPJ(doc) {
+		return OrganizationResponse{}, ErrInvalidDocument
+	}
+
+	skuPattern := strings.TrimSpace(req.Settings.SKUPattern)
+	if skuPattern != "" {
+		if err := sku.ValidatePattern(skuPattern); err != nil {
+			return OrganizationResponse{}, err
+		}
+	}
+
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	existing, err := s.q.GetOrganizationBySlug(ctx, slug)
+	if err == nil && existing.ID != pgOrgID {
+		return OrganizationResponse{}, ErrSlugTaken
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationResponse{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrganizationResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	org, err := qtx.UpdateOrganization(ctx, db.UpdateOrganizationParams{
+		ID:             pgOrgID,
+		Name:           strings.TrimSpace(req.Name),
+		Slug:           slug,
+		DocumentNumber: pgtype.Text{String: doc, Valid: doc != ""},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return OrganizationResponse{}, ErrOrganizationNotFound
+		}
+		if db.IsUniqueViolation(err, "organizations_slug_key") {
+			return OrganizationResponse{}, ErrSlugTaken
+		}
+		return OrganizationResponse{}, err
+	}
+
+	row, err := qtx.UpsertOrganizationSettings(ctx, db.UpsertOrganizationSettingsParams{
+		OrganizationID:       pgOrgID,
+		Currency:             req.Settings.Currency,
+		Timezone:             req.Settings.Timezone,
+		LowStockThreshold:    int32(req.Settings.LowStockThreshold),
+		DefaultPaymentMethod: req.Settings.DefaultPaymentMethod,
+		SkuPattern:           pgtype.Text{String: skuPattern, Valid: skuPattern != ""},
+	})
+	if err != nil {
+		return OrganizationResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrganizationResponse{}, err
+	}
+
+	return toOrganizationResponse(org, toSettings(row)), nil
+}
+
+func (s *Service) ListMembers(ctx context.Context, orgID uuid.UUID) ([]MemberResponse, error) {
+	rows, err := s.q.ListOrganizationMembers(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]MemberResponse, 0, len(rows))
+	for _, r := range rows {
+		members = append(members, MemberResponse{
+			UserID:   uuid.UUID(r.UserID.Bytes),
+			Email:    r.Email,
+			FullName: r.FullName,
+			Role:     string(r.Role),
+			JoinedAt: r.JoinedAt.Time,
+		})
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole muda o papel de um membro. Só owners mexem no papel owner
+// (concedendo ou retirando), e a organização nunca fica sem owner.
+func (s *Service) UpdateMemberRole(ctx context.Context, claims *middleware.OrgClaims, userID uuid.UUID, req UpdateMemberRoleRequest) (MemberResponse, error) {
+	newRole := db.UserRole(req.Role)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return MemberResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: claims.OrgID, Valid: true}
+
+	if _, err := qtx.LockOrganization(ctx, pgOrgID); err != nil {
+		return MemberResponse{}, err
+	}
+
+	member, err := qtx.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{
+		OrganizationID: pgOrgID,
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MemberResponse{}, ErrMemberNotFound
+		}
+		return MemberResponse{}, err
+	}
+
+	if (member.Role == db.UserRoleOwner || newRole == db.UserRoleOwner) && !middleware.HasRole(claims.Role, db.UserRoleOwner) {
+		return MemberResponse{}, ErrOwnerRequired
+	}
+
+	if member.Role == db.UserRoleOwner && newRole != db.UserRoleOwner {
+		if err := ensureAnotherOwner(ctx, qtx, pgOrgID); err != nil {
+			return MemberResponse{}, err
+		}
+	}
+
+	if _, err := qtx.UpdateOrganizationMemberRole(ctx, db.UpdateOrganizationMemberRoleParams{
+		OrganizationID: pgOrgID,
+		UserID:         member.UserID,
+		Role:           newRole,
+	}); err != nil {
+		return MemberResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return MemberResponse{}, err
+	}
+
+	return s.findMember(ctx, claims.OrgID, userID)
+}
+
+// RemoveMember tira um usuário da organização. Admins removem qualquer um
+// abaixo de owner; qualquer membro pode sair por conta própria.
+func (s *Service) RemoveMember(ctx context.Context, claims *middleware.OrgClaims, userID uuid.UUID) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: claims.OrgID, Valid: true}
+
+	if _, err := qtx.LockOrganization(ctx, pgOrgID); err != nil {
+		return err
+	}
+
+	member, err := qtx.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{
+		OrganizationID: pgOrgID,
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+
+	self := userID == claims.UserID
+	if !self && !middleware.HasRole(claims.Role, db.UserRoleAdmin) {
+		return ErrInsufficientRole
+	}
+	if !self && member.Role == db.UserRoleOwner && !middleware.HasRole(claims.Role, db.UserRoleOwner) {
+		return ErrOwnerRequired
+	}
+
+	if member.Role == db.UserRoleOwner {
+		if err := ensureAnotherOwner(ctx, qtx, pgOrgID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := qtx.RemoveOrganizationMember(ctx, db.RemoveOrganizationMemberParams{
+		OrganizationID: pgOrgID,
+		UserID:         member.UserID,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *Service) CreateInvitation(ctx context.Context, claims *middleware.OrgClaims, req CreateInvitationRequest) (InvitationResponse, error) {
+	role := db.UserRole(req.Role)
+	if role == db.UserRoleOwner && !middleware.HasRole(claims.Role, db.UserRoleOwner) {
+		return InvitationResponse{}, ErrOwnerRequired
+	}
+
+	pgOrgID := pgtype.UUID{Bytes: claims.OrgID, Valid: true}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return InvitationResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	user, err := qtx.GetUserByEmail(ctx, req.Email)
+	if err == nil {
+		_, err = qtx.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{
+			OrganizationID: pgOrgID,
+			UserID:         user.ID,
+		})
+		if err == nil {
+			return InvitationResponse{}, ErrAlreadyMember
+		}
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return InvitationResponse{}, err
+	}
+
+	// Um novo convite substitui o pendente anterior para o mesmo e-mail
+	if err := qtx.RevokePendingInvitationsForEmail(ctx, db.RevokePendingInvitationsForEmailParams{
+		OrganizationID: pgOrgID,
+		Email:          req.Email,
+	}); err != nil {
+		return InvitationResponse{}, err
+	}
+
+	inviteToken, err := token.Generate()
+	if err != nil {
+		return InvitationResponse{}, err
+	}
+
+	inv, err := qtx.CreateInvitation(ctx, db.CreateInvitationParams{
+		OrganizationID: pgOrgID,
+		Email:          req.Email,
+		Role:           role,
+		TokenHash:      token.Hash(inviteToken),
+		InvitedBy:      pgtype.UUID{Bytes: claims.UserID, Valid: true},
+		ExpiresAt:      pgtype.Timestamptz{Time: time.Now().Add(invitationTTL), Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("email", req.Email).Msg("failed to create invitation")
+		return InvitationResponse{}, errors.New("could not create invitation")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return InvitationResponse{}, err
+	}
+
+	res := toInvitationResponse(inv)
+	res.Token = inviteToken
+	return res, nil
+}
+
+func (s *Service) ListInvitations(ctx context.Context, orgID uuid.UUID) ([]InvitationResponse, error) {
+	rows, err := s.q.ListPendingInvitations(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]InvitationResponse, 0, len(rows))
+	for _, r := range rows {
+		invitations = append(invitations, toInvitationResponse(r))
+	}
+
+	return invitations, nil
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, orgID, invitationID uuid.UUID) error {
+	n, err := s.q.RevokeInvitation(ctx, db.RevokeInvitationParams{
+		ID:             pgtype.UUID{Bytes: invitationID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (s *Service) PreviewInvitation(ctx context.Context, inviteToken string) (InvitationPreviewResponse, error) {
+	inv, err := s.q.GetInvitationByTokenHash(ctx, token.Hash(inviteToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return InvitationPreviewResponse{}, ErrInvitationNotFound
+		}
+		return InvitationPreviewResponse{}, err
+	}
+
+	if inv.Status != "pending" || time.Now().After(inv.ExpiresAt.Time) {
+		return InvitationPreviewResponse{}, ErrInvitationInvalid
+	}
+
+	_, err = s.q.GetUserByEmail(ctx, inv.Email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return InvitationPreviewResponse{}, err
+	}
+
+	return InvitationPreviewResponse{
+		OrganizationName: inv.OrganizationName,
+		Email:            inv.Email,
+		Role:             string(inv.Role),
+		ExpiresAt:        inv.ExpiresAt.Time,
+		UserExists:       err == nil,
+	}, nil
+}
+
+// AcceptInvitation consome o convite. Se já existe conta com o e-mail
+// convidado, a senha dela confirma a identidade; senão a conta é criada aqui,
+// sem a organização pessoal que o cadastro normal gera.
+func (s *Service) AcceptInvitation(ctx context.Context, inviteToken string, req AcceptInvitationRequest) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	inv, err := lockPendingInvitation(ctx, qtx, inviteToken)
+	if err != nil {
+		return err
+	}
+
+	var userID pgtype.UUID
+	user, err := qtx.GetUserByEmail(ctx, inv.Email)
+	switch {
+	case err == nil:
+		if !user.IsActive {
+			return ErrInvalidCredentials
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+			return ErrInvalidCredentials
+		}
+		userID = user.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		if req.FullName == "" {
+			return ErrFullNameRequired
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		created, err := qtx.CreateUser(ctx, db.CreateUserParams{
+			Email:        inv.Email,
+			PasswordHash: string(hashed),
+			FullName:     req.FullName,
+		})
+		if err != nil {
+			log.Error().Err(err).Str("email", inv.Email).Msg("failed to create invited user")
+			return errors.New("could not create user")
+		}
+		userID = created.ID
+	default:
+		return err
+	}
+
+	_, err = qtx.GetOrganizationMember(ctx, db.GetOrganizationMemberParams{
+		OrganizationID: inv.OrganizationID,
+		UserID:         userID,
+	})
+	if err == nil {
+		return ErrAlreadyMember
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	if _, err := qtx.AddUserToOrganization(ctx, db.AddUserToOrganizationParams{
+		OrganizationID: inv.OrganizationID,
+		UserID:         userID,
+		Role:           inv.Role,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.SetInvitationStatus(ctx, db.SetInvitationStatusParams{
+		ID:     inv.ID,
+		Status: "accepted",
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *Service) DeclineInvitation(ctx context.Context, inviteToken string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	inv, err := lockPendingInvitation(ctx, qtx, inviteToken)
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.SetInvitationStatus(ctx, db.SetInvitationStatusParams{
+		ID:     inv.ID,
+		Status: "declined",
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *Service) findMember(ctx context.Context, orgID, userID uuid.UUID) (MemberResponse, error) {
+	members, err := s.ListMembers(ctx, orgID)
+	if err != nil {
+		return MemberResponse{}, err
+	}
+	for _, m := range members {
+		if m.UserID == userID {
+			return m, nil
+		}
+	}
+	return MemberResponse{}, ErrMemberNotFound
+}
+
+func ensureAnotherOwner(ctx context.Context, q *db.Queries, orgID pgtype.UUID) error {
+	owners, err := q.CountOrganizationOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+func lockPendingInvitation(ctx context.Context, q *db.Queries, inviteToken string) (db.OrganizationInvitation, error) {
+	inv, err := q.GetInvitationByTokenHashForUpdate(ctx, token.Hash(inviteToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.OrganizationInvitation{}, ErrInvitationNotFound
+		}
+		return db.OrganizationInvitation{}, err
+	}
+
+	if inv.Status != "pending" || time.Now().After(inv.ExpiresAt.Time) {
+		return db.OrganizationInvitation{}, ErrInvitationInvalid
+	}
+
+	return inv, nil
+}
+
+func toOrganizationResponse(org db.Organization, settings OrganizationSettings) OrganizationResponse {
+	return OrganizationResponse{
+		ID:             uuid.UUID(org.ID.Bytes),
+		Name:           org.Name,
+		Slug:           org.Slug,
+		DocumentNumber: org.DocumentNumber.String,
+		IsActive:       org.IsActive,
+		CreatedAt:      org.CreatedAt.Time,
+		UpdatedAt:      org.UpdatedAt.Time,
+		Settings:       settings,
+	}
+}
+
+func toSettings(row db.OrganizationSetting) OrganizationSettings {
+	return OrganizationSettings{
+		Currency:             row.Currency,
+		Timezone:             row.Timezone,
+		LowStockThreshold:    int(row.LowStockThreshold),
+		DefaultPaymentMethod: row.DefaultPaymentMethod,
+		SKUPattern:           row.SkuPattern.String,
+	}
+}
+
+func toInvitationResponse(inv db.OrganizationInvitation) InvitationResponse {
+	return InvitationResponse{
+		ID:        uuid.UUID(inv.ID.Bytes),
+		Email:     inv.Email,
+		Role:      string(inv.Role),
+		Status:    inv.Status,
+		ExpiresAt: inv.ExpiresAt.Time,
+		CreatedAt: inv.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,230 @@
+// Package pagination implementa paginação por keyset (cursor), ordenação por
+// campos permitidos e busca textual para as listagens.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidLimit  = errors.New("limit must be between 1 and 200")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidOrder  = errors.New("order must be asc or desc")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// SortField é um campo aceito em ?sort=. Column é a expressão SQL usada na
+// ordenação e no keyset; Type é o tipo para o cast do valor do cursor
+// (timestamptz, numeric, integer, text ou uuid).
+type SortField struct {
+	Name   string
+	Column string
+	Type   string
+}
+
+type Params struct {
+	Limit  int
+	Sort   SortField
+	Desc   bool
+	Search string
+	cursor *cursor
+}
+
+type cursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Page é o envelope devolvido pelas listagens.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+}
+
+// FromQuery lê limit, cursor, sort, order e q da query string. O cursor só
+// vale para a mesma ordenação em que foi gerado.
+func FromQuery(c *fiber.Ctx, fields []SortField, defaultSort string, defaultDesc bool) (Params, error) {
+	p := Params{
+		Limit:  DefaultLimit,
+		Desc:   defaultDesc,
+		Search: strings.TrimSpace(c.Query("q")),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxLimit {
+			return Params{}, ErrInvalidLimit
+		}
+		p.Limit = n
+	}
+
+	sortName := c.Query("sort", defaultSort)
+	found := false
+	for _, f := range fields {
+		if f.Name == sortName {
+			p.Sort, found = f, true
+			break
+		}
+	}
+	if !found {
+		return Params{}, ErrInvalidSort
+	}
+
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+	case "asc":
+		p.Desc = false
+	case "desc":
+		p.Desc = true
+	default:
+		return Params{}, ErrInvalidOrder
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		cur, err := decodeCursor(v)
+		if err != nil || cur.Sort != p.Sort.Name || cur.Desc != p.Desc || !validValue(p.Sort.Type, cur.Value) {
+			return Params{}, ErrInvalidCursor
+		}
+		p.cursor = &cur
+	}
+
+	return p, nil
+}
+
+// Builder acumula condições e argumentos posicionais ($1, $2...) de uma
+// consulta montada em tempo de execução.
+type Builder struct {
+	conds []string
+	args  []any
+}
+
+// Arg registra um argumento e devolve o placeholder correspondente.
+func (b *Builder) Arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *Builder) Where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *Builder) Args() []any {
+	return b.args
+}
+
+// WhereSQL devolve a cláusula WHERE com as condições acumuladas até aqui.
+func (b *Builder) WhereSQL() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+// Keyset adiciona a condição do cursor (se houver) e devolve o ORDER BY e o
+// LIMIT. Busca uma linha a mais para saber se existe próxima página.
+func (p Params) Keyset(b *Builder, idColumn string) string {
+	op := ">"
+	if p.Desc {
+		op = "<"
+	}
+
+	if p.cursor != nil {
+		b.Where(fmt.Sprintf("(%s, %s) %s (%s::%s, %s)",
+			p.Sort.Column, idColumn, op, b.Arg(p.cursor.Value), p.Sort.Type, b.Arg(p.cursor.ID)))
+	}
+
+	return fmt.Sprintf("%s%s LIMIT %d", b.WhereSQL(), p.OrderBy(idColumn), p.Limit+1)
+}
+
+// OrderBy devolve só o ORDER BY da ordenação escolhida, para consultas que
+// percorrem o resultado inteiro (exportações) em vez de uma página.
+func (p Params) OrderBy(idColumn string) string {
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", p.Sort.Column, dir, idColumn, dir)
+}
+
+// NewPage corta a linha extra e gera o próximo cursor a partir da última
+// linha. key devolve o valor do campo de ordenação (em texto) e o id.
+func NewPage[T any](rows []T, p Params, total int64, key func(T) (string, uuid.UUID)) Page[T] {
+	page := Page[T]{Data: rows, Total: total, Limit: p.Limit}
+	if page.Data == nil {
+		page.Data = []T{}
+	}
+
+	if len(rows) > p.Limit {
+		page.Data = rows[:p.Limit]
+		value, id := key(page.Data[p.Limit-1])
+		page.NextCursor = encodeCursor(cursor{Sort: p.Sort.Name, Desc: p.Desc, Value: value, ID: id})
+	}
+
+	return page
+}
+
+// ContainsPattern monta um padrão ILIKE "contém", escapando curingas.
+func ContainsPattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+var numericValue = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// validValue confere se o valor do cursor aceita o cast para o tipo do campo,
+// para que um cursor adulterado vire ErrInvalidCursor e não um erro do banco.
+func validValue(typ, v string) bool {
+	switch typ {
+	case "timestamptz":
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err == nil
+	case "numeric":
+		return numericValue.MatchString(v)
+	case "integer":
+		_, err := strconv.ParseInt(v, 10, 32)
+		return err == nil
+	case "text":
+		return utf8.ValidString(v) && !strings.ContainsRune(v, 0)
+	case "uuid":
+		_, err := uuid.Parse(v)
+		return err == nil
+	}
+	return false
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return cursor{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,161 @@
+package pagination
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var testFields = []SortField{
+	{Name: "created_at", Column: "p.created_at", Type: "timestamptz"},
+	{Name: "name", Column: "p.name", Type: "text"},
+}
+
+func parse(t *testing.T, query string) (Params, error) {
+	t.Helper()
+
+	var (
+		p   Params
+		err error
+	)
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		p, err = FromQuery(c, testFields, "created_at", true)
+		return nil
+	})
+
+	resp, reqErr := app.Test(httptest.NewRequest("GET", "/?"+query, nil))
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return p, err
+}
+
+func TestFromQuery(t *testing.T) {
+	p, err := parse(t, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Limit != DefaultLimit || p.Sort.Name != "created_at" || !p.Desc {
+		t.Errorf("defaults = %+v", p)
+	}
+
+	p, err = parse(t, "limit=10&sort=name&order=asc&q=%20abc%20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Limit != 10 || p.Sort.Name != "name" || p.Desc || p.Search != "abc" {
+		t.Errorf("parsed = %+v", p)
+	}
+
+	for query, want := range map[string]error{
+		"limit=0":       ErrInvalidLimit,
+		"limit=201":     ErrInvalidLimit,
+		"sort=password": ErrInvalidSort,
+		"order=up":      ErrInvalidOrder,
+		"cursor=%%%":    ErrInvalidCursor,
+	} {
+		if _, err := parse(t, query); err != want {
+			t.Errorf("%s: got %v, want %v", query, err, want)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	p, _ := parse(t, "limit=2&sort=name&order=asc")
+
+	ids := map[string]uuid.UUID{"a": uuid.New(), "b": uuid.New(), "c": uuid.New()}
+	page := NewPage([]string{"a", "b", "c"}, p, 3, func(s string) (string, uuid.UUID) {
+		return s, ids[s]
+	})
+
+	if len(page.Data) != 2 || page.NextCursor == "" || page.Total != 3 {
+		t.Fatalf("page = %+v", page)
+	}
+
+	next, err := parse(t, "limit=2&sort=name&order=asc&cursor="+page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b Builder
+	b.Where("p.organization_id = " + b.Arg("org"))
+	got := next.Keyset(&b, "p.id")
+	want := " WHERE p.organization_id = $1 AND (p.name, p.id) > ($2::text, $3) ORDER BY p.name ASC, p.id ASC LIMIT 3"
+	if got != want {
+		t.Errorf("Keyset =\n%q\nwant\n%q", got, want)
+	}
+	if args := b.Args(); len(args) != 3 || args[1] != "b" || args[2] != ids["b"] {
+		t.Errorf("args = %v", args)
+	}
+
+	// Cursor gerado para outra ordenação é rejeitado
+	if _, err := parse(t, "sort=name&order=desc&cursor="+page.NextCursor); err != ErrInvalidCursor {
+		t.Errorf("got %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestCursorValueMustMatchType(t *testing.T) {
+	for _, value := range []string{"yesterday", "2024-13-01T00:00:00Z", ""} {
+		cur := encodeCursor(cursor{Sort: "created_at", Desc: true, Value: value, ID: uuid.New()})
+		if _, err := parse(t, "cursor="+cur); err != ErrInvalidCursor {
+			t.Errorf("%q: got %v, want ErrInvalidCursor", value, err)
+		}
+	}
+
+	cur := encodeCursor(cursor{Sort: "created_at", Desc: true, Value: "2024-05-01T10:00:00.123456Z", ID: uuid.New()})
+	if _, err := parse(t, "cursor="+cur); err != nil {
+		t.Errorf("valid timestamp: %v", err)
+	}
+}
+
+func TestValidValue(t *testing.T) {
+	tests := []struct {
+		typ, value string
+		want       bool
+	}{
+		{"numeric", "-12.50", true},
+		{"numeric", "12", true},
+		{"numeric", "1e3", false},
+		{"numeric", "NaN", false},
+		{"integer", "42", true},
+		{"integer", "4.2", false},
+		{"integer", "99999999999", false},
+		{"text", "Caneta azul", true},
+		{"text", "a\x00b", false},
+		{"uuid", "8f2c6d1e-4b7a-4c3e-9a1d-2f5b6c7d8e9f", true},
+		{"uuid", "not-a-uuid", false},
+		{"date", "2024-05-01", false},
+	}
+
+	for _, tt := range tests {
+		if got := validValue(tt.typ, tt.value); got != tt.want {
+			t.Errorf("validValue(%q, %q) = %v, want %v", tt.typ, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	p, _ := parse(t, "sort=name&order=desc&limit=10")
+	if got, want := p.OrderBy("p.id"), " ORDER BY p.name DESC, p.id DESC"; got != want {
+		t.Errorf("OrderBy = %q, want %q", got, want)
+	}
+}
+
+func TestLastPageHasNoCursor(t *testing.T) {
+	p, _ := parse(t, "limit=5")
+	page := NewPage([]int{1, 2}, p, 2, func(int) (string, uuid.UUID) { return "", uuid.Nil })
+	if page.NextCursor != "" || len(page.Data) != 2 {
+		t.Errorf("page = %+v", page)
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	if got := ContainsPattern(`50%_off\`); got != `%50\%\_off\\%` {
+		t.Errorf("ContainsPattern = %q", got)
+	}
+}
@@ -0,0 +1,222 @@
+// Package pdf gera relatórios em tabela como PDF, uma página por vez, sem
+// manter o documento inteiro em memória. Usa só as fontes padrão (Helvetica),
+// então o texto precisa caber no WinAnsi; o que não couber vira "?".
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Página A4 em pontos e margens.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 36.0
+
+	fontSize   = 8.5
+	lineHeight = 13.0
+)
+
+// Objetos com número fixo; páginas e conteúdos vêm depois.
+const (
+	objCatalog = 1
+	objPages   = 2
+	objFont    = 3
+	objBold    = 4
+	firstFree  = 5
+)
+
+// Column é uma coluna da tabela. Width está em pontos; a soma das larguras
+// deve caber em ContentWidth.
+type Column struct {
+	Title string
+	Width float64
+	Right bool
+}
+
+// ContentWidth é a largura útil da página, entre as margens.
+const ContentWidth = pageWidth - 2*margin
+
+// Report escreve um título, uma tabela que se estende por quantas páginas
+// forem precisas (repetindo o cabeçalho) e linhas de texto ao final.
+type Report struct {
+	w        *countingWriter
+	title    string
+	subtitle string
+	columns  []Column
+
+	offsets map[int]int64
+	next    int
+	pages   []int
+
+	page    bytes.Buffer
+	y       float64
+	inTable bool
+	err     error
+}
+
+// New começa o documento. subtitle pode ficar vazio.
+func New(w io.Writer, title, subtitle string, columns []Column) *Report {
+	r := &Report{
+		w:        &countingWriter{w: bufio.NewWriter(w)},
+		title:    title,
+		subtitle: subtitle,
+		columns:  columns,
+		offsets:  make(map[int]int64),
+		next:     firstFree,
+		inTable:  true,
+	}
+
+	r.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	r.object(objCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", objPages))
+	r.object(objFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	r.object(objBold, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	r.newPage()
+	return r
+}
+
+// Row acrescenta uma linha à tabela, cortando textos que não cabem na coluna.
+func (r *Report) Row(values []string) error {
+	r.ensureSpace()
+	x := margin
+	for i, col := range r.columns {
+		if i < len(values) {
+			r.cell(x, r.y, col, values[i], false)
+		}
+		x += col.Width
+	}
+	r.y -= lineHeight
+	return r.err
+}
+
+// Text escreve uma linha corrida depois da tabela (ex.: totais).
+func (r *Report) Text(line string, bold bool) error {
+	if r.inTable {
+		r.inTable = false
+		r.y -= lineHeight / 2
+	}
+	r.ensureSpace()
+	r.text(margin, r.y, line, bold)
+	r.y -= lineHeight
+	return r.err
+}
+
+// Close fecha a última página e grava a árvore de páginas, a tabela de
+// referências e o trailer.
+func (r *Report) Close() error {
+	r.finishPage()
+
+	kids := make([]string, len(r.pages))
+	for i, id := range r.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	r.object(objPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(r.pages)))
+
+	xref := r.w.n
+	r.printf("xref\n0 %d\n0000000000 65535 f \n", r.next)
+	for id := 1; id < r.next; id++ {
+		r.printf("%010d 00000 n \n", r.offsets[id])
+	}
+	r.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", r.next, objCatalog, xref)
+
+	if r.err != nil {
+		return r.err
+	}
+	return r.w.w.Flush()
+}
+
+func (r *Report) newPage() {
+	r.page.Reset()
+	r.y = pageHeight - margin - 14
+
+	r.text(margin, r.y, r.title, true)
+	fmt.Fprintf(&r.page, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n",
+		pageWidth-margin-textWidth(pageLabel(len(r.pages)+1), 8), margin-14, encode(pageLabel(len(r.pages)+1)))
+	r.y -= lineHeight
+	if r.subtitle != "" {
+		r.text(margin, r.y, r.subtitle, false)
+		r.y -= lineHeight
+	}
+	r.y -= lineHeight / 2
+
+	if r.inTable {
+		x := margin
+		for _, col := range r.columns {
+			r.cell(x, r.y, col, col.Title, true)
+			x += col.Width
+		}
+		fmt.Fprintf(&r.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, r.y-4, pageWidth-margin, r.y-4)
+		r.y -= lineHeight + 2
+	}
+}
+
+func (r *Report) ensureSpace() {
+	if r.y < margin+lineHeight {
+		r.finishPage()
+		r.newPage()
+	}
+}
+
+// finishPage grava o conteúdo da página corrente e o objeto da página.
+func (r *Report) finishPage() {
+	content := r.next
+	page := r.next + 1
+	r.next += 2
+
+	r.object(content, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", r.page.Len(), r.page.String()))
+	r.object(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] "+
+		"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		objPages, pageWidth, pageHeight, objFont, objBold, content))
+	r.pages = append(r.pages, page)
+}
+
+func (r *Report) cell(x, y float64, col Column, value string, bold bool) {
+	value = fit(value, col.Width-4, fontSize)
+	if col.Right {
+		x += col.Width - 4 - textWidth(value, fontSize)
+	}
+	r.textAt(x, y, value, bold)
+}
+
+func (r *Report) text(x, y float64, value string, bold bool) {
+	r.textAt(x, y, fit(value, ContentWidth, fontSize), bold)
+}
+
+func (r *Report) textAt(x, y float64, value string, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&r.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, fontSize, x, y, encode(value))
+}
+
+func (r *Report) object(id int, body string) {
+	r.offsets[id] = r.w.n
+	r.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (r *Report) printf(format string, args ...any) {
+	if r.err != nil {
+		return
+	}
+	_, r.err = fmt.Fprintf(r.w, format, args...)
+}
+
+func pageLabel(n int) string {
+	return fmt.Sprintf("Página %d", n)
+}
+
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(&buf, "Relatório de vendas", "01/10/2026 a 15/10/2026", []Column{
+		{Title: "Cliente", Width: 300},
+		{Title: "Total", Width: 100, Right: true},
+	})
+	for i := range 120 {
+		if err := r.Row([]string{fmt.Sprintf("Cliente (%d)", i), "R$ 10,00"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.Text("Total: R$ 1.200,00", true)
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	// Cada entrada da xref precisa apontar para o início do objeto
+	start, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(out)[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[start:], -1)
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(e[1])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(out[offset:], want) {
+			t.Errorf("xref entry %d points to %q", i+1, out[offset:offset+10])
+		}
+	}
+
+	count := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(out)
+	if n, _ := strconv.Atoi(count[1]); n < 2 {
+		t.Errorf("got %d pages, want the table to span at least 2", n)
+	}
+	if !strings.Contains(out, `(Cliente \(119\))`) {
+		t.Error("parentheses should be escaped")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	got := encode("Ação – 5€ ✓")
+	want := "A\xe7\xe3o \x96 5\x80 ?"
+	if got != want {
+		t.Errorf("encode = %q, want %q", got, want)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("curto", 100, 10); got != "curto" {
+		t.Errorf("fit kept %q", got)
+	}
+	got := fit("um nome de cliente bem comprido", 60, 10)
+	if !strings.HasSuffix(got, ellipsis) || textWidth(got, 10) > 60 {
+		t.Errorf("fit = %q (%.1fpt)", got, textWidth(got, 10))
+	}
+}
@@ -0,0 +1,79 @@
+package pdf
+
+import "strings"
+
+// helveticaWidths são as larguras (em milésimos do corpo) dos caracteres
+// ASCII imprimíveis da Helvetica, do espaço ao til. A versão negrito é um
+// pouco mais larga; a diferença é absorvida pela folga das colunas.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsi mapeia os caracteres fora do Latin-1 que o WinAnsi tem.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+const ellipsis = "…"
+
+func runeWidth(r rune, size float64) float64 {
+	w := 556
+	if r >= ' ' && r <= '~' {
+		w = helveticaWidths[r-' ']
+	}
+	return float64(w) * size / 1000
+}
+
+func textWidth(s string, size float64) float64 {
+	var w float64
+	for _, r := range s {
+		w += runeWidth(r, size)
+	}
+	return w
+}
+
+// fit corta o texto com reticências para caber em width pontos.
+func fit(s string, width, size float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+
+	limit := width - textWidth(ellipsis, size)
+	var w float64
+	for i, r := range s {
+		w += runeWidth(r, size)
+		if w > limit {
+			return strings.TrimRight(s[:i], " ") + ellipsis
+		}
+	}
+	return s
+}
+
+// encode converte para WinAnsi e escapa o que é especial numa string PDF.
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
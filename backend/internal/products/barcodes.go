@@ -0,0 +1,105 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/barcode"
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrInvalidBarcode  = errors.New("barcode is not a valid GTIN")
+	ErrBarcodeTaken    = errors.New("barcode already belongs to another product")
+	ErrBarcodeNotFound = errors.New("barcode not found")
+)
+
+type AddBarcodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+func (s *Service) ListBarcodes(ctx context.Context, id, orgID uuid.UUID) ([]db.ProductBarcode, error) {
+	product, err := s.Get(ctx, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.q.ListProductBarcodes(ctx, db.ListProductBarcodesParams{
+		ProductID:      product.ID,
+		OrganizationID: product.OrganizationID,
+	})
+	if codes == nil {
+		codes = []db.ProductBarcode{}
+	}
+	return codes, err
+}
+
+// AddBarcode associa um código de barras ao produto ou variação.
+func (s *Service) AddBarcode(ctx context.Context, id, orgID uuid.UUID, req AddBarcodeRequest) (db.ProductBarcode, error) {
+	product, err := s.Get(ctx, id, orgID)
+	if err != nil {
+		return db.ProductBarcode{}, err
+	}
+	return addBarcode(ctx, s.q, product, req.Code)
+}
+
+func (s *Service) RemoveBarcode(ctx context.Context, id, orgID uuid.UUID, code string) error {
+	n, err := s.q.DeleteProductBarcode(ctx, db.DeleteProductBarcodeParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		ProductID:      pgtype.UUID{Bytes: id, Valid: true},
+		Code:           barcode.Normalize(code),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrBarcodeNotFound
+	}
+	return nil
+}
+
+// Lookup resolve o código lido no caixa para o produto, procurando primeiro
+// nos códigos de barras e depois no SKU. Produtos inativos ou com variações
+// não são encontrados, pois não podem ser vendidos.
+func (s *Service) Lookup(ctx context.Context, orgID uuid.UUID, code string) (db.Product, error) {
+	product, err := s.q.LookupProduct(ctx, lookupParams(orgID, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Product{}, ErrProductNotFound
+	}
+	return product, err
+}
+
+// lookupParams normaliza o código só para a busca por código de barras; o
+// SKU vai como digitado, porque hífens fazem parte dele (CAM-01-M-AZUL).
+func lookupParams(orgID uuid.UUID, code string) db.LookupProductParams {
+	return db.LookupProductParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Barcode:        barcode.Normalize(code),
+		Sku:            strings.TrimSpace(code),
+	}
+}
+
+func addBarcode(ctx context.Context, q *db.Queries, product db.Product, code string) (db.ProductBarcode, error) {
+	code = barcode.Normalize(code)
+	if !barcode.ValidGTIN(code) {
+		return db.ProductBarcode{}, ErrInvalidBarcode
+	}
+
+	created, err := q.AddProductBarcode(ctx, db.AddProductBarcodeParams{
+		Code:           code,
+		ProductID:      product.ID,
+		OrganizationID: product.OrganizationID,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// O produto já foi conferido; sem linha, só pode ter variações
+		return db.ProductBarcode{}, ErrProductHasVariants
+	case db.IsUniqueViolation(err, "product_barcodes_pkey"):
+		return db.ProductBarcode{}, ErrBarcodeTaken
+	}
+	return created, err
+}
@@ -0,0 +1,25 @@
+package products
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestLookupParams(t *testing.T) {
+	tests := []struct {
+		code    string
+		barcode string
+		sku     string
+	}{
+		{"CAM-01-M-AZUL", "CAM01MAZUL", "CAM-01-M-AZUL"},
+		{" CAM-ROU-0042 ", "CAMROU0042", "CAM-ROU-0042"},
+		{"789-1000-315507", "7891000315507", "789-1000-315507"},
+	}
+	for _, tt := range tests {
+		p := lookupParams(uuid.New(), tt.code)
+		if p.Barcode != tt.barcode || p.Sku != tt.sku {
+			t.Errorf("lookupParams(%q) = barcode %q, sku %q; want %q, %q", tt.code, p.Barcode, p.Sku, tt.barcode, tt.sku)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package products
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportColumns usa os nomes de ImportSpec, para que o arquivo exportado
+// possa ser editado e importado de volta.
+var ExportColumns = []string{
+	"id", "sku", "name", "description", "category", "tags", "barcodes", "price", "cost",
+	"stock_quantity", "min_stock", "reorder_quantity", "is_active", "created_at",
+}
+
+type exportRow struct {
+	db.Product
+	CategoryName string
+	Barcodes     string
+}
+
+// Export grava os produtos com os mesmos filtros e ordenação de List, mas sem
+// paginar: as linhas vão para w à medida que saem do banco.
+func (s *Service) Export(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params, w sheet.Writer) error {
+	var b pagination.Builder
+	listWhere(&b, orgID, filter, page.Search)
+
+	rows, err := s.db.Query(ctx, `SELECT p.*, COALESCE(cat.name, '') AS category_name,
+		COALESCE((SELECT string_agg(pb.code, ',' ORDER BY pb.code) FROM product_barcodes pb WHERE pb.product_id = p.id), '') AS barcodes
+		FROM products p LEFT JOIN categories cat ON cat.id = p.category_id`+b.WhereSQL()+page.OrderBy("p.id"), b.Args()...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	header := make([]sheet.Cell, len(ExportColumns))
+	for i, col := range ExportColumns {
+		header[i] = sheet.Text(col)
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		p, err := pgx.RowToStructByName[exportRow](rows)
+		if err != nil {
+			return err
+		}
+		if err := w.Write([]sheet.Cell{
+			sheet.Text(uuid.UUID(p.ID.Bytes).String()),
+			sheet.Text(p.Sku.String),
+			sheet.Text(p.Name),
+			sheet.Text(p.Description.String),
+			sheet.Text(p.CategoryName),
+			sheet.Text(strings.Join(p.Tags, ",")),
+			sheet.Text(p.Barcodes),
+			sheet.Number(p.Price.String()),
+			sheet.Number(p.AverageCost.String()),
+			sheet.Number(strconv.Itoa(int(p.StockQuantity))),
+			optionalNumber(p.MinStock.Int32, p.MinStock.Valid),
+			optionalNumber(p.ReorderQuantity.Int32, p.ReorderQuantity.Valid),
+			sheet.Text(strconv.FormatBool(p.IsActive)),
+			sheet.Text(p.CreatedAt.Time.Format("2006-01-02 15:04:05")),
+		}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func optionalNumber(v int32, valid bool) sheet.Cell {
+	if !valid {
+		return sheet.Text("")
+	}
+	return sheet.Number(strconv.Itoa(int(v)))
+}
@@ -1,16 +1,34 @@
 package products
 
 import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/export"
+	"github.com/dcastro0/aether-backend/internal/importer"
 	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sheet"
+	"github.com/dcastro0/aether-backend/internal/sku"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
 )
 
 type Handler struct {
-	service *Service
+	service   *Service
+	validator *validator.Validate
 }
 
 func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
 }
 
 func (h *Handler) Create(c *fiber.Ctx) error {
@@ -21,9 +39,13 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
 	}
 
-	product, err := h.service.Create(c.Context(), claims.OrgID, req)
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	product, err := h.service.Create(c.Context(), claims.OrgID, claims.UserID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return errorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(product)
@@ -32,7 +54,95 @@ func (h *Handler) Create(c *fiber.Ctx) error {
 func (h *Handler) List(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
-	products, err := h.service.List(c.Context(), claims.OrgID)
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	products, err := h.service.List(c.Context(), claims.OrgID, filter, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(products)
+}
+
+// Import recebe a planilha em multipart; ver importer.ReadRequest.
+func (h *Handler) Import(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	records, opts, err := importer.ReadRequest(c, ImportSpec)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.service.Import(c.Context(), claims.OrgID, claims.UserID, records, opts)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// Export baixa os produtos com os filtros e a ordenação de List, em CSV ou
+// XLSX (?format=).
+func (h *Handler) Export(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	format, err := export.SheetFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	filter, err := listFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return export.Sheet(c, "produtos", format, func(ctx context.Context, w sheet.Writer) error {
+		return h.service.Export(ctx, claims.OrgID, filter, page, w)
+	})
+}
+
+// listFilter lê os filtros de GET /products: active, category_id e tags
+// (separadas por vírgula).
+func listFilter(c *fiber.Ctx) (ListFilter, error) {
+	var filter ListFilter
+	if v := c.Query("active"); v != "" {
+		filter.Active = pgtype.Bool{Bool: c.QueryBool("active"), Valid: true}
+	}
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := uuid.Parse(v)
+		if err != nil {
+			return ListFilter{}, errors.New("invalid category_id")
+		}
+		filter.CategoryID = &categoryID
+	}
+	if v := c.Query("tags"); v != "" {
+		filter.Tags = strings.Split(v, ",")
+	}
+	return filter, nil
+}
+
+func (h *Handler) ListLowStock(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	page, err := pagination.FromQuery(c, LowStockSortFields, "shortage", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	products, err := h.service.ListLowStock(c.Context(), claims.OrgID, page)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -40,6 +150,404 @@ func (h *Handler) List(c *fiber.Ctx) error {
 	return c.JSON(products)
 }
 
+func (h *Handler) Lookup(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	code := c.Query("code")
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "code is required"})
+	}
+
+	product, err := h.service.Lookup(c.Context(), claims.OrgID, code)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(product)
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	product, err := h.service.Get(c.Context(), productID, claims.OrgID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(product)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req UpdateProductRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	product, err := h.service.Update(c.Context(), productID, claims.OrgID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(product)
+}
+
+func (h *Handler) Activate(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *Handler) Deactivate(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *Handler) setActive(c *fiber.Ctx, active bool) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	product, err := h.service.SetActive(c.Context(), productID, claims.OrgID, active)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(product)
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	softDeleted, err := h.service.Delete(c.Context(), productID, claims.OrgID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	if softDeleted {
+		return c.JSON(fiber.Map{
+			"message":      "product has history and was deactivated instead of deleted",
+			"soft_deleted": true,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) ListVariants(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	variants, err := h.service.Variants(c.Context(), productID, claims.OrgID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(variants)
+}
+
+func (h *Handler) SetOptions(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req SetOptionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	variants, err := h.service.SetOptions(c.Context(), productID, claims.OrgID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(variants)
+}
+
+func (h *Handler) UpdateVariant(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	variantID, err := uuid.Parse(c.Params("variantId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid variant id"})
+	}
+
+	var req UpdateVariantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	variant, err := h.service.UpdateVariant(c.Context(), productID, variantID, claims.OrgID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(variant)
+}
+
+func (h *Handler) ListBarcodes(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	codes, err := h.service.ListBarcodes(c.Context(), productID, claims.OrgID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(codes)
+}
+
+func (h *Handler) AddBarcode(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req AddBarcodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	code, err := h.service.AddBarcode(c.Context(), productID, claims.OrgID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(code)
+}
+
+func (h *Handler) RemoveBarcode(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.RemoveBarcode(c.Context(), productID, claims.OrgID, c.Params("code")); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) ListMovements(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	movementType := c.Query("type")
+	if movementType != "" && !validMovementType(db.StockMovementType(movementType)) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid movement type"})
+	}
+
+	page, err := pagination.FromQuery(c, MovementSortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	movements, err := h.service.ListMovements(c.Context(), productID, claims.OrgID, movementType, page)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(movements)
+}
+
+func (h *Handler) Adjust(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req AdjustStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	movement, err := h.service.Adjust(c.Context(), productID, claims.OrgID, claims.UserID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(movement)
+}
+
+func (h *Handler) OpenStockCount(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req OpenStockCountRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	count, err := h.service.OpenStockCount(c.Context(), claims.OrgID, claims.UserID, req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(count)
+}
+
+func (h *Handler) ListStockCounts(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	status := c.Query("status")
+	if status != "" && status != "open" && status != "posted" && status != "canceled" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid status"})
+	}
+
+	page, err := pagination.FromQuery(c, StockCountSortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	counts, err := h.service.ListStockCounts(c.Context(), claims.OrgID, status, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(counts)
+}
+
+func (h *Handler) GetStockCount(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	countID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	count, err := h.service.GetStockCount(c.Context(), claims.OrgID, countID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(count)
+}
+
+func (h *Handler) SubmitStockCount(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	countID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req SubmitStockCountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	count, err := h.service.SubmitStockCount(c.Context(), claims.OrgID, claims.UserID, countID, req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(count)
+}
+
+func (h *Handler) PostStockCount(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	countID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	count, err := h.service.PostStockCount(c.Context(), claims.OrgID, claims.UserID, countID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(count)
+}
+
+func (h *Handler) CancelStockCount(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	countID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	count, err := h.service.CancelStockCount(c.Context(), claims.OrgID, countID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(count)
+}
+
 func (h *Handler) GetMetrics(c *fiber.Ctx) error {
 	claims := middleware.GetClaims(c)
 
@@ -49,4 +557,63 @@ func (h *Handler) GetMetrics(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(metrics)
-}
\ No newline at end of file
+}
+
+func validMovementType(t db.StockMovementType) bool {
+	switch t {
+	case db.StockMovementTypeSale, db.StockMovementTypeReturn, db.StockMovementTypePurchase,
+		db.StockMovementTypeAdjustment, db.StockMovementTypeTransfer, db.StockMovementTypeCount:
+		return true
+	}
+	return false
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrProductNotFound), errors.Is(err, ErrStockCountNotFound),
+		errors.Is(err, ErrVariantNotFound), errors.Is(err, ErrBarcodeNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrInsufficientStock), errors.Is(err, ErrStockCountClosed),
+		errors.Is(err, ErrProductHasStock), errors.Is(err, ErrProductHasHistory), errors.Is(err, ErrBarcodeTaken),
+		errors.Is(err, ErrSKUConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, ErrStockCountEmpty), errors.Is(err, ErrProductIsVariant),
+		errors.Is(err, ErrCategoryNotFound), errors.Is(err, ErrInvalidBarcode),
+		errors.Is(err, ErrProductHasVariants), errors.Is(err, ErrTooManyVariants),
+		errors.Is(err, ErrDuplicateOption), errors.Is(err, sku.ErrTooLong):
+		return fiber.StatusUnprocessableEntity
+	}
+	return fiber.StatusInternalServerError
+}
+
+// errorCode dá às falhas que integrações precisam tratar um código estável,
+// independente da mensagem.
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrSKUConflict):
+		return "sku_conflict"
+	case errors.Is(err, ErrBarcodeTaken):
+		return "barcode_conflict"
+	case errors.Is(err, ErrInvalidBarcode):
+		return "invalid_barcode"
+	case errors.Is(err, ErrCategoryNotFound):
+		return "category_not_found"
+	}
+	return ""
+}
+
+// errorResponse responde com o status de errorStatus. Erros inesperados vão
+// para o log e o cliente recebe só uma mensagem genérica, sem o texto do banco.
+func errorResponse(c *fiber.Ctx, err error) error {
+	status := errorStatus(err)
+	if status == fiber.StatusInternalServerError {
+		log.Error().Err(err).Str("path", c.Path()).Msg("product request failed")
+		return c.Status(status).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	body := fiber.Map{"error": err.Error()}
+	if code := errorCode(err); code != "" {
+		body["code"] = code
+	}
+	return c.Status(status).JSON(body)
+}
@@ -0,0 +1,252 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/importer"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ImportSpec são as colunas aceitas em POST /products/import. category aceita
+// o nome ou o id da categoria; tags e barcodes são listas separadas por
+// vírgula, ponto e vírgula ou barra vertical.
+var ImportSpec = importer.Spec{
+	Fields: []string{
+		"name", "sku", "price", "cost", "stock_quantity", "description",
+		"min_stock", "reorder_quantity", "category", "tags", "barcodes",
+	},
+	Required: []string{"name"},
+}
+
+// importRow guarda a requisição pronta de uma linha: criação ou, quando o
+// SKU já existe, atualização do produto id.
+type importRow struct {
+	id     uuid.UUID
+	create CreateProductRequest
+	update UpdateProductRequest
+}
+
+// Import cadastra produtos a partir de uma planilha, com as mesmas regras de
+// Create. O SKU é a chave do upsert: linha com SKU já cadastrado atualiza o
+// produto, mantendo o valor atual dos campos sem coluna; estoque inicial e
+// códigos de barras só valem para produtos novos (o estoque de um produto
+// existente muda por ajuste ou contagem). Linha sem SKU cria um produto novo,
+// com SKU gerado se a organização tiver padrão.
+func (s *Service) Import(ctx context.Context, orgID, userID uuid.UUID, records []importer.Record, opts importer.Options) (*importer.Result, error) {
+	res := importer.NewResult(opts, len(records))
+
+	categories, err := s.categoryIndex(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.productsBySKU(ctx, orgID, records)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int, len(records))
+	jobs := make([]importer.Job[importRow], 0, len(records))
+	for _, rec := range records {
+		req := parseProductRow(res, rec, categories)
+		res.Validate(rec.Row, req)
+
+		key := strings.ToLower(strings.TrimSpace(req.SKU))
+		if key != "" {
+			if first, dup := seen[key]; dup {
+				res.Fail(rec.Row, "sku", fmt.Sprintf("duplicate sku, first seen at row %d", first))
+			} else {
+				seen[key] = rec.Row
+			}
+		}
+		if res.Failing(rec.Row) {
+			continue
+		}
+
+		current, ok := existing[key]
+		if !ok {
+			jobs = append(jobs, importer.Job[importRow]{Row: rec.Row, Data: importRow{create: req}})
+			continue
+		}
+		if current.ParentID.Valid {
+			res.Fail(rec.Row, "sku", ErrProductIsVariant.Error())
+			continue
+		}
+		jobs = append(jobs, importer.Job[importRow]{
+			Row:      rec.Row,
+			Existing: true,
+			Data:     importRow{id: current.ID.Bytes, update: mergeProductRow(current, rec, req)},
+		})
+	}
+
+	write := func(ctx context.Context, q *db.Queries, job importer.Job[importRow]) error {
+		var err error
+		if job.Existing {
+			_, err = update(ctx, q, job.Data.id, orgID, job.Data.update)
+		} else {
+			_, err = create(ctx, q, orgID, userID, job.Data.create)
+		}
+		return err
+	}
+	public := func(err error) bool {
+		return errorStatus(err) != fiber.StatusInternalServerError
+	}
+	if err := importer.Commit(ctx, s.db, res, jobs, write, public); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseProductRow converte as células em CreateProductRequest, registrando no
+// resultado os valores que não puderam ser lidos.
+func parseProductRow(res *importer.Result, rec importer.Record, categories map[string][]uuid.UUID) CreateProductRequest {
+	req := CreateProductRequest{
+		Name:        rec.Get("name"),
+		SKU:         rec.Get("sku"),
+		Description: rec.Get("description"),
+		Tags:        importer.SplitList(rec.Get("tags")),
+		Barcodes:    importer.SplitList(rec.Get("barcodes")),
+	}
+
+	var err error
+	if v := rec.Get("price"); v != "" {
+		if req.Price, err = importer.ParseMoney(v); err != nil {
+			res.Fail(rec.Row, "price", "must be a decimal number")
+		}
+	}
+	if v := rec.Get("cost"); v != "" {
+		if req.Cost, err = importer.ParseMoney(v); err != nil {
+			res.Fail(rec.Row, "cost", "must be a decimal number")
+		}
+	}
+	if v := rec.Get("stock_quantity"); v != "" {
+		if req.StockQuantity, err = importer.ParseInt(v); err != nil {
+			res.Fail(rec.Row, "stock_quantity", "must be an integer")
+		}
+	}
+	req.MinStock = parseOptionalInt(res, rec, "min_stock")
+	req.ReorderQuantity = parseOptionalInt(res, rec, "reorder_quantity")
+
+	if v := rec.Get("category"); v != "" {
+		if id, err := uuid.Parse(v); err == nil {
+			req.CategoryID = &id
+		} else if ids := categories[strings.ToLower(v)]; len(ids) == 1 {
+			req.CategoryID = &ids[0]
+		} else if len(ids) > 1 {
+			res.Fail(rec.Row, "category", "category name is ambiguous; use its id")
+		} else {
+			res.Fail(rec.Row, "category", ErrCategoryNotFound.Error())
+		}
+	}
+	return req
+}
+
+func parseOptionalInt(res *importer.Result, rec importer.Record, field string) *int {
+	v := rec.Get(field)
+	if v == "" {
+		return nil
+	}
+	n, err := importer.ParseInt(v)
+	if err != nil {
+		res.Fail(rec.Row, field, "must be an integer")
+		return nil
+	}
+	return &n
+}
+
+// mergeProductRow parte do cadastro atual e aplica só os campos que a
+// planilha traz.
+func mergeProductRow(current db.Product, rec importer.Record, req CreateProductRequest) UpdateProductRequest {
+	upd := UpdateProductRequest{
+		Name:            req.Name,
+		Price:           current.Price,
+		Description:     current.Description.String,
+		SKU:             current.Sku.String,
+		IsActive:        current.IsActive,
+		MinStock:        fromInt4(current.MinStock),
+		ReorderQuantity: fromInt4(current.ReorderQuantity),
+		Tags:            current.Tags,
+	}
+	if current.CategoryID.Valid {
+		id := uuid.UUID(current.CategoryID.Bytes)
+		upd.CategoryID = &id
+	}
+
+	if rec.Has("price") {
+		upd.Price = req.Price
+	}
+	if rec.Has("cost") && rec.Get("cost") != "" {
+		upd.Cost = &req.Cost
+	}
+	if rec.Has("description") {
+		upd.Description = req.Description
+	}
+	if rec.Has("min_stock") {
+		upd.MinStock = req.MinStock
+	}
+	if rec.Has("reorder_quantity") {
+		upd.ReorderQuantity = req.ReorderQuantity
+	}
+	if rec.Has("category") {
+		upd.CategoryID = req.CategoryID
+	}
+	if rec.Has("tags") {
+		upd.Tags = req.Tags
+	}
+	return upd
+}
+
+// categoryIndex indexa as categorias da organização pelo nome em minúsculas.
+// Subcategorias de pais diferentes podem repetir nome, daí a lista.
+func (s *Service) categoryIndex(ctx context.Context, orgID uuid.UUID) (map[string][]uuid.UUID, error) {
+	categories, err := s.q.ListCategories(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string][]uuid.UUID, len(categories))
+	for _, c := range categories {
+		key := strings.ToLower(c.Name)
+		index[key] = append(index[key], c.ID.Bytes)
+	}
+	return index, nil
+}
+
+// productsBySKU busca de uma vez os produtos cujos SKUs aparecem na planilha.
+func (s *Service) productsBySKU(ctx context.Context, orgID uuid.UUID, records []importer.Record) (map[string]db.Product, error) {
+	skus := make([]string, 0, len(records))
+	for _, rec := range records {
+		if v := rec.Get("sku"); v != "" {
+			skus = append(skus, strings.ToLower(v))
+		}
+	}
+	if len(skus) == 0 {
+		return map[string]db.Product{}, nil
+	}
+
+	products, err := s.q.FindProductsBySKU(ctx, db.FindProductsBySKUParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Skus:           skus,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]db.Product, len(products))
+	for _, p := range products {
+		index[strings.ToLower(p.Sku.String)] = p
+	}
+	return index, nil
+}
+
+func fromInt4(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int32)
+	return &n
+}
@@ -2,22 +2,50 @@ package products
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/dcastro0/aether-backend/internal/sku"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// MinStock e ReorderQuantity são opcionais; sem min_stock vale o limite de
+// estoque baixo da organização. Cost é o custo inicial, usado até o primeiro
+// recebimento de compra. Tags são gravadas em minúsculas, sem repetição.
+// Barcodes precisam ser GTIN válidos e livres na organização. Sem SKU, um é
+// gerado pelo padrão da organização, quando houver.
 type CreateProductRequest struct {
-	Name          string  `json:"name" validate:"required"`
-	Price         float64 `json:"price" validate:"gte=0"`
-	StockQuantity int     `json:"stock_quantity" validate:"gte=0"`
-	Description   string  `json:"description"`
-	SKU           string  `json:"sku"`
+	Name            string      `json:"name" validate:"required"`
+	Price           money.Money `json:"price" validate:"gte=0"`
+	Cost            money.Money `json:"cost" validate:"gte=0"`
+	StockQuantity   int         `json:"stock_quantity" validate:"gte=0"`
+	Description     string      `json:"description"`
+	SKU             string      `json:"sku" validate:"max=50"`
+	Barcodes        []string    `json:"barcodes" validate:"max=10"`
+	MinStock        *int        `json:"min_stock" validate:"omitempty,gte=0"`
+	ReorderQuantity *int        `json:"reorder_quantity" validate:"omitempty,gte=1"`
+	CategoryID      *uuid.UUID  `json:"category_id"`
+	Tags            []string    `json:"tags" validate:"max=20,dive,required,max=50"`
 }
 
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrSKUConflict      = errors.New("sku already in use in this organization")
+)
+
+// maxSKUAttempts limita quantos sequenciais o gerador pula quando o SKU gerado
+// já foi cadastrado à mão.
+const maxSKUAttempts = 100
+
 type Service struct {
 	q  *db.Queries
 	db *pgxpool.Pool
@@ -30,59 +58,500 @@ func NewService(pool *pgxpool.Pool) *Service {
 	}
 }
 
-func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CreateProductRequest) (db.Product, error) {
-	// 1. Converter UUID para formato do driver
-	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+// Create cadastra o produto com saldo zero e lança o estoque inicial como
+// movimento de ajuste, na mesma transação. O sequencial do SKU gerado também é
+// reservado nela, então um cadastro que falha não consome número.
+func (s *Service) Create(ctx context.Context, orgID, userID uuid.UUID, req CreateProductRequest) (db.Product, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return db.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	product, err := create(ctx, s.q.WithTx(tx), orgID, userID, req)
+	if err != nil {
+		return db.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.Product{}, err
+	}
+	return product, nil
+}
+
+// create faz o trabalho de Create com as queries de uma transação já aberta.
+func create(ctx context.Context, qtx *db.Queries, orgID, userID uuid.UUID, req CreateProductRequest) (db.Product, error) {
+	cat, err := category(ctx, qtx, orgID, req.CategoryID)
+	if err != nil {
+		return db.Product{}, err
+	}
+
+	productSKU := optionalSKU(req.SKU)
+	if !productSKU.Valid {
+		if productSKU, err = generateSKU(ctx, qtx, orgID, cat.Name); err != nil {
+			return db.Product{}, err
+		}
+	}
+
+	// O preço já chega em centavos, sem passar por float
+	product, err := qtx.CreateProduct(ctx, db.CreateProductParams{
+		OrganizationID:  pgtype.UUID{Bytes: orgID, Valid: true},
+		Name:            req.Name,
+		Description:     pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		Price:           req.Price,
+		Sku:             productSKU,
+		MinStock:        optionalInt(req.MinStock),
+		ReorderQuantity: optionalInt(req.ReorderQuantity),
+		AverageCost:     req.Cost,
+		CategoryID:      cat.ID,
+		Tags:            normalizeTags(req.Tags),
+	})
+	if err != nil {
+		return db.Product{}, skuError(err)
+	}
+
+	for _, code := range req.Barcodes {
+		if _, err := addBarcode(ctx, qtx, product, code); err != nil {
+			return db.Product{}, err
+		}
+	}
+
+	if req.StockQuantity != 0 {
+		movement, err := qtx.RecordStockMovement(ctx, db.RecordStockMovementParams{
+			Type:           db.StockMovementTypeAdjustment,
+			Quantity:       int32(req.StockQuantity),
+			ReferenceType:  pgtype.Text{String: "product", Valid: true},
+			ReferenceID:    product.ID,
+			Note:           pgtype.Text{String: "Estoque inicial", Valid: true},
+			CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+			ProductID:      product.ID,
+			OrganizationID: product.OrganizationID,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+		product.StockQuantity = movement.BalanceAfter
+	}
+	return product, nil
+}
+
+// SortFields são as ordenações aceitas em GET /products.
+var SortFields = []pagination.SortField{
+	{Name: "created_at", Column: "p.created_at", Type: "timestamptz"},
+	{Name: "name", Column: "p.name", Type: "text"},
+	{Name: "sku", Column: "COALESCE(p.sku, '')", Type: "text"},
+	{Name: "price", Column: "p.price", Type: "numeric"},
+	{Name: "stock_quantity", Column: "p.stock_quantity", Type: "integer"},
+}
+
+// ListFilter restringe GET /products. Active nulo traz ativos e inativos;
+// CategoryID inclui as subcategorias; com várias Tags, o produto precisa ter
+// todas.
+type ListFilter struct {
+	Active     pgtype.Bool
+	CategoryID *uuid.UUID
+	Tags       []string
+}
+
+// List devolve uma página dos produtos da organização. A busca procura no nome
+// e no SKU. Variações ficam de fora e são listadas por Variants.
+func (s *Service) List(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params) (pagination.Page[db.Product], error) {
+	var b pagination.Builder
+	listWhere(&b, orgID, filter, page.Search)
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM products p"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[db.Product]{}, err
+	}
 
-	// 2. Converter Preço (Float -> String -> Numeric)
-	// Isso evita erros de precisão e falhas de scan do driver
-	priceNumeric := pgtype.Numeric{}
-	if err := priceNumeric.Scan(fmt.Sprintf("%.2f", req.Price)); err != nil {
-		return db.Product{}, fmt.Errorf("erro ao processar preço: %w", err)
-	}
-
-	// 3. Executar Query
-	return s.q.CreateProduct(ctx, db.CreateProductParams{
-		OrganizationID: pgOrgID,
-		Name:           req.Name,
-		Description:    pgtype.Text{String: req.Description, Valid: req.Description != ""},
-		Price:          priceNumeric,
-		StockQuantity:  int32(req.StockQuantity),
-		Sku:            pgtype.Text{String: req.SKU, Valid: req.SKU != ""},
+	rows, err := s.db.Query(ctx, "SELECT p.* FROM products p"+page.Keyset(&b, "p.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[db.Product]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.Product])
+	if err != nil {
+		return pagination.Page[db.Product]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(p db.Product) (string, uuid.UUID) {
+		return productSortValue(p, page.Sort.Name), uuid.UUID(p.ID.Bytes)
+	}), nil
+}
+
+func productSortValue(p db.Product, sort string) string {
+	switch sort {
+	case "name":
+		return p.Name
+	case "sku":
+		return p.Sku.String
+	case "price":
+		return p.Price.String()
+	case "stock_quantity":
+		return strconv.Itoa(int(p.StockQuantity))
+	default:
+		return p.CreatedAt.Time.Format(time.RFC3339Nano)
+	}
+}
+
+// listWhere monta os filtros de List, reaproveitados por Export.
+func listWhere(b *pagination.Builder, orgID uuid.UUID, filter ListFilter, search string) {
+	pgOrgID := b.Arg(pgtype.UUID{Bytes: orgID, Valid: true})
+	b.Where("p.organization_id = " + pgOrgID)
+	b.Where("p.parent_id IS NULL")
+	if filter.Active.Valid {
+		b.Where("p.is_active = " + b.Arg(filter.Active.Bool))
+	}
+	if filter.CategoryID != nil {
+		b.Where(`p.category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT c.id FROM categories c WHERE c.id = ` + b.Arg(pgtype.UUID{Bytes: *filter.CategoryID, Valid: true}) + ` AND c.organization_id = ` + pgOrgID + `
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree st ON c.parent_id = st.id
+			)
+			SELECT id FROM subtree)`)
+	}
+	if tags := normalizeTags(filter.Tags); len(tags) > 0 {
+		b.Where("p.tags @> " + b.Arg(tags) + "::TEXT[]")
+	}
+	if search != "" {
+		pattern := b.Arg(pagination.ContainsPattern(search))
+		b.Where("(p.name ILIKE " + pattern + " OR p.sku ILIKE " + pattern + ")")
+	}
+}
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (db.Product, error) {
+	product, err := s.q.GetProduct(ctx, db.GetProductParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Product{}, ErrProductNotFound
+	}
+	return product, err
 }
 
-func (s *Service) List(ctx context.Context, orgID uuid.UUID) ([]db.Product, error) {
-	return s.q.ListProducts(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
+// LowStockProduct é um produto ativo abaixo do ponto de reposição. MinStock já
+// vem resolvido (do produto ou da organização) e SuggestedQuantity é quanto
+// comprar: o lote de reposição ou o que falta para voltar ao mínimo, o que for
+// maior.
+type LowStockProduct struct {
+	ID                pgtype.UUID `json:"id"`
+	Name              string      `json:"name"`
+	Sku               string      `json:"sku"`
+	StockQuantity     int32       `json:"stock_quantity"`
+	MinStock          int32       `json:"min_stock"`
+	ReorderQuantity   pgtype.Int4 `json:"reorder_quantity"`
+	Shortage          int32       `json:"shortage"`
+	SuggestedQuantity int32       `json:"suggested_quantity"`
+}
+
+const effectiveMinStock = "COALESCE(p.min_stock, s.low_stock_threshold, 5)"
+
+// LowStockSortFields são as ordenações aceitas em GET /products/low-stock.
+var LowStockSortFields = []pagination.SortField{
+	{Name: "shortage", Column: "(" + effectiveMinStock + " - p.stock_quantity)", Type: "integer"},
+	{Name: "name", Column: "p.name", Type: "text"},
+	{Name: "stock_quantity", Column: "p.stock_quantity", Type: "integer"},
+}
+
+// ListLowStock devolve uma página dos produtos que precisam de reposição, por
+// padrão os de maior falta primeiro.
+func (s *Service) ListLowStock(ctx context.Context, orgID uuid.UUID, page pagination.Params) (pagination.Page[LowStockProduct], error) {
+	const from = " FROM products p LEFT JOIN organization_settings s ON s.organization_id = p.organization_id"
+
+	var b pagination.Builder
+	b.Where("p.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	b.Where("p.is_active")
+	b.Where("NOT p.has_variants")
+	b.Where("p.stock_quantity < " + effectiveMinStock)
+	if page.Search != "" {
+		pattern := b.Arg(pagination.ContainsPattern(page.Search))
+		b.Where("(p.name ILIKE " + pattern + " OR p.sku ILIKE " + pattern + ")")
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*)"+from+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[LowStockProduct]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT p.id, p.name, COALESCE(p.sku, '') AS sku, p.stock_quantity,
+		`+effectiveMinStock+` AS min_stock, p.reorder_quantity,
+		`+effectiveMinStock+` - p.stock_quantity AS shortage,
+		GREATEST(COALESCE(p.reorder_quantity, 0), `+effectiveMinStock+` - p.stock_quantity) AS suggested_quantity`+
+		from+page.Keyset(&b, "p.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[LowStockProduct]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[LowStockProduct])
+	if err != nil {
+		return pagination.Page[LowStockProduct]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(p LowStockProduct) (string, uuid.UUID) {
+		switch page.Sort.Name {
+		case "name":
+			return p.Name, uuid.UUID(p.ID.Bytes)
+		case "stock_quantity":
+			return strconv.Itoa(int(p.StockQuantity)), uuid.UUID(p.ID.Bytes)
+		default:
+			return strconv.Itoa(int(p.Shortage)), uuid.UUID(p.ID.Bytes)
+		}
+	}), nil
 }
 
 func (s *Service) GetMetrics(ctx context.Context, orgID uuid.UUID) (db.GetProductMetricsRow, error) {
 	return s.q.GetProductMetrics(ctx, pgtype.UUID{Bytes: orgID, Valid: true})
 }
 
+// Cost, quando enviado, substitui o custo médio (reavaliação manual).
 type UpdateProductRequest struct {
-	Name          string  `json:"name" validate:"required"`
-	Price         float64 `json:"price" validate:"gte=0"`
-	StockQuantity int     `json:"stock_quantity" validate:"gte=0"`
-	Description   string  `json:"description"`
-	SKU           string  `json:"sku"`
-	IsActive      bool    `json:"is_active"`
+	Name            string       `json:"name" validate:"required"`
+	Price           money.Money  `json:"price" validate:"gte=0"`
+	Cost            *money.Money `json:"cost" validate:"omitempty,min=0"`
+	Description     string       `json:"description"`
+	SKU             string       `json:"sku" validate:"max=50"`
+	IsActive        bool         `json:"is_active"`
+	MinStock        *int         `json:"min_stock" validate:"omitempty,gte=0"`
+	ReorderQuantity *int         `json:"reorder_quantity" validate:"omitempty,gte=1"`
+	CategoryID      *uuid.UUID   `json:"category_id"`
+	Tags            []string     `json:"tags" validate:"max=20,dive,required,max=50"`
 }
 
+// Update altera o cadastro do produto. O estoque não passa por aqui: use
+// Adjust ou uma contagem para que a mudança fique no razão. Nome, descrição e
+// preço são repassados às variações; variações em si são alteradas por
+// UpdateVariant.
 func (s *Service) Update(ctx context.Context, id uuid.UUID, orgID uuid.UUID, req UpdateProductRequest) (db.Product, error) {
-	priceNumeric := pgtype.Numeric{}
-	if err := priceNumeric.Scan(fmt.Sprintf("%.2f", req.Price)); err != nil {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return db.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	product, err := update(ctx, s.q.WithTx(tx), id, orgID, req)
+	if err != nil {
 		return db.Product{}, err
 	}
 
-	return s.q.UpdateProduct(ctx, db.UpdateProductParams{
+	if err := tx.Commit(ctx); err != nil {
+		return db.Product{}, err
+	}
+	return product, nil
+}
+
+// update faz o trabalho de Update com as queries de uma transação já aberta.
+func update(ctx context.Context, qtx *db.Queries, id, orgID uuid.UUID, req UpdateProductRequest) (db.Product, error) {
+	pgID := pgtype.UUID{Bytes: id, Valid: true}
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	cat, err := category(ctx, qtx, orgID, req.CategoryID)
+	if err != nil {
+		return db.Product{}, err
+	}
+
+	product, err := qtx.UpdateProduct(ctx, db.UpdateProductParams{
+		ID:              pgID,
+		OrganizationID:  pgOrgID,
+		Name:            req.Name,
+		Description:     pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		Price:           req.Price,
+		Sku:             optionalSKU(req.SKU),
+		IsActive:        req.IsActive,
+		MinStock:        optionalInt(req.MinStock),
+		ReorderQuantity: optionalInt(req.ReorderQuantity),
+		CategoryID:      cat.ID,
+		Tags:            normalizeTags(req.Tags),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Sem linha: ou o produto não existe, ou é uma variação
+			existing, err := qtx.GetProduct(ctx, db.GetProductParams{ID: pgID, OrganizationID: pgOrgID})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return db.Product{}, ErrProductNotFound
+			} else if err != nil {
+				return db.Product{}, err
+			}
+			if existing.ParentID.Valid {
+				return db.Product{}, ErrProductIsVariant
+			}
+			return db.Product{}, ErrProductNotFound
+		}
+		return db.Product{}, skuError(err)
+	}
+
+	if product.HasVariants {
+		if err := qtx.SyncProductVariants(ctx, pgID); err != nil {
+			return db.Product{}, err
+		}
+	}
+
+	if req.Cost != nil {
+		product, err = qtx.SetProductAverageCost(ctx, db.SetProductAverageCostParams{
+			ID:             pgID,
+			OrganizationID: pgOrgID,
+			AverageCost:    *req.Cost,
+		})
+		if err != nil {
+			return db.Product{}, err
+		}
+	}
+	return product, nil
+}
+
+func (s *Service) SetActive(ctx context.Context, id uuid.UUID, orgID uuid.UUID, active bool) (db.Product, error) {
+	product, err := s.q.SetProductActive(ctx, db.SetProductActiveParams{
 		ID:             pgtype.UUID{Bytes: id, Valid: true},
 		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
-		Name:           req.Name,
-		Description:    pgtype.Text{String: req.Description, Valid: req.Description != ""},
-		Price:          priceNumeric,
-		StockQuantity:  int32(req.StockQuantity),
-		Sku:            pgtype.Text{String: req.SKU, Valid: req.SKU != ""},
-		IsActive:       req.IsActive,
+		IsActive:       active,
 	})
-}
\ No newline at end of file
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Product{}, ErrProductNotFound
+	}
+	return product, err
+}
+
+// Delete apaga o produto de vez quando nenhum pedido ou movimento de estoque o
+// referencia. Se uma FK impedir, o produto é apenas desativado para preservar
+// o histórico; softDeleted indica qual dos dois aconteceu.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (softDeleted bool, err error) {
+	n, err := s.q.DeleteProduct(ctx, db.DeleteProductParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			_, err := s.SetActive(ctx, id, orgID, false)
+			return err == nil, err
+		}
+		return false, err
+	}
+
+	if n == 0 {
+		return false, ErrProductNotFound
+	}
+	return false, nil
+}
+
+// MovementSortFields são as ordenações aceitas em GET /products/:id/movements.
+var MovementSortFields = []pagination.SortField{
+	{Name: "created_at", Column: "m.created_at", Type: "timestamptz"},
+}
+
+// ListMovements devolve uma página do razão de estoque do produto, do mais
+// recente para o mais antigo por padrão. movementType vazio traz todos os tipos.
+func (s *Service) ListMovements(ctx context.Context, id, orgID uuid.UUID, movementType string, page pagination.Params) (pagination.Page[db.StockMovement], error) {
+	if _, err := s.Get(ctx, id, orgID); err != nil {
+		return pagination.Page[db.StockMovement]{}, err
+	}
+
+	var b pagination.Builder
+	b.Where("m.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	b.Where("m.product_id = " + b.Arg(pgtype.UUID{Bytes: id, Valid: true}))
+	if movementType != "" {
+		b.Where("m.type = " + b.Arg(movementType) + "::stock_movement_type")
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM stock_movements m"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[db.StockMovement]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT m.* FROM stock_movements m"+page.Keyset(&b, "m.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[db.StockMovement]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.StockMovement])
+	if err != nil {
+		return pagination.Page[db.StockMovement]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(m db.StockMovement) (string, uuid.UUID) {
+		return m.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(m.ID.Bytes)
+	}), nil
+}
+
+// category confere que a categoria é da organização. Sem id, devolve uma
+// categoria vazia (ID nulo).
+func category(ctx context.Context, q *db.Queries, orgID uuid.UUID, id *uuid.UUID) (db.Category, error) {
+	if id == nil {
+		return db.Category{}, nil
+	}
+
+	c, err := q.GetCategory(ctx, db.GetCategoryParams{
+		ID:             pgtype.UUID{Bytes: *id, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Category{}, ErrCategoryNotFound
+	}
+	return c, err
+}
+
+// generateSKU monta um SKU pelo padrão da organização, pulando sequenciais
+// cujo SKU já exista. Sem padrão configurado, o produto fica sem SKU.
+func generateSKU(ctx context.Context, q *db.Queries, orgID uuid.UUID, categoryName string) (pgtype.Text, error) {
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+	now := time.Now()
+
+	for range maxSKUAttempts {
+		next, err := q.NextSKUSequence(ctx, pgOrgID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgtype.Text{}, nil
+			}
+			return pgtype.Text{}, err
+		}
+
+		code, err := sku.Format(next.Pattern, next.Seq, categoryName, now)
+		if err != nil {
+			return pgtype.Text{}, err
+		}
+
+		exists, err := q.SKUExists(ctx, db.SKUExistsParams{OrganizationID: pgOrgID, Sku: code})
+		if err != nil {
+			return pgtype.Text{}, err
+		}
+		if !exists {
+			return pgtype.Text{String: code, Valid: true}, nil
+		}
+	}
+	return pgtype.Text{}, ErrSKUConflict
+}
+
+// skuError traduz a violação do índice único de SKU para ErrSKUConflict.
+func skuError(err error) error {
+	if db.IsUniqueViolation(err, "idx_products_org_sku") {
+		return ErrSKUConflict
+	}
+	return err
+}
+
+// optionalSKU tira espaços das pontas; SKU em branco vira NULL, que não
+// conflita com nenhum outro.
+func optionalSKU(v string) pgtype.Text {
+	v = strings.TrimSpace(v)
+	return pgtype.Text{String: v, Valid: v != ""}
+}
+
+// normalizeTags devolve as tags em minúsculas, sem espaços nas pontas e sem
+// repetição, na ordem recebida. Nunca devolve nil: a coluna é NOT NULL.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+func optionalInt(v *int) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*v), Valid: true}
+}
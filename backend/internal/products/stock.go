@@ -0,0 +1,331 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AdjustStockRequest corrige o saldo de um produto. Quantity é a variação:
+// positiva entra no estoque, negativa sai.
+type AdjustStockRequest struct {
+	Quantity int    `json:"quantity" validate:"required,ne=0"`
+	Reason   string `json:"reason" validate:"required,oneof=damage loss theft expired internal_use found correction"`
+	Note     string `json:"note" validate:"max=500"`
+}
+
+type OpenStockCountRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+type StockCountEntry struct {
+	ProductID       uuid.UUID `json:"product_id" validate:"required"`
+	CountedQuantity int       `json:"counted_quantity" validate:"gte=0"`
+}
+
+type SubmitStockCountRequest struct {
+	Items []StockCountEntry `json:"items" validate:"required,min=1,dive"`
+}
+
+// StockCountItemResponse compara o contado com o saldo do sistema. Enquanto a
+// contagem está aberta, SystemQuantity é o saldo atual; depois de lançada, é o
+// saldo no momento do lançamento.
+type StockCountItemResponse struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	SKU             string    `json:"sku"`
+	CountedQuantity int32     `json:"counted_quantity"`
+	SystemQuantity  int32     `json:"system_quantity"`
+	Variance        int32     `json:"variance"`
+	CountedAt       time.Time `json:"counted_at"`
+}
+
+type StockCountResponse struct {
+	db.StockCount
+	Items             []StockCountItemResponse `json:"items"`
+	ItemsWithVariance int                      `json:"items_with_variance"`
+}
+
+const (
+	stockCountOpen = "open"
+	reasonCount    = "count"
+)
+
+var (
+	ErrInsufficientStock  = errors.New("stock cannot become negative")
+	ErrStockCountNotFound = errors.New("stock count not found")
+	ErrStockCountClosed   = errors.New("stock count is not open")
+	ErrStockCountEmpty    = errors.New("stock count has no items")
+)
+
+// Adjust lança um ajuste manual no razão de estoque.
+func (s *Service) Adjust(ctx context.Context, id, orgID, userID uuid.UUID, req AdjustStockRequest) (db.StockMovement, error) {
+	movement, err := s.q.RecordStockMovement(ctx, db.RecordStockMovementParams{
+		Type:           db.StockMovementTypeAdjustment,
+		Quantity:       int32(req.Quantity),
+		ReasonCode:     pgtype.Text{String: req.Reason, Valid: true},
+		Note:           pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+		ProductID:      pgtype.UUID{Bytes: id, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Sem linha: o produto não existe, tem variações ou o saldo ficaria
+		// negativo
+		product, err := s.Get(ctx, id, orgID)
+		if err != nil {
+			return db.StockMovement{}, err
+		}
+		if product.HasVariants {
+			return db.StockMovement{}, ErrProductHasVariants
+		}
+		return db.StockMovement{}, ErrInsufficientStock
+	}
+	return movement, err
+}
+
+func (s *Service) OpenStockCount(ctx context.Context, orgID, userID uuid.UUID, req OpenStockCountRequest) (db.StockCount, error) {
+	return s.q.CreateStockCount(ctx, db.CreateStockCountParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Note:           pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		OpenedBy:       pgtype.UUID{Bytes: userID, Valid: true},
+	})
+}
+
+// StockCountSortFields são as ordenações aceitas em GET /stock-counts.
+var StockCountSortFields = []pagination.SortField{
+	{Name: "created_at", Column: "sc.created_at", Type: "timestamptz"},
+}
+
+// ListStockCounts devolve uma página das contagens; status vazio traz todas.
+func (s *Service) ListStockCounts(ctx context.Context, orgID uuid.UUID, status string, page pagination.Params) (pagination.Page[db.StockCount], error) {
+	var b pagination.Builder
+	b.Where("sc.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	if status != "" {
+		b.Where("sc.status = " + b.Arg(status))
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM stock_counts sc"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[db.StockCount]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT sc.* FROM stock_counts sc"+page.Keyset(&b, "sc.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[db.StockCount]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.StockCount])
+	if err != nil {
+		return pagination.Page[db.StockCount]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(sc db.StockCount) (string, uuid.UUID) {
+		return sc.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(sc.ID.Bytes)
+	}), nil
+}
+
+// GetStockCount devolve a contagem com as divergências de cada item.
+func (s *Service) GetStockCount(ctx context.Context, orgID, countID uuid.UUID) (StockCountResponse, error) {
+	count, err := s.q.GetStockCount(ctx, db.GetStockCountParams{
+		ID:             pgtype.UUID{Bytes: countID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return StockCountResponse{}, ErrStockCountNotFound
+		}
+		return StockCountResponse{}, err
+	}
+
+	rows, err := s.q.ListStockCountItems(ctx, count.ID)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+
+	resp := StockCountResponse{StockCount: count, Items: make([]StockCountItemResponse, 0, len(rows))}
+	for _, r := range rows {
+		system := r.CurrentQuantity
+		if r.SystemQuantity.Valid {
+			system = r.SystemQuantity.Int32
+		}
+
+		item := StockCountItemResponse{
+			ProductID:       uuid.UUID(r.ProductID.Bytes),
+			ProductName:     r.ProductName,
+			SKU:             r.Sku.String,
+			CountedQuantity: r.CountedQuantity,
+			SystemQuantity:  system,
+			Variance:        r.CountedQuantity - system,
+			CountedAt:       r.CountedAt.Time,
+		}
+		if item.Variance != 0 {
+			resp.ItemsWithVariance++
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	return resp, nil
+}
+
+// SubmitStockCount grava as quantidades contadas. Enviar de novo o mesmo
+// produto substitui a contagem anterior.
+func (s *Service) SubmitStockCount(ctx context.Context, orgID, userID, countID uuid.UUID, req SubmitStockCountRequest) (StockCountResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	count, err := s.lockOpenStockCount(ctx, qtx, orgID, countID)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+
+	for _, entry := range req.Items {
+		n, err := qtx.UpsertStockCountItem(ctx, db.UpsertStockCountItemParams{
+			CountID:         count.ID,
+			CountedQuantity: int32(entry.CountedQuantity),
+			CountedBy:       pgtype.UUID{Bytes: userID, Valid: true},
+			ProductID:       pgtype.UUID{Bytes: entry.ProductID, Valid: true},
+			OrganizationID:  count.OrganizationID,
+		})
+		if err != nil {
+			return StockCountResponse{}, err
+		}
+		if n == 0 {
+			return StockCountResponse{}, ErrProductNotFound
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return StockCountResponse{}, err
+	}
+	return s.GetStockCount(ctx, orgID, countID)
+}
+
+// PostStockCount fecha a contagem e lança, numa única transação, um movimento
+// do tipo count para cada produto cujo contado difere do saldo. Os produtos
+// ficam travados durante o lançamento para que vendas simultâneas não se
+// percam no cálculo da diferença.
+func (s *Service) PostStockCount(ctx context.Context, orgID, userID, countID uuid.UUID) (StockCountResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	count, err := s.lockOpenStockCount(ctx, qtx, orgID, countID)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+
+	products, err := qtx.LockStockCountProducts(ctx, count.ID)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+	if len(products) == 0 {
+		return StockCountResponse{}, ErrStockCountEmpty
+	}
+
+	items, err := qtx.ListStockCountItems(ctx, count.ID)
+	if err != nil {
+		return StockCountResponse{}, err
+	}
+
+	stock := make(map[uuid.UUID]int32, len(products))
+	for _, p := range products {
+		stock[uuid.UUID(p.ID.Bytes)] = p.StockQuantity
+	}
+
+	for _, item := range items {
+		system := stock[uuid.UUID(item.ProductID.Bytes)]
+
+		if err := qtx.SetStockCountItemSystemQuantity(ctx, db.SetStockCountItemSystemQuantityParams{
+			CountID:        count.ID,
+			ProductID:      item.ProductID,
+			SystemQuantity: pgtype.Int4{Int32: system, Valid: true},
+		}); err != nil {
+			return StockCountResponse{}, err
+		}
+
+		delta := item.CountedQuantity - system
+		if delta == 0 {
+			continue
+		}
+
+		if _, err := qtx.RecordStockMovement(ctx, db.RecordStockMovementParams{
+			Type:           db.StockMovementTypeCount,
+			Quantity:       delta,
+			ReferenceType:  pgtype.Text{String: "stock_count", Valid: true},
+			ReferenceID:    count.ID,
+			ReasonCode:     pgtype.Text{String: reasonCount, Valid: true},
+			CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+			ProductID:      item.ProductID,
+			OrganizationID: count.OrganizationID,
+		}); err != nil {
+			return StockCountResponse{}, err
+		}
+	}
+
+	if _, err := qtx.PostStockCount(ctx, db.PostStockCountParams{
+		ID:             count.ID,
+		OrganizationID: count.OrganizationID,
+		PostedBy:       pgtype.UUID{Bytes: userID, Valid: true},
+	}); err != nil {
+		return StockCountResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return StockCountResponse{}, err
+	}
+	return s.GetStockCount(ctx, orgID, countID)
+}
+
+// CancelStockCount descarta uma contagem aberta sem mexer no estoque.
+func (s *Service) CancelStockCount(ctx context.Context, orgID, countID uuid.UUID) (db.StockCount, error) {
+	count, err := s.q.CancelStockCount(ctx, db.CancelStockCountParams{
+		ID:             pgtype.UUID{Bytes: countID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Distingue contagem inexistente de contagem já fechada
+		if _, err := s.q.GetStockCount(ctx, db.GetStockCountParams{
+			ID:             pgtype.UUID{Bytes: countID, Valid: true},
+			OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return db.StockCount{}, ErrStockCountNotFound
+			}
+			return db.StockCount{}, err
+		}
+		return db.StockCount{}, ErrStockCountClosed
+	}
+	return count, err
+}
+
+func (s *Service) lockOpenStockCount(ctx context.Context, qtx *db.Queries, orgID, countID uuid.UUID) (db.StockCount, error) {
+	count, err := qtx.GetStockCountForUpdate(ctx, db.GetStockCountForUpdateParams{
+		ID:             pgtype.UUID{Bytes: countID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.StockCount{}, ErrStockCountNotFound
+		}
+		return db.StockCount{}, err
+	}
+	if count.Status != stockCountOpen {
+		return db.StockCount{}, ErrStockCountClosed
+	}
+	return count, nil
+}
@@ -0,0 +1,326 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxVariants limita o produto cartesiano dos eixos de opção.
+const maxVariants = 100
+
+var (
+	ErrVariantNotFound    = errors.New("variant not found")
+	ErrProductIsVariant   = errors.New("product is a variant; use the variant endpoints")
+	ErrProductHasVariants = errors.New("product has variants; use one of its variants")
+	ErrProductHasStock    = errors.New("product has stock; zero it before adding variants")
+	ErrProductHasHistory  = errors.New("product has sales, purchases or stock movements; create a new product with variants instead")
+	ErrTooManyVariants    = errors.New("options generate too many variants")
+	ErrDuplicateOption    = errors.New("option names and values must be unique")
+)
+
+// ProductOption é um eixo de variação (ex.: Tamanho: P, M, G).
+type ProductOption struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max=50,dive,required,max=50"`
+}
+
+type SetOptionsRequest struct {
+	Options []ProductOption `json:"options" validate:"required,min=1,max=3,dive"`
+}
+
+// Price nulo faz a variação seguir o preço do produto pai. Códigos de barras
+// da variação são mantidos em /products/:id/barcodes, com o id da variação.
+type UpdateVariantRequest struct {
+	SKU      string       `json:"sku" validate:"max=50"`
+	Price    *money.Money `json:"price" validate:"omitempty,min=0"`
+	IsActive bool         `json:"is_active"`
+}
+
+type VariantsResponse struct {
+	Options  []ProductOption `json:"options"`
+	Variants []db.Product    `json:"variants"`
+}
+
+// Variants devolve os eixos de opção e as variações do produto.
+func (s *Service) Variants(ctx context.Context, id, orgID uuid.UUID) (VariantsResponse, error) {
+	product, err := s.Get(ctx, id, orgID)
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+	if product.ParentID.Valid {
+		return VariantsResponse{}, ErrProductIsVariant
+	}
+
+	options, err := s.q.ListProductOptions(ctx, product.ID)
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+
+	variants, err := s.q.ListProductVariants(ctx, db.ListProductVariantsParams{
+		ParentID:       product.ID,
+		OrganizationID: product.OrganizationID,
+	})
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+
+	resp := VariantsResponse{
+		Options:  make([]ProductOption, 0, len(options)),
+		Variants: variants,
+	}
+	if resp.Variants == nil {
+		resp.Variants = []db.Product{}
+	}
+	for _, o := range options {
+		resp.Options = append(resp.Options, ProductOption{Name: o.Name, Values: o.Choices})
+	}
+	return resp, nil
+}
+
+// SetOptions grava os eixos de opção do produto e gera as variações que ainda
+// não existem, uma por combinação, com estoque zero e o SKU do pai seguido dos
+// valores. Variações cuja combinação saiu dos eixos são desativadas (podem ter
+// histórico); as que continuam valendo não são alteradas. O produto só pode
+// ganhar variações com saldo zero e sem histórico de vendas, compras ou
+// movimentos, já que o estoque passa a ficar nelas.
+func (s *Service) SetOptions(ctx context.Context, id, orgID uuid.UUID, req SetOptionsRequest) (VariantsResponse, error) {
+	options, err := normalizeOptions(req.Options)
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+
+	combos := combinations(options)
+	if len(combos) > maxVariants {
+		return VariantsResponse{}, ErrTooManyVariants
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+
+	product, err := qtx.GetProductForUpdate(ctx, db.GetProductForUpdateParams{
+		ID:             pgtype.UUID{Bytes: id, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return VariantsResponse{}, ErrProductNotFound
+		}
+		return VariantsResponse{}, err
+	}
+	if product.ParentID.Valid {
+		return VariantsResponse{}, ErrProductIsVariant
+	}
+	if !product.HasVariants {
+		if product.StockQuantity != 0 {
+			return VariantsResponse{}, ErrProductHasStock
+		}
+		hasHistory, err := qtx.ProductHasHistory(ctx, product.ID)
+		if err != nil {
+			return VariantsResponse{}, err
+		}
+		if hasHistory {
+			return VariantsResponse{}, ErrProductHasHistory
+		}
+	}
+
+	if err := qtx.DeleteProductOptions(ctx, product.ID); err != nil {
+		return VariantsResponse{}, err
+	}
+	for i, o := range options {
+		if err := qtx.CreateProductOption(ctx, db.CreateProductOptionParams{
+			ProductID: product.ID,
+			Position:  int16(i),
+			Name:      o.Name,
+			Choices:   o.Values,
+		}); err != nil {
+			return VariantsResponse{}, err
+		}
+	}
+
+	existing, err := qtx.ListProductVariants(ctx, db.ListProductVariantsParams{
+		ParentID:       product.ID,
+		OrganizationID: product.OrganizationID,
+	})
+	if err != nil {
+		return VariantsResponse{}, err
+	}
+	stale := make(map[string]db.Product, len(existing))
+	for _, v := range existing {
+		stale[variantKey(v.OptionValues)] = v
+	}
+
+	for _, combo := range combos {
+		key := variantKey(combo)
+		if _, ok := stale[key]; ok {
+			delete(stale, key)
+			continue
+		}
+
+		sku := variantSKU(product.Sku.String, combo)
+		if _, err := qtx.CreateProductVariant(ctx, db.CreateProductVariantParams{
+			Name:         product.Name + " - " + strings.Join(combo, " / "),
+			Sku:          pgtype.Text{String: sku, Valid: sku != ""},
+			OptionValues: combo,
+			ParentID:     product.ID,
+		}); err != nil {
+			return VariantsResponse{}, skuError(err)
+		}
+	}
+
+	for _, v := range stale {
+		if !v.IsActive {
+			continue
+		}
+		if err := qtx.SetVariantActive(ctx, db.SetVariantActiveParams{ID: v.ID, IsActive: false}); err != nil {
+			return VariantsResponse{}, err
+		}
+	}
+
+	if !product.HasVariants {
+		if err := qtx.SetProductHasVariants(ctx, product.ID); err != nil {
+			return VariantsResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return VariantsResponse{}, err
+	}
+	return s.Variants(ctx, id, orgID)
+}
+
+// UpdateVariant altera SKU, preço e status de uma variação. Nome e descrição
+// vêm sempre do produto pai.
+func (s *Service) UpdateVariant(ctx context.Context, parentID, variantID, orgID uuid.UUID, req UpdateVariantRequest) (db.Product, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return db.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	// Trava o pai para que uma mudança de preço simultânea não se perca
+	parent, err := qtx.GetProductForUpdate(ctx, db.GetProductForUpdateParams{
+		ID:             pgtype.UUID{Bytes: parentID, Valid: true},
+		OrganizationID: pgOrgID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrProductNotFound
+		}
+		return db.Product{}, err
+	}
+
+	price := parent.Price
+	if req.Price != nil {
+		price = *req.Price
+	}
+
+	variant, err := qtx.UpdateProductVariant(ctx, db.UpdateProductVariantParams{
+		ID:              pgtype.UUID{Bytes: variantID, Valid: true},
+		ParentID:        parent.ID,
+		OrganizationID:  pgOrgID,
+		Sku:             optionalSKU(req.SKU),
+		Price:           price,
+		PriceOverridden: req.Price != nil,
+		IsActive:        req.IsActive,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Product{}, ErrVariantNotFound
+		}
+		return db.Product{}, skuError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.Product{}, err
+	}
+	return variant, nil
+}
+
+// normalizeOptions tira espaços das pontas e recusa eixos ou valores
+// repetidos, sem diferenciar maiúsculas.
+func normalizeOptions(options []ProductOption) ([]ProductOption, error) {
+	names := make(map[string]bool, len(options))
+	result := make([]ProductOption, 0, len(options))
+	for _, o := range options {
+		name := strings.TrimSpace(o.Name)
+		if name == "" || names[strings.ToLower(name)] {
+			return nil, ErrDuplicateOption
+		}
+		names[strings.ToLower(name)] = true
+
+		seen := make(map[string]bool, len(o.Values))
+		values := make([]string, 0, len(o.Values))
+		for _, v := range o.Values {
+			v = strings.TrimSpace(v)
+			if v == "" || seen[strings.ToLower(v)] {
+				return nil, ErrDuplicateOption
+			}
+			seen[strings.ToLower(v)] = true
+			values = append(values, v)
+		}
+		result = append(result, ProductOption{Name: name, Values: values})
+	}
+	return result, nil
+}
+
+// combinations devolve o produto cartesiano dos valores, na ordem dos eixos.
+func combinations(options []ProductOption) [][]string {
+	combos := [][]string{{}}
+	for _, o := range options {
+		next := make([][]string, 0, len(combos)*len(o.Values))
+		for _, c := range combos {
+			for _, v := range o.Values {
+				combo := make([]string, len(c), len(c)+1)
+				copy(combo, c)
+				next = append(next, append(combo, v))
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+func variantKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+// variantSKU monta o SKU da variação a partir do SKU do pai (ex.: CAM-01-M-AZUL).
+// Sem SKU no pai, a variação também fica sem.
+func variantSKU(parentSKU string, values []string) string {
+	if parentSKU == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(parentSKU)
+	for _, v := range values {
+		b.WriteByte('-')
+		for _, r := range strings.ToUpper(v) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	sku := []rune(b.String())
+	if len(sku) > 50 {
+		sku = sku[:50]
+	}
+	return string(sku)
+}
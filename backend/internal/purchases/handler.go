@@ -0,0 +1,188 @@
+package purchases
+
+import (
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req CreatePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id, err := h.service.Create(c.Context(), claims.OrgID, claims.UserID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Pedido de compra criado", "id": id})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	filter := ListFilter{Status: c.Query("status")}
+	switch filter.Status {
+	case "", StatusDraft, StatusSent, StatusPartiallyReceived, StatusReceived, StatusCanceled:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid status"})
+	}
+
+	if v := c.Query("supplier_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid supplier_id"})
+		}
+		filter.SupplierID = id
+	}
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	orders, err := h.service.List(c.Context(), claims.OrgID, filter, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(orders)
+}
+
+func (h *Handler) GetDetails(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	details, err := h.service.GetDetails(c.Context(), claims.OrgID, orderID)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(details)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req CreatePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.service.Update(c.Context(), claims.OrgID, orderID, req); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return h.GetDetails(c)
+}
+
+func (h *Handler) Send(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.Send(c.Context(), claims.OrgID, orderID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return h.GetDetails(c)
+}
+
+func (h *Handler) Cancel(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.Cancel(c.Context(), claims.OrgID, orderID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return h.GetDetails(c)
+}
+
+func (h *Handler) Receive(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req ReceivePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	receiptID, err := h.service.Receive(c.Context(), claims.OrgID, claims.UserID, orderID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Recebimento registrado", "id": receiptID})
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrPurchaseOrderNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrSupplierNotFound),
+		errors.Is(err, ErrProductNotFound),
+		errors.Is(err, ErrProductInactive),
+		errors.Is(err, ErrProductHasVariants),
+		errors.Is(err, ErrPurchaseOrderItemNotFound):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, ErrPurchaseOrderNotDraft),
+		errors.Is(err, ErrPurchaseOrderNotCancelable),
+		errors.Is(err, ErrPurchaseOrderNotReceiving),
+		errors.Is(err, ErrOverReceipt):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
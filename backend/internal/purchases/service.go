@@ -0,0 +1,610 @@
+package purchases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	StatusDraft             = "draft"
+	StatusSent              = "sent"
+	StatusPartiallyReceived = "partially_received"
+	StatusReceived          = "received"
+	StatusCanceled          = "canceled"
+)
+
+type PurchaseOrderItemDTO struct {
+	ProductID uuid.UUID   `json:"product_id" validate:"required"`
+	Quantity  int         `json:"quantity" validate:"required,min=1"`
+	UnitCost  money.Money `json:"unit_cost" validate:"min=0"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID uuid.UUID              `json:"supplier_id" validate:"required"`
+	Note       string                 `json:"note" validate:"max=500"`
+	ExpectedAt string                 `json:"expected_at" validate:"omitempty,datetime=2006-01-02"`
+	Items      []PurchaseOrderItemDTO `json:"items" validate:"required,min=1,dive"`
+}
+
+// ReceiveItemDTO registra a chegada de parte de um item do pedido. Sem
+// unit_cost, vale o custo combinado no pedido.
+type ReceiveItemDTO struct {
+	ItemID   uuid.UUID    `json:"item_id" validate:"required"`
+	Quantity int          `json:"quantity" validate:"required,min=1"`
+	UnitCost *money.Money `json:"unit_cost" validate:"omitempty,min=0"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	Note  string           `json:"note" validate:"max=500"`
+	Items []ReceiveItemDTO `json:"items" validate:"required,min=1,dive"`
+}
+
+type PurchaseOrderResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	SupplierID   uuid.UUID   `json:"supplier_id"`
+	SupplierName string      `json:"supplier_name"`
+	Status       string      `json:"status"`
+	TotalAmount  money.Money `json:"total_amount"`
+	Note         string      `json:"note,omitempty"`
+	ExpectedAt   string      `json:"expected_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	SentAt       *time.Time  `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time  `json:"received_at,omitempty"`
+	CanceledAt   *time.Time  `json:"canceled_at,omitempty"`
+}
+
+type PurchaseOrderItemResponse struct {
+	ID               uuid.UUID   `json:"id"`
+	ProductID        uuid.UUID   `json:"product_id"`
+	ProductName      string      `json:"product_name"`
+	SKU              string      `json:"sku"`
+	QuantityOrdered  int32       `json:"quantity_ordered"`
+	QuantityReceived int32       `json:"quantity_received"`
+	UnitCost         money.Money `json:"unit_cost"`
+	TotalCost        money.Money `json:"total_cost"`
+}
+
+type ReceiptItemResponse struct {
+	ItemID      uuid.UUID   `json:"item_id"`
+	ProductID   uuid.UUID   `json:"product_id"`
+	ProductName string      `json:"product_name"`
+	Quantity    int32       `json:"quantity"`
+	UnitCost    money.Money `json:"unit_cost"`
+	TotalCost   money.Money `json:"total_cost"`
+}
+
+type ReceiptResponse struct {
+	ID         uuid.UUID             `json:"id"`
+	Note       string                `json:"note,omitempty"`
+	ReceivedBy *uuid.UUID            `json:"received_by,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	Items      []ReceiptItemResponse `json:"items"`
+}
+
+type PurchaseOrderDetailsResponse struct {
+	PurchaseOrderResponse
+	Items    []PurchaseOrderItemResponse `json:"items"`
+	Receipts []ReceiptResponse           `json:"receipts"`
+}
+
+var (
+	ErrPurchaseOrderNotFound      = errors.New("pedido de compra não encontrado")
+	ErrPurchaseOrderNotDraft      = errors.New("só pedidos em rascunho podem ser alterados ou enviados")
+	ErrPurchaseOrderNotCancelable = errors.New("só pedidos em rascunho ou enviados podem ser cancelados")
+	ErrPurchaseOrderNotReceiving  = errors.New("o pedido precisa estar enviado para receber mercadoria")
+	ErrPurchaseOrderItemNotFound  = errors.New("item não pertence ao pedido de compra")
+	ErrOverReceipt                = errors.New("quantidade recebida maior que a pendente")
+	ErrSupplierNotFound           = errors.New("fornecedor não encontrado")
+	ErrProductNotFound            = errors.New("produto não encontrado")
+	ErrProductInactive            = errors.New("produto inativo")
+	ErrProductHasVariants         = errors.New("produto possui variações; compre uma variação")
+)
+
+type Service struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+// Create abre um pedido de compra em rascunho.
+func (s *Service) Create(ctx context.Context, orgID, userID uuid.UUID, req CreatePurchaseOrderRequest) (uuid.UUID, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	total, err := s.checkRequest(ctx, qtx, pgOrgID, req)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	expectedAt, _ := parseDate(req.ExpectedAt)
+	order, err := qtx.CreatePurchaseOrder(ctx, db.CreatePurchaseOrderParams{
+		OrganizationID: pgOrgID,
+		SupplierID:     pgtype.UUID{Bytes: req.SupplierID, Valid: true},
+		TotalAmount:    total,
+		Note:           pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		ExpectedAt:     expectedAt,
+		CreatedBy:      pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := createItems(ctx, qtx, order.ID, req.Items); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.UUID(order.ID.Bytes), nil
+}
+
+// Update substitui fornecedor, observações e itens de um rascunho.
+func (s *Service) Update(ctx context.Context, orgID, orderID uuid.UUID, req CreatePurchaseOrderRequest) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+
+	order, err := lockOrder(ctx, qtx, pgOrgID, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != StatusDraft {
+		return ErrPurchaseOrderNotDraft
+	}
+
+	total, err := s.checkRequest(ctx, qtx, pgOrgID, req)
+	if err != nil {
+		return err
+	}
+
+	expectedAt, _ := parseDate(req.ExpectedAt)
+	if _, err := qtx.UpdatePurchaseOrder(ctx, db.UpdatePurchaseOrderParams{
+		ID:             order.ID,
+		OrganizationID: pgOrgID,
+		SupplierID:     pgtype.UUID{Bytes: req.SupplierID, Valid: true},
+		TotalAmount:    total,
+		Note:           pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		ExpectedAt:     expectedAt,
+	}); err != nil {
+		return err
+	}
+
+	if err := qtx.DeletePurchaseOrderItems(ctx, order.ID); err != nil {
+		return err
+	}
+	if err := createItems(ctx, qtx, order.ID, req.Items); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Send marca o rascunho como enviado ao fornecedor; a partir daqui ele pode
+// receber mercadoria, mas não pode mais ser alterado.
+func (s *Service) Send(ctx context.Context, orgID, orderID uuid.UUID) error {
+	_, err := s.q.SendPurchaseOrder(ctx, db.SendPurchaseOrderParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.transitionError(ctx, orgID, orderID, ErrPurchaseOrderNotDraft)
+	}
+	return err
+}
+
+// Cancel cancela pedidos que ainda não receberam nada.
+func (s *Service) Cancel(ctx context.Context, orgID, orderID uuid.UUID) error {
+	_, err := s.q.CancelPurchaseOrder(ctx, db.CancelPurchaseOrderParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.transitionError(ctx, orgID, orderID, ErrPurchaseOrderNotCancelable)
+	}
+	return err
+}
+
+// Receive registra um recebimento de mercadoria: grava o custo de cada item
+// recebido, lança a entrada no razão de estoque, recalcula o custo médio
+// ponderado do produto e move o pedido para partially_received ou received.
+// Tudo numa única transação, com o pedido travado para que dois recebimentos
+// simultâneos não passem do pedido.
+func (s *Service) Receive(ctx context.Context, orgID, userID, orderID uuid.UUID, req ReceivePurchaseOrderRequest) (uuid.UUID, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.q.WithTx(tx)
+	pgOrgID := pgtype.UUID{Bytes: orgID, Valid: true}
+	pgUserID := pgtype.UUID{Bytes: userID, Valid: true}
+
+	order, err := lockOrder(ctx, qtx, pgOrgID, orderID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if order.Status != StatusSent && order.Status != StatusPartiallyReceived {
+		return uuid.Nil, ErrPurchaseOrderNotReceiving
+	}
+
+	rows, err := qtx.ListPurchaseOrderItems(ctx, order.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	items := make(map[uuid.UUID]db.ListPurchaseOrderItemsRow, len(rows))
+	for _, r := range rows {
+		items[uuid.UUID(r.ID.Bytes)] = r
+	}
+
+	receipt, err := qtx.CreatePurchaseReceipt(ctx, db.CreatePurchaseReceiptParams{
+		OrganizationID:  pgOrgID,
+		PurchaseOrderID: order.ID,
+		Note:            pgtype.Text{String: req.Note, Valid: req.Note != ""},
+		ReceivedBy:      pgUserID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	for _, entry := range req.Items {
+		item, ok := items[entry.ItemID]
+		if !ok {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrPurchaseOrderItemNotFound, entry.ItemID)
+		}
+
+		unitCost := item.UnitCost
+		if entry.UnitCost != nil {
+			unitCost = *entry.UnitCost
+		}
+
+		n, err := qtx.AddPurchaseOrderItemReceived(ctx, db.AddPurchaseOrderItemReceivedParams{
+			Quantity:        int32(entry.Quantity),
+			ID:              item.ID,
+			PurchaseOrderID: order.ID,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if n == 0 {
+			return uuid.Nil, fmt.Errorf("%w: %s", ErrOverReceipt, item.ProductName)
+		}
+
+		if err := qtx.CreatePurchaseReceiptItem(ctx, db.CreatePurchaseReceiptItemParams{
+			ReceiptID:           receipt.ID,
+			PurchaseOrderItemID: item.ID,
+			ProductID:           item.ProductID,
+			Quantity:            int32(entry.Quantity),
+			UnitCost:            unitCost,
+		}); err != nil {
+			return uuid.Nil, err
+		}
+
+		// Trava o produto e lê o custo médio antes da entrada
+		product, err := qtx.GetProductCostForUpdate(ctx, db.GetProductCostForUpdateParams{
+			ID:             item.ProductID,
+			OrganizationID: pgOrgID,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		if _, err := qtx.RecordStockMovement(ctx, db.RecordStockMovementParams{
+			Type:           db.StockMovementTypePurchase,
+			Quantity:       int32(entry.Quantity),
+			ReferenceType:  pgtype.Text{String: "purchase_receipt", Valid: true},
+			ReferenceID:    receipt.ID,
+			CreatedBy:      pgUserID,
+			ProductID:      item.ProductID,
+			OrganizationID: pgOrgID,
+		}); err != nil {
+			return uuid.Nil, err
+		}
+
+		if err := qtx.UpdateProductCost(ctx, db.UpdateProductCostParams{
+			ID:          item.ProductID,
+			AverageCost: money.WeightedAverage(product.AverageCost, int64(product.StockQuantity), unitCost, int64(entry.Quantity)),
+			LastCost:    unitCost,
+		}); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	pending, err := qtx.CountPendingPurchaseOrderItems(ctx, order.ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	status := StatusPartiallyReceived
+	if pending == 0 {
+		status = StatusReceived
+	}
+	if err := qtx.SetPurchaseOrderReceiptStatus(ctx, db.SetPurchaseOrderReceiptStatusParams{
+		Status: status,
+		ID:     order.ID,
+	}); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.UUID(receipt.ID.Bytes), nil
+}
+
+// SortFields são as ordenações aceitas em GET /purchase-orders.
+var SortFields = []pagination.SortField{
+	{Name: "created_at", Column: "po.created_at", Type: "timestamptz"},
+	{Name: "total_amount", Column: "po.total_amount", Type: "numeric"},
+}
+
+// ListFilter restringe a listagem; campos vazios não filtram.
+type ListFilter struct {
+	Status     string
+	SupplierID uuid.UUID
+}
+
+// List devolve uma página dos pedidos de compra. A busca procura no nome do
+// fornecedor.
+func (s *Service) List(ctx context.Context, orgID uuid.UUID, filter ListFilter, page pagination.Params) (pagination.Page[PurchaseOrderResponse], error) {
+	const from = " FROM purchase_orders po JOIN suppliers s ON po.supplier_id = s.id"
+
+	var b pagination.Builder
+	b.Where("po.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	if filter.Status != "" {
+		b.Where("po.status = " + b.Arg(filter.Status))
+	}
+	if filter.SupplierID != uuid.Nil {
+		b.Where("po.supplier_id = " + b.Arg(pgtype.UUID{Bytes: filter.SupplierID, Valid: true}))
+	}
+	if page.Search != "" {
+		b.Where("s.name ILIKE " + b.Arg(pagination.ContainsPattern(page.Search)))
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*)"+from+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[PurchaseOrderResponse]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT po.*, s.name AS supplier_name"+from+page.Keyset(&b, "po.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[PurchaseOrderResponse]{}, err
+	}
+	list, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.GetPurchaseOrderRow])
+	if err != nil {
+		return pagination.Page[PurchaseOrderResponse]{}, err
+	}
+
+	rowsPage := pagination.NewPage(list, page, total, func(r db.GetPurchaseOrderRow) (string, uuid.UUID) {
+		if page.Sort.Name == "total_amount" {
+			return r.TotalAmount.String(), uuid.UUID(r.ID.Bytes)
+		}
+		return r.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(r.ID.Bytes)
+	})
+
+	orders := make([]PurchaseOrderResponse, 0, len(rowsPage.Data))
+	for _, r := range rowsPage.Data {
+		orders = append(orders, toResponse(r))
+	}
+
+	return pagination.Page[PurchaseOrderResponse]{
+		Data:       orders,
+		NextCursor: rowsPage.NextCursor,
+		Total:      rowsPage.Total,
+		Limit:      rowsPage.Limit,
+	}, nil
+}
+
+// GetDetails devolve o pedido com os itens e o histórico de recebimentos.
+func (s *Service) GetDetails(ctx context.Context, orgID, orderID uuid.UUID) (PurchaseOrderDetailsResponse, error) {
+	order, err := s.q.GetPurchaseOrder(ctx, db.GetPurchaseOrderParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PurchaseOrderDetailsResponse{}, ErrPurchaseOrderNotFound
+		}
+		return PurchaseOrderDetailsResponse{}, err
+	}
+
+	itemRows, err := s.q.ListPurchaseOrderItems(ctx, order.ID)
+	if err != nil {
+		return PurchaseOrderDetailsResponse{}, err
+	}
+
+	items := make([]PurchaseOrderItemResponse, 0, len(itemRows))
+	for _, r := range itemRows {
+		items = append(items, PurchaseOrderItemResponse{
+			ID:               uuid.UUID(r.ID.Bytes),
+			ProductID:        uuid.UUID(r.ProductID.Bytes),
+			ProductName:      r.ProductName,
+			SKU:              r.Sku.String,
+			QuantityOrdered:  r.QuantityOrdered,
+			QuantityReceived: r.QuantityReceived,
+			UnitCost:         r.UnitCost,
+			TotalCost:        r.UnitCost.Mul(int64(r.QuantityOrdered)),
+		})
+	}
+
+	receiptRows, err := s.q.ListPurchaseReceiptItems(ctx, order.ID)
+	if err != nil {
+		return PurchaseOrderDetailsResponse{}, err
+	}
+
+	// As linhas vêm ordenadas por recebimento; agrupa mantendo a ordem
+	receipts := []ReceiptResponse{}
+	for _, r := range receiptRows {
+		id := uuid.UUID(r.ReceiptID.Bytes)
+		if len(receipts) == 0 || receipts[len(receipts)-1].ID != id {
+			receipt := ReceiptResponse{ID: id, Note: r.Note.String, CreatedAt: r.CreatedAt.Time}
+			if r.ReceivedBy.Valid {
+				by := uuid.UUID(r.ReceivedBy.Bytes)
+				receipt.ReceivedBy = &by
+			}
+			receipts = append(receipts, receipt)
+		}
+		last := &receipts[len(receipts)-1]
+		last.Items = append(last.Items, ReceiptItemResponse{
+			ItemID:      uuid.UUID(r.PurchaseOrderItemID.Bytes),
+			ProductID:   uuid.UUID(r.ProductID.Bytes),
+			ProductName: r.ProductName,
+			Quantity:    r.Quantity,
+			UnitCost:    r.UnitCost,
+			TotalCost:   r.UnitCost.Mul(int64(r.Quantity)),
+		})
+	}
+
+	return PurchaseOrderDetailsResponse{
+		PurchaseOrderResponse: toResponse(order),
+		Items:                 items,
+		Receipts:              receipts,
+	}, nil
+}
+
+// checkRequest confere fornecedor e produtos na organização e devolve o total
+// do pedido.
+func (s *Service) checkRequest(ctx context.Context, qtx *db.Queries, orgID pgtype.UUID, req CreatePurchaseOrderRequest) (money.Money, error) {
+	if _, err := qtx.GetSupplier(ctx, db.GetSupplierParams{
+		ID:             pgtype.UUID{Bytes: req.SupplierID, Valid: true},
+		OrganizationID: orgID,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrSupplierNotFound
+		}
+		return 0, err
+	}
+
+	ids := make([]pgtype.UUID, 0, len(req.Items))
+	for _, item := range req.Items {
+		ids = append(ids, pgtype.UUID{Bytes: item.ProductID, Valid: true})
+	}
+	rows, err := qtx.GetProductsByIDs(ctx, db.GetProductsByIDsParams{OrganizationID: orgID, Ids: ids})
+	if err != nil {
+		return 0, err
+	}
+	catalog := make(map[uuid.UUID]db.GetProductsByIDsRow, len(rows))
+	for _, r := range rows {
+		catalog[uuid.UUID(r.ID.Bytes)] = r
+	}
+
+	var total money.Money
+	for _, item := range req.Items {
+		product, ok := catalog[item.ProductID]
+		if !ok {
+			return 0, fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if product.HasVariants {
+			return 0, fmt.Errorf("%w: %s", ErrProductHasVariants, product.Name)
+		}
+		if !product.IsActive {
+			return 0, fmt.Errorf("%w: %s", ErrProductInactive, product.Name)
+		}
+		total = total.Add(item.UnitCost.Mul(int64(item.Quantity)))
+	}
+	return total, nil
+}
+
+// transitionError distingue pedido inexistente de pedido no status errado
+// quando uma transição não atinge nenhuma linha.
+func (s *Service) transitionError(ctx context.Context, orgID, orderID uuid.UUID, statusErr error) error {
+	if _, err := s.q.GetPurchaseOrder(ctx, db.GetPurchaseOrderParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPurchaseOrderNotFound
+		}
+		return err
+	}
+	return statusErr
+}
+
+func lockOrder(ctx context.Context, qtx *db.Queries, orgID pgtype.UUID, orderID uuid.UUID) (db.PurchaseOrder, error) {
+	order, err := qtx.GetPurchaseOrderForUpdate(ctx, db.GetPurchaseOrderForUpdateParams{
+		ID:             pgtype.UUID{Bytes: orderID, Valid: true},
+		OrganizationID: orgID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.PurchaseOrder{}, ErrPurchaseOrderNotFound
+	}
+	return order, err
+}
+
+func createItems(ctx context.Context, qtx *db.Queries, orderID pgtype.UUID, items []PurchaseOrderItemDTO) error {
+	for _, item := range items {
+		if _, err := qtx.CreatePurchaseOrderItem(ctx, db.CreatePurchaseOrderItemParams{
+			PurchaseOrderID: orderID,
+			ProductID:       pgtype.UUID{Bytes: item.ProductID, Valid: true},
+			QuantityOrdered: int32(item.Quantity),
+			UnitCost:        item.UnitCost,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseDate(s string) (pgtype.Date, error) {
+	if s == "" {
+		return pgtype.Date{}, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return pgtype.Date{}, err
+	}
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
+
+func toResponse(r db.GetPurchaseOrderRow) PurchaseOrderResponse {
+	resp := PurchaseOrderResponse{
+		ID:           uuid.UUID(r.ID.Bytes),
+		SupplierID:   uuid.UUID(r.SupplierID.Bytes),
+		SupplierName: r.SupplierName,
+		Status:       r.Status,
+		TotalAmount:  r.TotalAmount,
+		Note:         r.Note.String,
+		CreatedAt:    r.CreatedAt.Time,
+		SentAt:       optionalTime(r.SentAt),
+		ReceivedAt:   optionalTime(r.ReceivedAt),
+		CanceledAt:   optionalTime(r.CanceledAt),
+	}
+	if r.ExpectedAt.Valid {
+		resp.ExpectedAt = r.ExpectedAt.Time.Format("2006-01-02")
+	}
+	return resp
+}
+
+func optionalTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
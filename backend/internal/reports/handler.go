@@ -0,0 +1,91 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/export"
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) ProductMargins(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	period, err := ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	products, err := h.service.ProductMargins(c.Context(), claims.OrgID, period, c.QueryInt("limit", 100))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(products)
+}
+
+func (h *Handler) OrderMargins(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	period, err := ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := pagination.FromQuery(c, OrderSortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	orders, err := h.service.OrderMargins(c.Context(), claims.OrgID, period, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(orders)
+}
+
+func (h *Handler) PeriodMargins(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	period, err := ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	report, err := h.service.PeriodMargins(c.Context(), claims.OrgID, period, c.Query("group", "day"))
+	if err != nil {
+		if errors.Is(err, ErrInvalidBucket) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
+// SalesPDF baixa o relatório de vendas do período em PDF.
+func (h *Handler) SalesPDF(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	period, err := ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return export.Stream(c, export.Filename("vendas", "pdf"), "application/pdf", func(ctx context.Context, w io.Writer) error {
+		return h.service.SalesReport(ctx, claims.OrgID, period, w)
+	})
+}
@@ -0,0 +1,166 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pdf"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var salesColumns = []pdf.Column{
+	{Title: "Data", Width: 78},
+	{Title: "Pedido", Width: 58},
+	{Title: "Cliente", Width: 160},
+	{Title: "Pagamento", Width: 75},
+	{Title: "Situação", Width: 62},
+	{Title: "Itens", Width: 30, Right: true},
+	{Title: "Total", Width: 60, Right: true},
+}
+
+var statusLabels = map[string]string{
+	"completed": "Concluído",
+	"canceled":  "Cancelado",
+	"pending":   "Pendente",
+}
+
+type salesRow struct {
+	ID            pgtype.UUID
+	CreatedAt     string
+	CustomerName  string
+	PaymentMethod string
+	Status        string
+	Items         int64
+	TotalAmount   money.Money
+}
+
+type salesTotal struct {
+	count int
+	total money.Money
+}
+
+// SalesReport grava em PDF os pedidos do período, um por linha e na ordem em
+// que foram feitos, com os totais ao final. As linhas vão para o PDF à medida
+// que saem do banco; os totais são somados no caminho.
+func (s *Service) SalesReport(ctx context.Context, orgID uuid.UUID, period Period, w io.Writer) error {
+	rows, err := s.db.Query(ctx, `SELECT o.id,
+		to_char(o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'), 'DD/MM/YYYY HH24:MI') AS created_at,
+		c.name AS customer_name, o.payment_method, o.status,
+		(SELECT COALESCE(SUM(oi.quantity), 0) FROM order_items oi WHERE oi.order_id = o.id)::BIGINT AS items,
+		o.total_amount
+		FROM orders o
+		JOIN customers c ON o.customer_id = c.id
+		LEFT JOIN organization_settings s ON s.organization_id = o.organization_id
+		WHERE o.organization_id = $1
+		  AND (o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE BETWEEN $2::DATE AND $3::DATE
+		ORDER BY o.created_at, o.id`,
+		pgtype.UUID{Bytes: orgID, Valid: true},
+		pgtype.Date{Time: period.From, Valid: true},
+		pgtype.Date{Time: period.To, Valid: true})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	report := pdf.New(w, "Relatório de vendas",
+		fmt.Sprintf("Período: %s a %s", period.From.Format("02/01/2006"), period.To.Format("02/01/2006")),
+		salesColumns)
+
+	byStatus := make(map[string]*salesTotal)
+	byPayment := make(map[string]*salesTotal)
+	for rows.Next() {
+		r, err := pgx.RowToStructByName[salesRow](rows)
+		if err != nil {
+			return err
+		}
+
+		status := statusLabels[r.Status]
+		if status == "" {
+			status = r.Status
+		}
+		if err := report.Row([]string{
+			r.CreatedAt,
+			uuid.UUID(r.ID.Bytes).String()[:8],
+			r.CustomerName,
+			r.PaymentMethod,
+			status,
+			strconv.FormatInt(r.Items, 10),
+			brl(r.TotalAmount),
+		}); err != nil {
+			return err
+		}
+
+		add(byStatus, r.Status, r.TotalAmount)
+		if r.Status == "completed" {
+			add(byPayment, r.PaymentMethod, r.TotalAmount)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	completed := byStatus["completed"]
+	if completed == nil {
+		completed = &salesTotal{}
+	}
+	summary := []string{fmt.Sprintf("Vendas concluídas: %d, total %s", completed.count, brl(completed.total))}
+	if completed.count > 0 {
+		summary = append(summary, "Ticket médio: "+brl(completed.total.MulRatio(1, int64(completed.count))))
+	}
+	if canceled := byStatus["canceled"]; canceled != nil {
+		summary = append(summary, fmt.Sprintf("Canceladas: %d, total %s", canceled.count, brl(canceled.total)))
+	}
+
+	methods := make([]string, 0, len(byPayment))
+	for m := range byPayment {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	for _, m := range methods {
+		summary = append(summary, fmt.Sprintf("%s: %d, total %s", m, byPayment[m].count, brl(byPayment[m].total)))
+	}
+
+	for i, line := range summary {
+		if err := report.Text(line, i == 0); err != nil {
+			return err
+		}
+	}
+	return report.Close()
+}
+
+func add(totals map[string]*salesTotal, key string, amount money.Money) {
+	t := totals[key]
+	if t == nil {
+		t = &salesTotal{}
+		totals[key] = t
+	}
+	t.count++
+	t.total = t.total.Add(amount)
+}
+
+// brl formata no padrão brasileiro: R$ 1.234,56.
+func brl(m money.Money) string {
+	cents := m.Cents()
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	digits := strconv.FormatInt(cents/100, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sign, b.String(), cents%100)
+}
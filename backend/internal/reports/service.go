@@ -0,0 +1,252 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/money"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	defaultPeriodDays = 30
+	maxProductRows    = 1000
+)
+
+var (
+	ErrInvalidDate   = errors.New("dates must be in YYYY-MM-DD format")
+	ErrInvalidPeriod = errors.New("from must not be after to")
+	ErrInvalidBucket = errors.New("group must be day, week or month")
+)
+
+// Period é o intervalo de datas (inclusivo) no fuso da organização.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParsePeriod lê from/to no formato YYYY-MM-DD; sem eles, usa os últimos 30
+// dias até hoje.
+func ParsePeriod(from, to string, now time.Time) (Period, error) {
+	p := Period{To: now}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return Period{}, ErrInvalidDate
+		}
+		p.To = t
+	}
+
+	p.From = p.To.AddDate(0, 0, -(defaultPeriodDays - 1))
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return Period{}, ErrInvalidDate
+		}
+		p.From = t
+	}
+
+	if p.From.After(p.To) {
+		return Period{}, ErrInvalidPeriod
+	}
+	return p, nil
+}
+
+// Margin é receita menos custo; MarginPercent é a margem sobre a receita.
+type Margin struct {
+	Revenue       money.Money `json:"revenue"`
+	Cost          money.Money `json:"cost"`
+	Margin        money.Money `json:"margin"`
+	MarginPercent float64     `json:"margin_percent"`
+}
+
+func newMargin(revenue, cost money.Money) Margin {
+	m := Margin{Revenue: revenue, Cost: cost, Margin: revenue.Sub(cost)}
+	if revenue != 0 {
+		m.MarginPercent = math.Round(float64(m.Margin)/float64(revenue)*10000) / 100
+	}
+	return m
+}
+
+type ProductMargin struct {
+	ProductID    uuid.UUID `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	SKU          string    `json:"sku"`
+	QuantitySold int64     `json:"quantity_sold"`
+	Margin
+}
+
+type OrderMargin struct {
+	OrderID      uuid.UUID `json:"order_id"`
+	CustomerName string    `json:"customer_name"`
+	CreatedAt    time.Time `json:"created_at"`
+	Margin
+}
+
+type PeriodMargin struct {
+	Period      string `json:"period"`
+	OrdersCount int32  `json:"orders_count"`
+	Margin
+}
+
+type PeriodReport struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Group   string         `json:"group"`
+	Total   Margin         `json:"total"`
+	Periods []PeriodMargin `json:"periods"`
+}
+
+type Service struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+// ProductMargins devolve os produtos vendidos no período, dos de maior margem
+// para os de menor.
+func (s *Service) ProductMargins(ctx context.Context, orgID uuid.UUID, period Period, limit int) ([]ProductMargin, error) {
+	if limit < 1 || limit > maxProductRows {
+		limit = maxProductRows
+	}
+
+	rows, err := s.q.GetProductMargins(ctx, db.GetProductMarginsParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		FromDate:       pgtype.Date{Time: period.From, Valid: true},
+		ToDate:         pgtype.Date{Time: period.To, Valid: true},
+		MaxRows:        int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ProductMargin, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, ProductMargin{
+			ProductID:    uuid.UUID(r.ProductID.Bytes),
+			ProductName:  r.ProductName,
+			SKU:          r.Sku.String,
+			QuantitySold: r.QuantitySold,
+			Margin:       newMargin(r.Revenue, r.Cost),
+		})
+	}
+	return result, nil
+}
+
+// OrderSortFields são as ordenações aceitas em GET /reports/margin/orders.
+var OrderSortFields = []pagination.SortField{
+	{Name: "created_at", Column: "o.created_at", Type: "timestamptz"},
+}
+
+type orderMarginRow struct {
+	ID           pgtype.UUID
+	CreatedAt    pgtype.Timestamptz
+	CustomerName string
+	Revenue      money.Money
+	Cost         money.Money
+}
+
+// OrderMargins devolve uma página das vendas concluídas no período com a
+// margem de cada uma.
+func (s *Service) OrderMargins(ctx context.Context, orgID uuid.UUID, period Period, page pagination.Params) (pagination.Page[OrderMargin], error) {
+	const from = ` FROM orders o
+		JOIN customers c ON o.customer_id = c.id
+		LEFT JOIN organization_settings s ON s.organization_id = o.organization_id`
+
+	var b pagination.Builder
+	b.Where("o.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	b.Where("o.status = 'completed'")
+	b.Where("(o.created_at AT TIME ZONE COALESCE(s.timezone, 'America/Sao_Paulo'))::DATE BETWEEN " +
+		b.Arg(pgtype.Date{Time: period.From, Valid: true}) + "::DATE AND " +
+		b.Arg(pgtype.Date{Time: period.To, Valid: true}) + "::DATE")
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*)"+from+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[OrderMargin]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT o.id, o.created_at, c.name AS customer_name, o.total_amount AS revenue,
+		COALESCE((SELECT SUM(oi.unit_cost * oi.quantity) FROM order_items oi WHERE oi.order_id = o.id), 0)::NUMERIC AS cost`+
+		from+page.Keyset(&b, "o.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[OrderMargin]{}, err
+	}
+	list, err := pgx.CollectRows(rows, pgx.RowToStructByName[orderMarginRow])
+	if err != nil {
+		return pagination.Page[OrderMargin]{}, err
+	}
+
+	rowsPage := pagination.NewPage(list, page, total, func(r orderMarginRow) (string, uuid.UUID) {
+		return r.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(r.ID.Bytes)
+	})
+
+	orders := make([]OrderMargin, 0, len(rowsPage.Data))
+	for _, r := range rowsPage.Data {
+		orders = append(orders, OrderMargin{
+			OrderID:      uuid.UUID(r.ID.Bytes),
+			CustomerName: r.CustomerName,
+			CreatedAt:    r.CreatedAt.Time,
+			Margin:       newMargin(r.Revenue, r.Cost),
+		})
+	}
+
+	return pagination.Page[OrderMargin]{
+		Data:       orders,
+		NextCursor: rowsPage.NextCursor,
+		Total:      rowsPage.Total,
+		Limit:      rowsPage.Limit,
+	}, nil
+}
+
+// PeriodMargins agrupa receita, custo e margem por dia, semana ou mês.
+func (s *Service) PeriodMargins(ctx context.Context, orgID uuid.UUID, period Period, group string) (PeriodReport, error) {
+	switch group {
+	case "day", "week", "month":
+	default:
+		return PeriodReport{}, ErrInvalidBucket
+	}
+
+	rows, err := s.q.GetMarginByPeriod(ctx, db.GetMarginByPeriodParams{
+		Bucket:         group,
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		FromDate:       pgtype.Date{Time: period.From, Valid: true},
+		ToDate:         pgtype.Date{Time: period.To, Valid: true},
+	})
+	if err != nil {
+		return PeriodReport{}, err
+	}
+
+	report := PeriodReport{
+		From:    period.From.Format("2006-01-02"),
+		To:      period.To.Format("2006-01-02"),
+		Group:   group,
+		Periods: make([]PeriodMargin, 0, len(rows)),
+	}
+
+	var revenue, cost money.Money
+	for _, r := range rows {
+		report.Periods = append(report.Periods, PeriodMargin{
+			Period:      r.Period,
+			OrdersCount: r.OrdersCount,
+			Margin:      newMargin(r.Revenue, r.Cost),
+		})
+		revenue = revenue.Add(r.Revenue)
+		cost = cost.Add(r.Cost)
+	}
+	report.Total = newMargin(revenue, cost)
+
+	return report, nil
+}
@@ -0,0 +1,44 @@
+package sheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+var bom = []byte("\ufeff")
+
+// NewCSVReader lê um CSV em UTF-8, com ou sem BOM. O separador é deduzido da
+// primeira linha com conteúdo: o Excel em português grava com ponto e vírgula.
+func NewCSVReader(r io.Reader) (Reader, error) {
+	br := bufio.NewReader(r)
+	if head, err := br.Peek(len(bom)); err == nil && bytes.Equal(head, bom) {
+		br.Discard(len(bom))
+	}
+
+	first, err := br.Peek(br.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	first = bytes.TrimLeft(first, "\r\n")
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = delimiter(first)
+	cr.FieldsPerRecord = -1
+	return cr, nil
+}
+
+// delimiter escolhe, entre os separadores usuais, o que mais aparece na linha.
+func delimiter(line []byte) rune {
+	best, count := ',', bytes.Count(line, []byte{','})
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(d))); n > count {
+			best, count = d, n
+		}
+	}
+	return best
+}
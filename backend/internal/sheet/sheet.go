@@ -0,0 +1,28 @@
+// Package sheet lê e grava planilhas CSV e XLSX linha a linha, sem depender
+// de bibliotecas de terceiros.
+package sheet
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format; use .csv or .xlsx")
+
+// Reader devolve uma linha por chamada e io.EOF depois da última.
+type Reader interface {
+	Read() ([]string, error)
+}
+
+// Open escolhe o leitor pela extensão do arquivo.
+func Open(name string, r io.ReaderAt, size int64) (Reader, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		return NewCSVReader(io.NewSectionReader(r, 0, size))
+	case ".xlsx":
+		return NewXLSXReader(r, size)
+	}
+	return nil, ErrUnsupportedFormat
+}
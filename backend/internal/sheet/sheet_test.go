@@ -0,0 +1,245 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, r Reader) [][]string {
+	t.Helper()
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]string
+	}{
+		{
+			name:  "comma",
+			input: "name,price\nCaneta,\"1,50\"\n",
+			want:  [][]string{{"name", "price"}, {"Caneta", "1,50"}},
+		},
+		{
+			name:  "semicolon with BOM",
+			input: "\ufeffnome;preço\nCaneta;1,50\nLápis\n",
+			want:  [][]string{{"nome", "preço"}, {"Caneta", "1,50"}, {"Lápis"}},
+		},
+		{
+			name:  "tab",
+			input: "a\tb\n1\t2",
+			want:  [][]string{{"a", "b"}, {"1", "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewCSVReader(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readAll(t, r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// singleSheet monta uma pasta de trabalho mínima com uma aba cujo sheetData
+// é rows.
+func singleSheet(t *testing.T, rows string) []byte {
+	t.Helper()
+	return buildXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Dados" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`,
+	})
+}
+
+func TestXLSXReader(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Produtos" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Target="styles.xml"/>
+			<Relationship Id="rId3" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>name</t></si><si><t>price</t></si><si><r><t>Cane</t></r><r><t>ta</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>1.5</v></c></row>
+			<row r="4"><c r="B4" t="inlineStr"><is><t>Lápis</t></is></c><c r="C4" t="b"><v>1</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	r, err := NewXLSXReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"name", "price"},
+		{"Caneta", "", "1.5"},
+		{},
+		{"", "Lápis", "TRUE"},
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("dados.ods", strings.NewReader(""), 0); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("got %v, want ErrUnsupportedFormat", err)
+	}
+	if _, err := Open("dados.xlsx", strings.NewReader("not a zip"), 9); !errors.Is(err, ErrInvalidXLSX) {
+		t.Errorf("got %v, want ErrInvalidXLSX", err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "Z9": 25, "AA1": 26, "AB12": 27} {
+		if got := columnIndex(ref); got != want {
+			t.Errorf("columnIndex(%q) = %d, want %d", ref, got, want)
+		}
+	}
+}
+
+func TestColumnIndexInvalid(t *testing.T) {
+	for _, ref := range []string{"", "1", "a1", "aA1", "A1b", "XFE1", "ZZZZZZZZZZ"} {
+		if got := columnIndex(ref); got != -1 {
+			t.Errorf("columnIndex(%q) = %d, want -1", ref, got)
+		}
+	}
+	if got := columnIndex("XFD1"); got != maxColumn {
+		t.Errorf("columnIndex(%q) = %d, want %d", "XFD1", got, maxColumn)
+	}
+}
+
+func TestXLSXReaderInvalidCellRef(t *testing.T) {
+	for _, ref := range []string{"1", "a1", "XFE1", "ZZZZZZZZZZ"} {
+		t.Run(ref, func(t *testing.T) {
+			data := singleSheet(t, `<row r="1"><c r="`+ref+`"><v>1</v></c></row>`)
+			r, err := NewXLSXReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Read(); !errors.Is(err, ErrInvalidXLSX) {
+				t.Errorf("got %v, want ErrInvalidXLSX", err)
+			}
+		})
+	}
+}
+
+func TestXLSXReaderInvalidRowNumber(t *testing.T) {
+	for _, num := range []string{"0", "-1", "x", "1048577", "2000000000"} {
+		t.Run(num, func(t *testing.T) {
+			data := singleSheet(t, `<row r="`+num+`"><c><v>1</v></c></row>`)
+			r, err := NewXLSXReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Read(); !errors.Is(err, ErrInvalidXLSX) {
+				t.Errorf("got %v, want ErrInvalidXLSX", err)
+			}
+		})
+	}
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]Cell{
+		{Text("name"), Text("price"), Text("notes")},
+		{Text("Caneta <azul> & cia"), Number("1.50"), Text("")},
+		{Text(" 007"), Number("-2"), Text("linha\nquebrada")},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "Produtos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewXLSXReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"name", "price", "notes"},
+		{"Caneta <azul> & cia", "1.50"},
+		{" 007", "-2", "linha\nquebrada"},
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]Cell{Text("=SUM(A1)"), Number("-5"), Text("a,b")})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "\ufeff'=SUM(A1),-5,\"a,b\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+		if back := columnIndex(want + "1"); back != i {
+			t.Errorf("columnIndex(%q) = %d, want %d", want, back, i)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package sheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedExport = errors.New("format must be csv or xlsx")
+
+// Cell é uma célula a gravar. Number faz o XLSX guardar o valor como número
+// (Value em notação decimal com ponto); no CSV não faz diferença.
+type Cell struct {
+	Value  string
+	Number bool
+}
+
+func Text(s string) Cell {
+	return Cell{Value: s}
+}
+
+func Number(s string) Cell {
+	return Cell{Value: s, Number: true}
+}
+
+// Writer grava uma linha por chamada; Close termina o arquivo.
+type Writer interface {
+	Write(row []Cell) error
+	Close() error
+}
+
+// NewWriter cria o gravador do formato pedido. name é o nome da aba no XLSX.
+func NewWriter(format string, w io.Writer, name string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w)
+	case FormatXLSX:
+		return NewXLSXWriter(w, name)
+	}
+	return nil, ErrUnsupportedExport
+}
+
+// ContentType devolve o MIME do formato.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+// NewCSVWriter grava CSV com BOM, para o Excel reconhecer o UTF-8. Textos que
+// começam como fórmula ganham um apóstrofo na frente, para não serem
+// executados ao abrir a planilha.
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	if _, err := w.Write(bom); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) Write(row []Cell) error {
+	c.record = c.record[:0]
+	for _, cell := range row {
+		v := cell.Value
+		if !cell.Number && v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+			v = "'" + v
+		}
+		c.record = append(c.record, v)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+	buf   []byte
+}
+
+// NewXLSXWriter grava uma pasta de trabalho com uma única aba. O zip é
+// escrito em streaming, então o arquivo nunca fica inteiro em memória.
+func NewXLSXWriter(w io.Writer, name string) (Writer, error) {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(name))
+
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + escaped.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xmlHeader+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{zw: zw, sheet: sheet}, nil
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+func (x *xlsxWriter) Write(row []Cell) error {
+	x.row++
+	num := strconv.Itoa(x.row)
+
+	b := x.buf[:0]
+	b = append(b, `<row r="`+num+`">`...)
+	for i, cell := range row {
+		if cell.Value == "" {
+			continue
+		}
+		ref := columnName(i) + num
+		if cell.Number {
+			b = append(b, `<c r="`+ref+`"><v>`...)
+			b = appendEscaped(b, cell.Value)
+			b = append(b, `</v></c>`...)
+			continue
+		}
+		b = append(b, `<c r="`+ref+`" t="inlineStr"><is><t xml:space="preserve">`...)
+		b = appendEscaped(b, cell.Value)
+		b = append(b, `</t></is></c>`...)
+	}
+	b = append(b, `</row>`...)
+	x.buf = b
+
+	_, err := x.sheet.Write(b)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+func appendEscaped(b []byte, s string) []byte {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return append(b, sb.String()...)
+}
+
+// columnName é o inverso de columnIndex: 0 vira "A", 27 vira "AB".
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
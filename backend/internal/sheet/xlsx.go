@@ -0,0 +1,303 @@
+package sheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidXLSX = errors.New("invalid xlsx file")
+
+const (
+	// maxRow é o número da última linha que o Excel admite.
+	maxRow = 1048576
+	// maxColumn é o índice da última coluna que o Excel admite (XFD).
+	maxColumn = 16383
+)
+
+// xlsxReader percorre a primeira aba da pasta de trabalho em streaming; só a
+// tabela de textos compartilhados fica inteira em memória.
+type xlsxReader struct {
+	file    io.Closer
+	dec     *xml.Decoder
+	strings []string
+	next    int // número (base 1) da próxima linha a devolver
+	pending []string
+	pendRow int
+	done    bool
+}
+
+// NewXLSXReader abre a primeira aba de um arquivo XLSX. Linhas vazias no meio
+// da aba voltam como linhas sem células, para que a numeração bata com a do
+// Excel.
+func NewXLSXReader(r io.ReaderAt, size int64) (Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	sheetPath, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+
+	shared, err := sharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidXLSX, sheetPath)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxReader{file: rc, dec: xml.NewDecoder(rc), strings: shared, next: 1}, nil
+}
+
+func (x *xlsxReader) Read() ([]string, error) {
+	for {
+		if x.pending != nil {
+			if x.next < x.pendRow {
+				x.next++
+				return []string{}, nil
+			}
+			row := x.pending
+			x.pending = nil
+			x.next++
+			return row, nil
+		}
+		if x.done {
+			return nil, io.EOF
+		}
+
+		num, row, err := x.readRow()
+		if err == io.EOF {
+			x.done = true
+			x.file.Close()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if num < x.next {
+			num = x.next
+		}
+		x.pending, x.pendRow = row, num
+	}
+}
+
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+// readRow avança até o próximo <row> e devolve seu número e suas células.
+func (x *xlsxReader) readRow() (int, []string, error) {
+	for {
+		tok, err := x.dec.Token()
+		if err != nil {
+			return 0, nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		num := 0
+		for _, a := range start.Attr {
+			if a.Name.Local != "r" {
+				continue
+			}
+			num, err = strconv.Atoi(a.Value)
+			if err != nil || num < 1 || num > maxRow {
+				return 0, nil, fmt.Errorf("%w: bad row number %q", ErrInvalidXLSX, a.Value)
+			}
+		}
+
+		row := []string{}
+		for {
+			tok, err := x.dec.Token()
+			if err != nil {
+				return 0, nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+			}
+			if end, ok := tok.(xml.EndElement); ok && end.Name.Local == "row" {
+				return num, row, nil
+			}
+			start, ok := tok.(xml.StartElement)
+			if !ok || start.Name.Local != "c" {
+				continue
+			}
+
+			var c xlsxCell
+			if err := x.dec.DecodeElement(&c, &start); err != nil {
+				return 0, nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+			}
+			col := len(row)
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			if col < 0 || col > maxColumn {
+				return 0, nil, fmt.Errorf("%w: bad cell reference %q", ErrInvalidXLSX, c.Ref)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			row[col] = x.cellValue(c)
+		}
+	}
+}
+
+func (x *xlsxReader) cellValue(c xlsxCell) string {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(x.strings) {
+			return ""
+		}
+		return x.strings[i]
+	case "inlineStr":
+		if len(c.Inline.Runs) == 0 {
+			return c.Inline.Text
+		}
+		var b strings.Builder
+		for _, r := range c.Inline.Runs {
+			b.WriteString(r.Text)
+		}
+		return b.String()
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return c.Value
+}
+
+// columnIndex converte a referência da célula (ex.: "AB12") no índice da
+// coluna, começando em zero. Devolve -1 se a referência não começar por letras
+// maiúsculas seguidas só de dígitos ou passar da última coluna.
+func columnIndex(ref string) int {
+	n, i := 0, 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		n = n*26 + int(ref[i]-'A'+1)
+		if n-1 > maxColumn {
+			return -1
+		}
+	}
+	if i == 0 {
+		return -1
+	}
+	for _, r := range ref[i:] {
+		if r < '0' || r > '9' {
+			return -1
+		}
+	}
+	return n - 1
+}
+
+// firstSheet segue workbook.xml e seus relacionamentos até o arquivo da
+// primeira aba.
+func firstSheet(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeFile(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("%w: workbook has no sheets", ErrInvalidXLSX)
+	}
+
+	var rels struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeFile(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	for _, r := range rels.Items {
+		if r.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(r.Target, "/") {
+			return strings.TrimPrefix(r.Target, "/"), nil
+		}
+		return path.Join("xl", r.Target), nil
+	}
+	return "", fmt.Errorf("%w: first sheet not found", ErrInvalidXLSX)
+}
+
+// sharedStrings carrega a tabela de textos; planilhas só com números ou textos
+// embutidos não a têm.
+func sharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodeFile(f, &sst); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		if len(si.Runs) == 0 {
+			result[i] = si.Text
+			continue
+		}
+		var b strings.Builder
+		for _, r := range si.Runs {
+			b.WriteString(r.Text)
+		}
+		result[i] = b.String()
+	}
+	return result, nil
+}
+
+func decodeFile(f *zip.File, v any) error {
+	if f == nil {
+		return fmt.Errorf("%w: missing workbook part", ErrInvalidXLSX)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package sku gera SKUs a partir do padrão configurado pela organização.
+//
+// O padrão mistura texto fixo com os marcadores {SEQ} (sequencial da
+// organização, opcionalmente com largura mínima: {SEQ:5} vira 00042), {CAT}
+// (três primeiras letras ou dígitos da categoria, GER sem categoria), {YYYY} e
+// {YY} (ano corrente). Ex.: "CAM-{CAT}-{SEQ:4}" gera "CAM-ROU-0042".
+package sku
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxLength é o tamanho da coluna products.sku.
+const MaxLength = 50
+
+var (
+	ErrInvalidPattern = errors.New("sku pattern must contain {SEQ} once and only {SEQ[:width]}, {CAT}, {YYYY} and {YY} placeholders")
+	ErrTooLong        = errors.New("generated sku is longer than 50 characters")
+)
+
+// ValidatePattern confere a sintaxe do padrão.
+func ValidatePattern(pattern string) error {
+	_, err := Format(pattern, 1, "", time.Time{})
+	return err
+}
+
+// Format aplica o padrão ao sequencial, ao nome da categoria e à data.
+func Format(pattern string, seq int64, category string, now time.Time) (string, error) {
+	var b strings.Builder
+	seqCount := 0
+
+	rest := pattern
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.ContainsRune(rest, '}') {
+				return "", ErrInvalidPattern
+			}
+			b.WriteString(rest)
+			break
+		}
+		if strings.ContainsRune(rest[:open], '}') {
+			return "", ErrInvalidPattern
+		}
+		b.WriteString(rest[:open])
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return "", ErrInvalidPattern
+		}
+		token := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		switch {
+		case token == "SEQ" || strings.HasPrefix(token, "SEQ:"):
+			seqCount++
+			width := 0
+			if w, ok := strings.CutPrefix(token, "SEQ:"); ok {
+				n, err := strconv.Atoi(w)
+				if err != nil || n < 1 || n > 12 {
+					return "", ErrInvalidPattern
+				}
+				width = n
+			}
+			fmt.Fprintf(&b, "%0*d", width, seq)
+		case token == "CAT":
+			b.WriteString(categoryCode(category))
+		case token == "YYYY":
+			fmt.Fprintf(&b, "%04d", now.Year())
+		case token == "YY":
+			fmt.Fprintf(&b, "%02d", now.Year()%100)
+		default:
+			return "", ErrInvalidPattern
+		}
+	}
+
+	if seqCount != 1 {
+		return "", ErrInvalidPattern
+	}
+	if len([]rune(b.String())) > MaxLength {
+		return "", ErrTooLong
+	}
+	return b.String(), nil
+}
+
+func categoryCode(name string) string {
+	var code []rune
+	for _, r := range strings.ToUpper(name) {
+		if len(code) == 3 {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			code = append(code, r)
+		}
+	}
+	if len(code) == 0 {
+		return "GER"
+	}
+	return string(code)
+}
@@ -0,0 +1,64 @@
+package sku
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern  string
+		seq      int64
+		category string
+		want     string
+	}{
+		{"PRD-{SEQ}", 42, "", "PRD-42"},
+		{"PRD-{SEQ:5}", 42, "", "PRD-00042"},
+		{"{CAT}-{SEQ:3}", 7, "Roupas", "ROU-007"},
+		{"{CAT}-{SEQ:3}", 7, "", "GER-007"},
+		{"{CAT}{SEQ}", 1, "é 1", "É11"},
+		{"{YYYY}/{SEQ:2}", 3, "", "2026/03"},
+		{"{YY}{SEQ:4}", 12345, "", "2612345"},
+	}
+
+	for _, tt := range tests {
+		got, err := Format(tt.pattern, tt.seq, tt.category, now)
+		if err != nil {
+			t.Errorf("Format(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Format(%q, %d, %q) = %q, want %q", tt.pattern, tt.seq, tt.category, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	invalid := []string{
+		"",
+		"PRD",
+		"{SEQ}-{SEQ}",
+		"{SEQ:0}",
+		"{SEQ:x}",
+		"{FOO}-{SEQ}",
+		"{SEQ",
+		"SEQ}",
+		"{SEQ:12}-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	}
+	for _, p := range invalid {
+		if err := ValidatePattern(p); err == nil {
+			t.Errorf("ValidatePattern(%q) = nil, want error", p)
+		}
+	}
+
+	if err := ValidatePattern("CAM-{CAT}-{SEQ:4}"); err != nil {
+		t.Errorf("valid pattern: %v", err)
+	}
+
+	if _, err := Format("{SEQ:12}-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", 1, "", time.Time{}); !errors.Is(err, ErrTooLong) {
+		t.Errorf("long pattern error = %v, want ErrTooLong", err)
+	}
+}
@@ -0,0 +1,125 @@
+package suppliers
+
+import (
+	"errors"
+
+	"github.com/dcastro0/aether-backend/internal/middleware"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	var req CreateSupplierRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	supplier, err := h.service.Create(c.Context(), claims.OrgID, req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(supplier)
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	page, err := pagination.FromQuery(c, SortFields, "created_at", true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	suppliers, err := h.service.List(c.Context(), claims.OrgID, page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(suppliers)
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	supplierID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	supplier, err := h.service.Get(c.Context(), claims.OrgID, supplierID)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(supplier)
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	supplierID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req CreateSupplierRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	supplier, err := h.service.Update(c.Context(), claims.OrgID, supplierID, req)
+	if err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(supplier)
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	claims := middleware.GetClaims(c)
+
+	supplierID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.Delete(c.Context(), claims.OrgID, supplierID); err != nil {
+		return c.Status(errorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrSupplierNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrSupplierInUse):
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
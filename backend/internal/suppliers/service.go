@@ -0,0 +1,134 @@
+package suppliers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dcastro0/aether-backend/internal/db"
+	"github.com/dcastro0/aether-backend/internal/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CreateSupplierRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"omitempty,email"`
+	Phone    string `json:"phone"`
+	Document string `json:"document"`
+	Type     string `json:"type" validate:"oneof=individual company"`
+}
+
+var (
+	ErrSupplierNotFound = errors.New("supplier not found")
+	ErrSupplierInUse    = errors.New("supplier has purchase orders")
+)
+
+type Service struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewService(pool *pgxpool.Pool) *Service {
+	return &Service{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, orgID uuid.UUID, req CreateSupplierRequest) (db.Supplier, error) {
+	return s.q.CreateSupplier(ctx, db.CreateSupplierParams{
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Name:           req.Name,
+		Email:          pgtype.Text{String: req.Email, Valid: req.Email != ""},
+		Phone:          pgtype.Text{String: req.Phone, Valid: req.Phone != ""},
+		Document:       pgtype.Text{String: req.Document, Valid: req.Document != ""},
+		Type:           pgtype.Text{String: req.Type, Valid: req.Type != ""},
+	})
+}
+
+// SortFields são as ordenações aceitas em GET /suppliers.
+var SortFields = []pagination.SortField{
+	{Name: "created_at", Column: "s.created_at", Type: "timestamptz"},
+	{Name: "name", Column: "s.name", Type: "text"},
+}
+
+// List devolve uma página dos fornecedores da organização. A busca procura no
+// nome, e-mail e documento.
+func (s *Service) List(ctx context.Context, orgID uuid.UUID, page pagination.Params) (pagination.Page[db.Supplier], error) {
+	var b pagination.Builder
+	b.Where("s.organization_id = " + b.Arg(pgtype.UUID{Bytes: orgID, Valid: true}))
+	if page.Search != "" {
+		pattern := b.Arg(pagination.ContainsPattern(page.Search))
+		b.Where("(s.name ILIKE " + pattern + " OR s.email ILIKE " + pattern + " OR s.document ILIKE " + pattern + ")")
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM suppliers s"+b.WhereSQL(), b.Args()...).Scan(&total); err != nil {
+		return pagination.Page[db.Supplier]{}, err
+	}
+
+	rows, err := s.db.Query(ctx, "SELECT s.* FROM suppliers s"+page.Keyset(&b, "s.id"), b.Args()...)
+	if err != nil {
+		return pagination.Page[db.Supplier]{}, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.Supplier])
+	if err != nil {
+		return pagination.Page[db.Supplier]{}, err
+	}
+
+	return pagination.NewPage(items, page, total, func(sp db.Supplier) (string, uuid.UUID) {
+		if page.Sort.Name == "name" {
+			return sp.Name, uuid.UUID(sp.ID.Bytes)
+		}
+		return sp.CreatedAt.Time.Format(time.RFC3339Nano), uuid.UUID(sp.ID.Bytes)
+	}), nil
+}
+
+func (s *Service) Get(ctx context.Context, orgID uuid.UUID, supplierID uuid.UUID) (db.Supplier, error) {
+	supplier, err := s.q.GetSupplier(ctx, db.GetSupplierParams{
+		ID:             pgtype.UUID{Bytes: supplierID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Supplier{}, ErrSupplierNotFound
+	}
+	return supplier, err
+}
+
+func (s *Service) Update(ctx context.Context, orgID uuid.UUID, supplierID uuid.UUID, req CreateSupplierRequest) (db.Supplier, error) {
+	supplier, err := s.q.UpdateSupplier(ctx, db.UpdateSupplierParams{
+		ID:             pgtype.UUID{Bytes: supplierID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+		Name:           req.Name,
+		Email:          pgtype.Text{String: req.Email, Valid: req.Email != ""},
+		Phone:          pgtype.Text{String: req.Phone, Valid: req.Phone != ""},
+		Document:       pgtype.Text{String: req.Document, Valid: req.Document != ""},
+		Type:           pgtype.Text{String: req.Type, Valid: req.Type != ""},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.Supplier{}, ErrSupplierNotFound
+	}
+	return supplier, err
+}
+
+// Delete remove o fornecedor. Fornecedores com pedidos de compra ficam, para
+// preservar o histórico.
+func (s *Service) Delete(ctx context.Context, orgID uuid.UUID, supplierID uuid.UUID) error {
+	n, err := s.q.DeleteSupplier(ctx, db.DeleteSupplierParams{
+		ID:             pgtype.UUID{Bytes: supplierID, Valid: true},
+		OrganizationID: pgtype.UUID{Bytes: orgID, Valid: true},
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return ErrSupplierInUse
+		}
+		return err
+	}
+	if n == 0 {
+		return ErrSupplierNotFound
+	}
+	return nil
+}
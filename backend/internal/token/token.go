@@ -0,0 +1,26 @@
+// Package token gera tokens opacos (refresh tokens, convites) e o hash com que
+// eles são guardados no banco.
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Generate devolve 256 bits aleatórios em base64 sem padding, seguro para URL.
+func Generate() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Hash usa SHA-256 (e não bcrypt) porque o hash precisa ser determinístico
+// para a busca por índice; o token já tem 256 bits de entropia.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
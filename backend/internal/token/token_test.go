@@ -0,0 +1,23 @@
+package token
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	a, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Generate()
+	if len(a) != 43 || a == b {
+		t.Errorf("Generate = %q, %q; want distinct 43-char tokens", a, b)
+	}
+}
+
+func TestHash(t *testing.T) {
+	if Hash("abc") != Hash("abc") || Hash("abc") == Hash("abd") {
+		t.Error("Hash must be deterministic and distinguish inputs")
+	}
+	if got := len(Hash("abc")); got != 64 {
+		t.Errorf("len(Hash) = %d, want 64", got)
+	}
+}
@@ -6,13 +6,21 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/dcastro0/aether-backend/internal/auth"
+	"github.com/dcastro0/aether-backend/internal/categories"
+	"github.com/dcastro0/aether-backend/internal/cep"
 	"github.com/dcastro0/aether-backend/internal/customers"
 	"github.com/dcastro0/aether-backend/internal/dashboard"
+	"github.com/dcastro0/aether-backend/internal/db"
 	"github.com/dcastro0/aether-backend/internal/middleware"
 	"github.com/dcastro0/aether-backend/internal/orders"
+	"github.com/dcastro0/aether-backend/internal/organizations"
 	"github.com/dcastro0/aether-backend/internal/products"
+	"github.com/dcastro0/aether-backend/internal/purchases"
+	"github.com/dcastro0/aether-backend/internal/reports"
+	"github.com/dcastro0/aether-backend/internal/suppliers"
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -46,15 +54,33 @@ func main() {
 	}
 	log.Info().Msg("Connected to PostgreSQL")
 
+	// Consulta de CEP sem rede, numa base local. Sem CEP_DATASET a base fica
+	// vazia: os endereços continuam validados pela faixa de CEP da UF, mas o
+	// preenchimento automático não encontra nada.
+	cepLookup := cep.NewLocal()
+	if path := os.Getenv("CEP_DATASET"); path != "" {
+		if err := cepLookup.LoadFile(path); err != nil {
+			log.Fatal().Err(err).Msg("Unable to load CEP dataset")
+		}
+		log.Info().Int("ceps", cepLookup.Len()).Msg("Loaded CEP dataset")
+	}
+
 	authHandler := auth.NewHandler(auth.NewService(dbPool))
 	productHandler := products.NewHandler(products.NewService(dbPool))
-	customerHandler := customers.NewHandler(customers.NewService(dbPool))
+	categoryHandler := categories.NewHandler(categories.NewService(dbPool))
+	customerHandler := customers.NewHandler(customers.NewService(dbPool, cepLookup))
 	orderHandler := orders.NewHandler(orders.NewService(dbPool))
+	supplierHandler := suppliers.NewHandler(suppliers.NewService(dbPool))
+	purchaseHandler := purchases.NewHandler(purchases.NewService(dbPool))
+	reportHandler := reports.NewHandler(reports.NewService(dbPool))
 	dashboardHandler := dashboard.NewHandler(dashboard.NewService(dbPool))
+	organizationHandler := organizations.NewHandler(organizations.NewService(dbPool))
 
 	app := fiber.New(fiber.Config{
 		AppName:       "Aether ERP",
 		CaseSensitive: true,
+		// Planilhas de importação passam do limite padrão de 4 MB
+		BodyLimit: 32 * 1024 * 1024,
 	})
 
 	app.Use(logger.New())
@@ -76,6 +102,13 @@ func main() {
 	authGroup := api.Group("/auth")
 	authGroup.Post("/register", authHandler.Register)
 	authGroup.Post("/login", authHandler.Login)
+	authGroup.Post("/refresh", authHandler.Refresh)
+	authGroup.Post("/logout", authHandler.Logout)
+
+	invitationsGroup := api.Group("/invitations")
+	invitationsGroup.Get("/:token", organizationHandler.PreviewInvitation)
+	invitationsGroup.Post("/:token/accept", organizationHandler.AcceptInvitation)
+	invitationsGroup.Post("/:token/decline", organizationHandler.DeclineInvitation)
 
 	jwtMiddleware := jwtware.New(jwtware.Config{
 		SigningKey: jwtware.SigningKey{Key: []byte(os.Getenv("JWT_SECRET"))},
@@ -83,27 +116,115 @@ func main() {
 
 	protected := api.Group("/protected", jwtMiddleware, middleware.ExtractOrgClaims)
 
-	profileGroup := protected.Group("/profile")
+	viewer := middleware.RequireRole(db.UserRoleViewer)
+	editor := middleware.RequireRole(db.UserRoleEditor)
+	admin := middleware.RequireRole(db.UserRoleAdmin)
+
+	profileGroup := protected.Group("/profile", viewer)
 	profileGroup.Put("/", authHandler.UpdateProfile)
 	profileGroup.Put("/password", authHandler.UpdatePassword)
 
-	productsGroup := protected.Group("/products")
-	productsGroup.Post("/", productHandler.Create)
+	organizationsGroup := protected.Group("/organizations", viewer)
+	organizationsGroup.Get("/", authHandler.ListOrganizations)
+	organizationsGroup.Post("/:id/switch", authHandler.SwitchOrganization)
+
+	organizationGroup := protected.Group("/organization", viewer)
+	organizationGroup.Get("/", organizationHandler.GetOrganization)
+	organizationGroup.Put("/", admin, organizationHandler.UpdateOrganization)
+
+	membersGroup := protected.Group("/members", viewer)
+	membersGroup.Get("/", organizationHandler.ListMembers)
+	membersGroup.Get("/invitations", admin, organizationHandler.ListInvitations)
+	membersGroup.Post("/invitations", admin, organizationHandler.CreateInvitation)
+	membersGroup.Delete("/invitations/:id", admin, organizationHandler.RevokeInvitation)
+	membersGroup.Put("/:userId/role", admin, organizationHandler.UpdateMemberRole)
+	membersGroup.Delete("/:userId", organizationHandler.RemoveMember)
+
+	productsGroup := protected.Group("/products", viewer)
+	productsGroup.Post("/", editor, productHandler.Create)
 	productsGroup.Get("/", productHandler.List)
 	productsGroup.Get("/metrics", productHandler.GetMetrics)
-
-	customersGroup := protected.Group("/customers")
-	customersGroup.Post("/", customerHandler.Create)
+	productsGroup.Get("/low-stock", productHandler.ListLowStock)
+	productsGroup.Get("/lookup", productHandler.Lookup)
+	productsGroup.Post("/import", editor, productHandler.Import)
+	productsGroup.Get("/export", productHandler.Export)
+	productsGroup.Get("/:id", productHandler.Get)
+	productsGroup.Get("/:id/movements", productHandler.ListMovements)
+	productsGroup.Put("/:id", editor, productHandler.Update)
+	productsGroup.Post("/:id/activate", editor, productHandler.Activate)
+	productsGroup.Post("/:id/deactivate", editor, productHandler.Deactivate)
+	productsGroup.Delete("/:id", admin, productHandler.Delete)
+	productsGroup.Post("/:id/adjustments", editor, productHandler.Adjust)
+	productsGroup.Get("/:id/variants", productHandler.ListVariants)
+	productsGroup.Put("/:id/options", editor, productHandler.SetOptions)
+	productsGroup.Put("/:id/variants/:variantId", editor, productHandler.UpdateVariant)
+	productsGroup.Get("/:id/barcodes", productHandler.ListBarcodes)
+	productsGroup.Post("/:id/barcodes", editor, productHandler.AddBarcode)
+	productsGroup.Delete("/:id/barcodes/:code", editor, productHandler.RemoveBarcode)
+
+	stockCounts := protected.Group("/stock-counts", viewer)
+	stockCounts.Post("/", editor, productHandler.OpenStockCount)
+	stockCounts.Get("/", productHandler.ListStockCounts)
+	stockCounts.Get("/:id", productHandler.GetStockCount)
+	stockCounts.Put("/:id/items", editor, productHandler.SubmitStockCount)
+	stockCounts.Post("/:id/post", admin, productHandler.PostStockCount)
+	stockCounts.Post("/:id/cancel", editor, productHandler.CancelStockCount)
+
+	customersGroup := protected.Group("/customers", viewer)
+	customersGroup.Post("/", editor, customerHandler.Create)
 	customersGroup.Get("/", customerHandler.List)
-	customersGroup.Put("/:id", customerHandler.Update)
-	customersGroup.Delete("/:id", customerHandler.Delete)
-
-	ordersGroup := protected.Group("/orders")
-	ordersGroup.Post("/", orderHandler.Create)
+	customersGroup.Post("/import", editor, customerHandler.Import)
+	customersGroup.Get("/export", customerHandler.Export)
+	customersGroup.Get("/cep/:cep", customerHandler.LookupCEP)
+	customersGroup.Put("/:id", editor, customerHandler.Update)
+	customersGroup.Post("/:id/merge", admin, customerHandler.Merge)
+	customersGroup.Delete("/:id", admin, customerHandler.Delete)
+	customersGroup.Post("/:id/archive", editor, customerHandler.Archive)
+	customersGroup.Post("/:id/restore", editor, customerHandler.Restore)
+	customersGroup.Post("/:id/anonymize", admin, customerHandler.Anonymize)
+	customersGroup.Get("/:id/summary", customerHandler.Summary)
+	customersGroup.Get("/:id/addresses", customerHandler.ListAddresses)
+	customersGroup.Post("/:id/addresses", editor, customerHandler.CreateAddress)
+	customersGroup.Put("/:id/addresses/:addressId", editor, customerHandler.UpdateAddress)
+	customersGroup.Delete("/:id/addresses/:addressId", editor, customerHandler.DeleteAddress)
+
+	ordersGroup := protected.Group("/orders", viewer)
+	ordersGroup.Post("/", editor, orderHandler.Create)
 	ordersGroup.Get("/", orderHandler.List)
+	ordersGroup.Get("/export", orderHandler.Export)
 	ordersGroup.Get("/:id", orderHandler.GetDetails)
-
-	dashboardGroup := protected.Group("/dashboard")
+	ordersGroup.Post("/:id/cancel", editor, orderHandler.Cancel)
+
+	categoriesGroup := protected.Group("/categories", viewer)
+	categoriesGroup.Post("/", editor, categoryHandler.Create)
+	categoriesGroup.Get("/", categoryHandler.Tree)
+	categoriesGroup.Put("/:id", editor, categoryHandler.Update)
+	categoriesGroup.Delete("/:id", admin, categoryHandler.Delete)
+
+	suppliersGroup := protected.Group("/suppliers", viewer)
+	suppliersGroup.Post("/", editor, supplierHandler.Create)
+	suppliersGroup.Get("/", supplierHandler.List)
+	suppliersGroup.Get("/:id", supplierHandler.Get)
+	suppliersGroup.Put("/:id", editor, supplierHandler.Update)
+	suppliersGroup.Delete("/:id", admin, supplierHandler.Delete)
+
+	purchasesGroup := protected.Group("/purchase-orders", viewer)
+	purchasesGroup.Post("/", editor, purchaseHandler.Create)
+	purchasesGroup.Get("/", purchaseHandler.List)
+	purchasesGroup.Get("/:id", purchaseHandler.GetDetails)
+	purchasesGroup.Put("/:id", editor, purchaseHandler.Update)
+	purchasesGroup.Post("/:id/send", editor, purchaseHandler.Send)
+	purchasesGroup.Post("/:id/cancel", editor, purchaseHandler.Cancel)
+	purchasesGroup.Post("/:id/receipts", editor, purchaseHandler.Receive)
+
+	// Relatórios de margem expõem custos; ficam restritos a admin
+	reportsGroup := protected.Group("/reports", admin)
+	reportsGroup.Get("/margin/products", reportHandler.ProductMargins)
+	reportsGroup.Get("/margin/orders", reportHandler.OrderMargins)
+	reportsGroup.Get("/margin/periods", reportHandler.PeriodMargins)
+	reportsGroup.Get("/sales/pdf", reportHandler.SalesPDF)
+
+	dashboardGroup := protected.Group("/dashboard", viewer)
 	dashboardGroup.Get("/metrics", dashboardHandler.GetMetrics)
 
 	go func() {
@@ -123,4 +244,4 @@ func main() {
 	log.Info().Msg("Shutting down server...")
 	_ = app.Shutdown()
 	log.Info().Msg("Server shutdown complete")
-}
\ No newline at end of file
+}
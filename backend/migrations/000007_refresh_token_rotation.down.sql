@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user;
+DROP INDEX IF EXISTS idx_refresh_tokens_family;
+DROP INDEX IF EXISTS idx_refresh_tokens_hash;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN revoked_at,
+    DROP COLUMN replaced_by,
+    DROP COLUMN family_id,
+    DROP COLUMN organization_id;
@@ -0,0 +1,9 @@
+ALTER TABLE refresh_tokens
+    ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    ADD COLUMN family_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    ADD COLUMN replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    ADD COLUMN revoked_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_refresh_tokens_hash ON refresh_tokens(token_hash);
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
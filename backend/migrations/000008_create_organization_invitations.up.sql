@@ -0,0 +1,17 @@
+CREATE TABLE organization_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email CITEXT NOT NULL,
+    role user_role NOT NULL DEFAULT 'viewer',
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted, declined, revoked
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Só pode haver um convite pendente por e-mail em cada organização
+CREATE UNIQUE INDEX idx_org_invitations_pending
+    ON organization_invitations(organization_id, email)
+    WHERE status = 'pending';
@@ -0,0 +1,11 @@
+CREATE TABLE organization_settings (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL DEFAULT 'BRL',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'America/Sao_Paulo',
+    low_stock_threshold INTEGER NOT NULL DEFAULT 5 CHECK (low_stock_threshold >= 0),
+    default_payment_method VARCHAR(50) NOT NULL DEFAULT 'dinheiro',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO organization_settings (organization_id)
+SELECT id FROM organizations;
@@ -0,0 +1,4 @@
+ALTER TABLE orders
+    DROP COLUMN cancel_reason,
+    DROP COLUMN canceled_by,
+    DROP COLUMN canceled_at;
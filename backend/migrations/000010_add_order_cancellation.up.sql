@@ -0,0 +1,4 @@
+ALTER TABLE orders
+    ADD COLUMN canceled_at TIMESTAMPTZ,
+    ADD COLUMN canceled_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN cancel_reason TEXT;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_orders_org_created;
+DROP INDEX IF EXISTS idx_customers_org_name;
+DROP INDEX IF EXISTS idx_customers_org_created;
+DROP INDEX IF EXISTS idx_products_org_name;
+DROP INDEX IF EXISTS idx_products_org_created;
+
+DROP INDEX IF EXISTS idx_customers_document_trgm;
+DROP INDEX IF EXISTS idx_customers_email_trgm;
+DROP INDEX IF EXISTS idx_customers_name_trgm;
+DROP INDEX IF EXISTS idx_products_sku_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Busca textual (ILIKE '%termo%') nas listagens
+CREATE INDEX idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_products_sku_trgm ON products USING GIN (sku gin_trgm_ops);
+CREATE INDEX idx_customers_name_trgm ON customers USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_customers_email_trgm ON customers USING GIN (email gin_trgm_ops);
+CREATE INDEX idx_customers_document_trgm ON customers USING GIN (document gin_trgm_ops);
+
+-- Paginação por keyset nas ordenações mais usadas
+CREATE INDEX idx_products_org_created ON products(organization_id, created_at, id);
+CREATE INDEX idx_products_org_name ON products(organization_id, name, id);
+CREATE INDEX idx_customers_org_created ON customers(organization_id, created_at, id);
+CREATE INDEX idx_customers_org_name ON customers(organization_id, name, id);
+CREATE INDEX idx_orders_org_created ON orders(organization_id, created_at, id);
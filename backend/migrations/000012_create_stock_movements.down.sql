@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_movements;
+DROP TYPE IF EXISTS stock_movement_type;
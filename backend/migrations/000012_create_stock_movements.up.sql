@@ -0,0 +1,27 @@
+CREATE TYPE stock_movement_type AS ENUM ('sale', 'return', 'purchase', 'adjustment', 'transfer', 'count');
+
+-- Razão de estoque: só recebe INSERTs. products.stock_quantity é a projeção
+-- do saldo e é atualizada na mesma instrução que grava cada movimento.
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    type stock_movement_type NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity <> 0), -- positivo entra, negativo sai
+    balance_after INTEGER NOT NULL,
+    reference_type VARCHAR(30), -- order, order_cancellation, product...
+    reference_id UUID,
+    note TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- clock_timestamp() para manter a ordem entre movimentos da mesma transação
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_stock_movements_product ON stock_movements(product_id, created_at, id);
+CREATE INDEX idx_stock_movements_reference ON stock_movements(reference_type, reference_id);
+
+-- Saldo de abertura para o estoque que já existia antes do razão
+INSERT INTO stock_movements (organization_id, product_id, type, quantity, balance_after, reference_type, note)
+SELECT organization_id, id, 'adjustment', stock_quantity, stock_quantity, 'opening_balance', 'Saldo inicial'
+FROM products
+WHERE stock_quantity <> 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_count_items;
+DROP TABLE IF EXISTS stock_counts;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS reason_code;
@@ -0,0 +1,27 @@
+-- Motivo dos ajustes manuais (damage, loss, theft, expired, internal_use, found, correction, count)
+ALTER TABLE stock_movements ADD COLUMN reason_code VARCHAR(30);
+
+CREATE TABLE stock_counts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, posted, canceled
+    note TEXT,
+    opened_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    posted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    posted_at TIMESTAMPTZ,
+    canceled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE stock_count_items (
+    count_id UUID NOT NULL REFERENCES stock_counts(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    counted_quantity INTEGER NOT NULL CHECK (counted_quantity >= 0),
+    system_quantity INTEGER, -- saldo no momento em que a contagem foi lançada
+    counted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    counted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (count_id, product_id)
+);
+
+CREATE INDEX idx_stock_counts_org ON stock_counts(organization_id, created_at, id);
@@ -0,0 +1,5 @@
+-- min_stock é o ponto de reposição: o produto entra em estoque baixo quando o
+-- saldo fica abaixo dele. Nulo usa organization_settings.low_stock_threshold.
+ALTER TABLE products
+    ADD COLUMN min_stock INTEGER CHECK (min_stock >= 0),
+    ADD COLUMN reorder_quantity INTEGER CHECK (reorder_quantity > 0);
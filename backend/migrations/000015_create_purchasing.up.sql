@@ -0,0 +1,65 @@
+CREATE TABLE suppliers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    email VARCHAR(200),
+    phone VARCHAR(50),
+    document VARCHAR(50), -- CPF ou CNPJ
+    type VARCHAR(20) DEFAULT 'company', -- individual ou company
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_suppliers_org ON suppliers(organization_id, created_at, id);
+CREATE INDEX idx_suppliers_name_trgm ON suppliers USING GIN (name gin_trgm_ops);
+
+CREATE TABLE purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    supplier_id UUID NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(30) NOT NULL DEFAULT 'draft', -- draft, sent, partially_received, received, canceled
+    total_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    note TEXT,
+    expected_at DATE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    sent_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    canceled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE purchase_order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    quantity_ordered INTEGER NOT NULL CHECK (quantity_ordered > 0),
+    quantity_received INTEGER NOT NULL DEFAULT 0 CHECK (quantity_received >= 0),
+    unit_cost DECIMAL(10, 2) NOT NULL CHECK (unit_cost >= 0),
+    CHECK (quantity_received <= quantity_ordered)
+);
+
+-- Cada recebimento (total ou parcial) guarda o custo efetivo de cada item
+CREATE TABLE purchase_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    purchase_order_id UUID NOT NULL REFERENCES purchase_orders(id),
+    note TEXT,
+    received_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE purchase_receipt_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    receipt_id UUID NOT NULL REFERENCES purchase_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id UUID NOT NULL REFERENCES purchase_order_items(id),
+    product_id UUID NOT NULL REFERENCES products(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_cost DECIMAL(10, 2) NOT NULL CHECK (unit_cost >= 0)
+);
+
+CREATE INDEX idx_purchase_orders_org ON purchase_orders(organization_id, created_at, id);
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_order_items_order ON purchase_order_items(purchase_order_id);
+CREATE INDEX idx_purchase_receipts_order ON purchase_receipts(purchase_order_id);
+CREATE INDEX idx_purchase_receipt_items_receipt ON purchase_receipt_items(receipt_id);
@@ -0,0 +1,5 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_cost;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS last_cost,
+    DROP COLUMN IF EXISTS average_cost;
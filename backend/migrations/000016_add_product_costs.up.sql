@@ -0,0 +1,10 @@
+-- average_cost é o custo médio ponderado, recalculado a cada recebimento de
+-- compra; last_cost é o custo do último recebimento.
+ALTER TABLE products
+    ADD COLUMN average_cost DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (average_cost >= 0),
+    ADD COLUMN last_cost DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (last_cost >= 0);
+
+-- Custo unitário no momento da venda. Vendas anteriores a esta migração ficam
+-- com custo zero.
+ALTER TABLE order_items
+    ADD COLUMN unit_cost DECIMAL(10, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS product_options;
+
+-- As variações continuam como produtos avulsos.
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_variant_has_no_variants,
+    DROP COLUMN IF EXISTS price_overridden,
+    DROP COLUMN IF EXISTS option_values,
+    DROP COLUMN IF EXISTS has_variants,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,23 @@
+-- Um produto com variações passa a ser o "pai": guarda os eixos de opção
+-- (tamanho, cor...) e não tem estoque próprio. Cada variação é uma linha de
+-- products com parent_id e SKU, preço e estoque próprios, de modo que razão
+-- de estoque, compras, contagens e itens de pedido continuam apontando para
+-- products (order_items.product_id é a variação vendida).
+ALTER TABLE products
+    ADD COLUMN parent_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    ADD COLUMN has_variants BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN option_values TEXT[],
+    ADD COLUMN price_overridden BOOLEAN NOT NULL DEFAULT false,
+    ADD CONSTRAINT products_variant_has_no_variants CHECK (parent_id IS NULL OR NOT has_variants);
+
+CREATE INDEX idx_products_parent ON products(parent_id) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_products_variant_options ON products(parent_id, option_values) WHERE parent_id IS NOT NULL;
+
+-- Eixos de opção do produto pai, na ordem em que aparecem no nome da variação.
+CREATE TABLE product_options (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    choices TEXT[] NOT NULL,
+    PRIMARY KEY (product_id, position)
+);
@@ -0,0 +1,26 @@
+-- Categorias aninháveis por organização. Uma categoria com subcategorias não
+-- pode ser apagada; ao apagar uma categoria folha, seus produtos ficam sem
+-- categoria.
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_categories_org ON categories(organization_id);
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+-- Nome único entre irmãs, sem diferenciar maiúsculas
+CREATE UNIQUE INDEX idx_categories_sibling_name
+    ON categories(organization_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), LOWER(name));
+
+-- Tags livres, gravadas em minúsculas.
+ALTER TABLE products
+    ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_products_category ON products(category_id);
+CREATE INDEX idx_products_tags ON products USING GIN (tags);
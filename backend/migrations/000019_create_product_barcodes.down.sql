@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_org_sku_lower;
+
+DROP TABLE IF EXISTS product_barcodes;
@@ -0,0 +1,14 @@
+-- Um produto (ou variação) pode ter vários códigos de barras; cada código é
+-- único na organização. Os códigos são validados (GTIN) na aplicação.
+CREATE TABLE product_barcodes (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    code VARCHAR(14) NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, code)
+);
+
+CREATE INDEX idx_product_barcodes_product ON product_barcodes(product_id);
+
+-- Busca de SKU do leitor, sem diferenciar maiúsculas
+CREATE INDEX idx_products_org_sku_lower ON products(organization_id, LOWER(sku));
@@ -0,0 +1,7 @@
+ALTER TABLE organization_settings
+    DROP COLUMN IF EXISTS sku_next_seq,
+    DROP COLUMN IF EXISTS sku_pattern;
+
+DROP INDEX IF EXISTS idx_products_org_sku;
+CREATE INDEX idx_products_sku ON products(sku);
+CREATE INDEX idx_products_org_sku_lower ON products(organization_id, LOWER(sku));
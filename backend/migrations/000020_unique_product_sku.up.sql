@@ -0,0 +1,26 @@
+-- SKU vazio passa a ser NULL, como a aplicação já grava.
+UPDATE products SET sku = NULL WHERE TRIM(sku) = '';
+
+-- Duplicatas existentes (sem diferenciar maiúsculas) ganham sufixo; o mais
+-- antigo mantém o SKU original.
+WITH dups AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY organization_id, LOWER(sku) ORDER BY created_at, id
+    ) AS n
+    FROM products
+    WHERE sku IS NOT NULL
+)
+UPDATE products p
+SET sku = LEFT(p.sku, 40) || '-DUP' || d.n, updated_at = NOW()
+FROM dups d
+WHERE d.id = p.id AND d.n > 1;
+
+DROP INDEX IF EXISTS idx_products_sku;
+DROP INDEX IF EXISTS idx_products_org_sku_lower;
+CREATE UNIQUE INDEX idx_products_org_sku ON products(organization_id, LOWER(sku)) WHERE sku IS NOT NULL;
+
+-- Gerador opcional: sem padrão, nada é gerado. sku_next_seq é o próximo número
+-- a usar em {SEQ}.
+ALTER TABLE organization_settings
+    ADD COLUMN sku_pattern VARCHAR(50),
+    ADD COLUMN sku_next_seq BIGINT NOT NULL DEFAULT 1 CHECK (sku_next_seq >= 1);
@@ -0,0 +1,21 @@
+-- Documento passa a ser gravado só com letras e dígitos, em maiúsculas; a
+-- máscara é aplicada na saída. Vazio vira NULL.
+UPDATE customers
+SET document = NULLIF(UPPER(regexp_replace(document, '[^0-9A-Za-z]', '', 'g')), '')
+WHERE document IS NOT NULL;
+
+-- Duplicatas existentes: o cliente mais antigo mantém o documento e os demais
+-- ficam sem, até serem unidos a ele por POST /customers/:id/merge.
+WITH dups AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY organization_id, document ORDER BY created_at, id
+    ) AS n
+    FROM customers
+    WHERE document IS NOT NULL
+)
+UPDATE customers c
+SET document = NULL, updated_at = NOW()
+FROM dups d
+WHERE d.id = c.id AND d.n > 1;
+
+CREATE UNIQUE INDEX idx_customers_org_document ON customers(organization_id, document) WHERE document IS NOT NULL;
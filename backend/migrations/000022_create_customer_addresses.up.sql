@@ -0,0 +1,23 @@
+-- Endereços de cobrança e de entrega do cliente. Cada tipo tem no máximo um
+-- endereço padrão, usado na nota fiscal e na entrega quando o pedido não
+-- indica outro. O CEP é gravado só com os dígitos.
+CREATE TABLE customer_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('billing', 'shipping')),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    zip_code CHAR(8) NOT NULL,
+    street VARCHAR(200) NOT NULL,
+    number VARCHAR(20) NOT NULL,
+    complement VARCHAR(100),
+    district VARCHAR(100) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    state CHAR(2) NOT NULL,
+    ibge_code CHAR(7),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_customer_addresses_customer ON customer_addresses(customer_id);
+CREATE UNIQUE INDEX idx_customer_addresses_default ON customer_addresses(customer_id, type) WHERE is_default;
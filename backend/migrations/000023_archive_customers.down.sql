@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_customers_org_active;
+
+ALTER TABLE customers
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS is_active;
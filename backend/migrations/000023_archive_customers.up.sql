@@ -0,0 +1,10 @@
+-- Cliente com pedidos não pode ser apagado (orders.customer_id não tem
+-- cascata): ele é arquivado, saindo das listagens e de novos pedidos, ou
+-- anonimizado, quando os dados pessoais precisam sumir (LGPD) mas o
+-- histórico de vendas fica.
+ALTER TABLE customers
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN archived_at TIMESTAMPTZ,
+    ADD COLUMN anonymized_at TIMESTAMPTZ;
+
+CREATE INDEX idx_customers_org_active ON customers(organization_id, is_active);
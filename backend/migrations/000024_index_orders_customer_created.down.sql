@@ -0,0 +1,2 @@
+CREATE INDEX idx_orders_customer ON orders(customer_id);
+DROP INDEX IF EXISTS idx_orders_customer_created;
@@ -0,0 +1,4 @@
+-- Resumo e histórico de compras do cliente percorrem os pedidos dele em
+-- ordem de data; o índice antigo só por customer_id fica redundante.
+CREATE INDEX idx_orders_customer_created ON orders(customer_id, created_at, id);
+DROP INDEX IF EXISTS idx_orders_customer;
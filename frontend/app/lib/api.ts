@@ -19,15 +19,47 @@ export interface Product {
   stock_quantity: number;
   sku: string;
   is_active: boolean;
+  min_stock: number | null;
+  reorder_quantity: number | null;
+  // Produto com variações não tem estoque próprio; vende-se uma variação
+  has_variants: boolean;
+  parent_id: string | null;
+  option_values: string[] | null;
+  category_id: string | null;
+  tags: string[];
   created_at: string;
 }
 
+export interface Category {
+  id: string;
+  parent_id: string | null;
+  name: string;
+  product_count: number;
+  children: Category[];
+}
+
+export interface ProductOption {
+  name: string;
+  values: string[];
+}
+
+export interface ProductVariants {
+  options: ProductOption[];
+  variants: Product[];
+}
+
+// Valores em dinheiro trafegam como texto decimal ("12.50"), com no máximo
+// duas casas, tanto nas respostas quanto nos envios.
 export interface CreateProductDTO {
   name: string;
   description?: string;
-  price: number;
+  price: string;
   stock_quantity: number;
   sku?: string;
+  min_stock?: number;
+  reorder_quantity?: number;
+  category_id?: string;
+  tags?: string[];
 }
 
 export interface Customer {
@@ -52,7 +84,8 @@ export interface CreateCustomerDTO {
 export interface OrderItemDTO {
   product_id: string;
   quantity: number;
-  unit_price: number;
+  // O preço vem do cadastro; override_price exige papel admin
+  override_price?: string;
 }
 
 export interface CreateOrderDTO {
@@ -61,6 +94,13 @@ export interface CreateOrderDTO {
   items: OrderItemDTO[];
 }
 
+export interface Page<T> {
+  data: T[];
+  next_cursor?: string;
+  total: number;
+  limit: number;
+}
+
 export interface Order {
   id: string;
   customer_name: string;
@@ -73,8 +113,8 @@ export interface Order {
 export interface OrderItem {
   product_name: string;
   quantity: number;
-  unit_price: number;
-  total_price: number;
+  unit_price: string;
+  total_price: string;
   payment_method: string;
 }
 
@@ -82,11 +122,20 @@ export interface OrderDetails {
   items: OrderItem[];
 }
 
+export interface CategorySales {
+  category_id: string | null;
+  category_name: string;
+  orders_count: number;
+  quantity_sold: number;
+  total: string;
+}
+
 export interface DashboardMetrics {
-  total_revenue: number;
+  total_revenue: string;
   sales_count: number;
   customers_count: number;
   low_stock_count: number;
+  sales_by_category: CategorySales[];
 }
 
 export const api = {
@@ -17,7 +17,12 @@ import {
   Download,
 } from "lucide-react";
 import { DashboardLayout } from "../components/DashboardLayout";
-import { api, type Customer, type CreateCustomerDTO } from "../lib/api";
+import {
+  api,
+  type Customer,
+  type CreateCustomerDTO,
+  type Page,
+} from "../lib/api";
 import { exportToCSV } from "../lib/export";
 
 const customerSchema = z.object({
@@ -41,7 +46,10 @@ export default function CustomersPage() {
     isError,
   } = useQuery({
     queryKey: ["customers"],
-    queryFn: () => api.get<Customer[]>("/protected/customers"),
+    queryFn: () =>
+      api
+        .get<Page<Customer>>("/protected/customers?limit=200")
+        .then((page) => page.data),
   });
 
   const createMutation = useMutation({
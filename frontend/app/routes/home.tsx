@@ -58,7 +58,7 @@ export default function HomePage() {
       value: new Intl.NumberFormat("pt-BR", {
         style: "currency",
         currency: "BRL",
-      }).format(stats?.total_revenue || 0),
+      }).format(Number(stats?.total_revenue ?? 0)),
       icon: TrendingUp,
       color: "blue",
       trend: "+12.5%",
@@ -150,7 +150,12 @@ export default function HomePage() {
             <div className="h-72 w-full">
               {stats?.sales_over_time && stats.sales_over_time.length > 0 ? (
                 <ResponsiveContainer width="100%" height="100%">
-                  <LineChart data={stats.sales_over_time}>
+                  <LineChart
+                    data={stats.sales_over_time.map((d: any) => ({
+                      ...d,
+                      total: Number(d.total),
+                    }))}
+                  >
                     <CartesianGrid
                       strokeDasharray="3 3"
                       vertical={false}
@@ -245,6 +250,44 @@ export default function HomePage() {
             </div>
           </div>
         </div>
+
+        <div className="bg-white rounded-3xl p-8 border border-slate-100 shadow-sm">
+          <h2 className="text-xl font-bold text-slate-900 mb-6">
+            Vendas por Categoria (30 dias)
+          </h2>
+          {stats?.sales_by_category && stats.sales_by_category.length > 0 ? (
+            <div className="space-y-4">
+              {stats.sales_by_category.map((c: any) => {
+                const max = Number(stats.sales_by_category[0].total) || 1;
+                return (
+                  <div key={c.category_id ?? "none"}>
+                    <div className="flex justify-between text-sm mb-1">
+                      <span className="font-medium text-slate-700">
+                        {c.category_name}
+                      </span>
+                      <span className="font-bold text-slate-900">
+                        {new Intl.NumberFormat("pt-BR", {
+                          style: "currency",
+                          currency: "BRL",
+                        }).format(Number(c.total))}
+                      </span>
+                    </div>
+                    <div className="h-2 rounded-full bg-slate-100">
+                      <div
+                        className="h-2 rounded-full bg-blue-600"
+                        style={{ width: `${(Number(c.total) / max) * 100}%` }}
+                      />
+                    </div>
+                  </div>
+                );
+              })}
+            </div>
+          ) : (
+            <p className="text-slate-400 font-medium">
+              Sem vendas nos últimos 30 dias.
+            </p>
+          )}
+        </div>
       </div>
     </DashboardLayout>
   );
@@ -18,7 +18,7 @@ import {
   Wallet,
 } from "lucide-react";
 import { DashboardLayout } from "../components/DashboardLayout";
-import { api, type Order, type OrderDetails } from "../lib/api";
+import { api, type Order, type OrderDetails, type Page } from "../lib/api";
 import { exportToCSV } from "../lib/export";
 
 export default function OrdersPage() {
@@ -27,7 +27,10 @@ export default function OrdersPage() {
 
   const { data: orders, isLoading } = useQuery({
     queryKey: ["orders"],
-    queryFn: () => api.get<Order[]>("/protected/orders"),
+    queryFn: () =>
+      api
+        .get<Page<Order>>("/protected/orders?limit=200")
+        .then((page) => page.data),
   });
 
   const { data: details, isLoading: loadingDetails } = useQuery({
@@ -147,7 +150,7 @@ export default function OrdersPage() {
                 <tr>
                   <td>${item.quantity}x</td>
                   <td>${item.product_name}</td>
-                  <td class="right">${new Intl.NumberFormat("pt-BR", { style: "currency", currency: "BRL" }).format(item.total_price)}</td>
+                  <td class="right">${new Intl.NumberFormat("pt-BR", { style: "currency", currency: "BRL" }).format(Number(item.total_price))}</td>
                 </tr>
               `,
                 )
@@ -481,14 +484,14 @@ export default function OrdersPage() {
                             {new Intl.NumberFormat("pt-BR", {
                               style: "currency",
                               currency: "BRL",
-                            }).format(item.unit_price)}
+                            }).format(Number(item.unit_price))}
                           </p>
                         </div>
                         <p className="font-bold text-slate-900 text-sm">
                           {new Intl.NumberFormat("pt-BR", {
                             style: "currency",
                             currency: "BRL",
-                          }).format(item.total_price)}
+                          }).format(Number(item.total_price))}
                         </p>
                       </div>
                     ))}
@@ -18,7 +18,12 @@ import {
   Power,
 } from "lucide-react";
 import { DashboardLayout } from "../components/DashboardLayout";
-import { api, type Product, type CreateProductDTO } from "../lib/api";
+import {
+  api,
+  type Product,
+  type CreateProductDTO,
+  type Page,
+} from "../lib/api";
 import { exportToCSV } from "../lib/export";
 
 const productSchema = z.object({
@@ -32,6 +37,14 @@ const productSchema = z.object({
     z.number().int().min(0, "Estoque não pode ser negativo"),
   ),
   sku: z.string().optional(),
+  min_stock: z.preprocess(
+    (val) => (val === "" || val == null ? undefined : Number(val)),
+    z.number().int().min(0, "Mínimo não pode ser negativo").optional(),
+  ),
+  reorder_quantity: z.preprocess(
+    (val) => (val === "" || val == null ? undefined : Number(val)),
+    z.number().int().min(1, "Lote deve ser maior que zero").optional(),
+  ),
   description: z.string().optional(),
   is_active: z.boolean().default(true),
 });
@@ -50,9 +63,22 @@ export default function ProductsPage() {
     isError,
   } = useQuery({
     queryKey: ["products"],
-    queryFn: () => api.get<Product[]>("/protected/products"),
+    queryFn: () =>
+      api
+        .get<Page<Product>>("/protected/products?limit=200")
+        .then((page) => page.data),
+  });
+
+  const { data: organization } = useQuery({
+    queryKey: ["organization"],
+    queryFn: () => api.get<any>("/protected/organization"),
+    staleTime: 1000 * 60 * 5,
   });
 
+  const lowStockThreshold = organization?.settings?.low_stock_threshold ?? 5;
+  const isLowStock = (p: Product) =>
+    p.is_active && p.stock_quantity < (p.min_stock ?? lowStockThreshold);
+
   const createMutation = useMutation({
     mutationFn: (data: CreateProductDTO) =>
       api.post("/protected/products", data),
@@ -63,7 +89,13 @@ export default function ProductsPage() {
   });
 
   const updateMutation = useMutation({
-    mutationFn: ({ id, data }: { id: string; data: ProductForm }) =>
+    mutationFn: ({
+      id,
+      data,
+    }: {
+      id: string;
+      data: CreateProductDTO & { is_active: boolean };
+    }) =>
       api.put(`/protected/products/${id}`, data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["products"] });
@@ -93,10 +125,11 @@ export default function ProductsPage() {
   const isActiveWatch = watch("is_active");
 
   const onSubmit = (data: ProductForm) => {
+    const payload = { ...data, price: data.price.toFixed(2) };
     if (editingId) {
-      updateMutation.mutate({ id: editingId, data });
+      updateMutation.mutate({ id: editingId, data: payload });
     } else {
-      createMutation.mutate(data);
+      createMutation.mutate(payload);
     }
   };
 
@@ -106,6 +139,8 @@ export default function ProductsPage() {
     setValue("price", Number(product.price));
     setValue("stock_quantity", product.stock_quantity);
     setValue("sku", product.sku || "");
+    setValue("min_stock", product.min_stock ?? undefined);
+    setValue("reorder_quantity", product.reorder_quantity ?? undefined);
     setValue("description", product.description || "");
     setValue("is_active", product.is_active);
     setIsModalOpen(true);
@@ -143,9 +178,9 @@ export default function ProductsPage() {
         (acc, p) => acc + Number(p.price) * p.stock_quantity,
         0,
       ),
-      lowStock: products.filter((p) => p.stock_quantity < 5).length,
+      lowStock: products.filter(isLowStock).length,
     };
-  }, [products]);
+  }, [products, lowStockThreshold]);
 
   const filteredProducts = products?.filter(
     (p) =>
@@ -345,14 +380,14 @@ export default function ProductsPage() {
                         <div className="flex items-center gap-2">
                           <div
                             className={`h-2 w-2 rounded-full ${
-                              product.stock_quantity < 5
+                              isLowStock(product)
                                 ? "bg-red-500 shadow-[0_0_8px_rgba(239,68,68,0.5)]"
                                 : "bg-emerald-500 shadow-[0_0_8px_rgba(16,185,129,0.5)]"
                             }`}
                           />
                           <span
                             className={
-                              product.stock_quantity < 5
+                              isLowStock(product)
                                 ? "text-red-600 font-semibold"
                                 : "text-slate-600 font-medium"
                             }
@@ -467,7 +502,13 @@ export default function ProductsPage() {
                     <input
                       type="number"
                       {...register("stock_quantity")}
-                      className="block w-full rounded-lg border border-slate-200 bg-slate-50 px-4 py-2.5 text-sm text-slate-900 focus:border-blue-500 focus:bg-white focus:ring-4 focus:ring-blue-500/10 transition-all outline-none"
+                      readOnly={!!editingId}
+                      title={
+                        editingId
+                          ? "Use um ajuste de estoque para alterar o saldo"
+                          : undefined
+                      }
+                      className="block w-full rounded-lg border border-slate-200 bg-slate-50 px-4 py-2.5 text-sm text-slate-900 focus:border-blue-500 focus:bg-white focus:ring-4 focus:ring-blue-500/10 transition-all outline-none read-only:text-slate-400"
                       placeholder="0"
                     />
                   </div>
@@ -484,6 +525,41 @@ export default function ProductsPage() {
                   />
                 </div>
 
+                <div className="grid grid-cols-2 gap-5">
+                  <div>
+                    <label className="block text-sm font-medium text-slate-700 mb-1">
+                      Estoque Mínimo
+                    </label>
+                    <input
+                      type="number"
+                      {...register("min_stock")}
+                      className="block w-full rounded-lg border border-slate-200 bg-slate-50 px-4 py-2.5 text-sm text-slate-900 focus:border-blue-500 focus:bg-white focus:ring-4 focus:ring-blue-500/10 transition-all outline-none"
+                      placeholder={`Padrão: ${lowStockThreshold}`}
+                    />
+                    {errors.min_stock && (
+                      <p className="text-xs text-red-500 mt-1">
+                        {errors.min_stock.message}
+                      </p>
+                    )}
+                  </div>
+                  <div>
+                    <label className="block text-sm font-medium text-slate-700 mb-1">
+                      Lote de Reposição
+                    </label>
+                    <input
+                      type="number"
+                      {...register("reorder_quantity")}
+                      className="block w-full rounded-lg border border-slate-200 bg-slate-50 px-4 py-2.5 text-sm text-slate-900 focus:border-blue-500 focus:bg-white focus:ring-4 focus:ring-blue-500/10 transition-all outline-none"
+                      placeholder="Opcional"
+                    />
+                    {errors.reorder_quantity && (
+                      <p className="text-xs text-red-500 mt-1">
+                        {errors.reorder_quantity.message}
+                      </p>
+                    )}
+                  </div>
+                </div>
+
                 <div>
                   <label className="block text-sm font-medium text-slate-700 mb-1">
                     Descrição
@@ -16,6 +16,8 @@ import {
   Wallet,
   AlertCircle,
   CheckCircle2,
+  Layers,
+  X,
 } from "lucide-react";
 import { DashboardLayout } from "../components/DashboardLayout";
 import {
@@ -23,6 +25,9 @@ import {
   type Product,
   type Customer,
   type CreateOrderDTO,
+  type Page,
+  type ProductVariants,
+  type Category,
 } from "../lib/api";
 
 interface CartItem extends Product {
@@ -34,16 +39,43 @@ export default function SalesPage() {
   const [selectedCustomerId, setSelectedCustomerId] = useState<string>("");
   const [paymentMethod, setPaymentMethod] = useState<string>("dinheiro");
   const [cart, setCart] = useState<CartItem[]>([]);
+  const [variantParent, setVariantParent] = useState<Product | null>(null);
+  const [categoryId, setCategoryId] = useState<string>("");
   const queryClient = useQueryClient();
 
   const { data: products, isLoading: loadingProducts } = useQuery({
-    queryKey: ["products"],
-    queryFn: () => api.get<Product[]>("/protected/products"),
+    queryKey: ["products", { active: true, categoryId }],
+    queryFn: () =>
+      api
+        .get<Page<Product>>(
+          `/protected/products?active=true&limit=200${
+            categoryId ? `&category_id=${categoryId}` : ""
+          }`,
+        )
+        .then((page) => page.data),
+  });
+
+  // Só as categorias raiz; o filtro já inclui as subcategorias
+  const { data: categories } = useQuery({
+    queryKey: ["categories"],
+    queryFn: () => api.get<Category[]>("/protected/categories"),
+  });
+
+  const { data: variantData, isLoading: loadingVariants } = useQuery({
+    queryKey: ["product-variants", variantParent?.id],
+    queryFn: () =>
+      api.get<ProductVariants>(
+        `/protected/products/${variantParent?.id}/variants`,
+      ),
+    enabled: !!variantParent,
   });
 
   const { data: customers, isLoading: loadingCustomers } = useQuery({
     queryKey: ["customers"],
-    queryFn: () => api.get<Customer[]>("/protected/customers"),
+    queryFn: () =>
+      api
+        .get<Page<Customer>>("/protected/customers?limit=200")
+        .then((page) => page.data),
   });
 
   const createOrderMutation = useMutation({
@@ -53,6 +85,7 @@ export default function SalesPage() {
       setSelectedCustomerId("");
       setPaymentMethod("dinheiro");
       queryClient.invalidateQueries({ queryKey: ["products"] });
+      queryClient.invalidateQueries({ queryKey: ["product-variants"] });
       alert("Venda realizada com sucesso!");
     },
     onError: (err) => {
@@ -77,6 +110,28 @@ export default function SalesPage() {
     });
   };
 
+  // Leitores de código de barras digitam o código e mandam Enter
+  const handleScan = async (code: string) => {
+    if (!code.trim()) return;
+    try {
+      const product = await api.get<Product>(
+        `/protected/products/lookup?code=${encodeURIComponent(code.trim())}`,
+      );
+      if (!product.is_active) {
+        alert(`Produto inativo: ${product.name}`);
+      } else if (product.has_variants) {
+        setVariantParent(product);
+      } else if (product.stock_quantity > 0) {
+        addToCart(product);
+      } else {
+        alert(`Produto esgotado: ${product.name}`);
+      }
+      setSearchTerm("");
+    } catch {
+      alert(`Código não encontrado: ${code}`);
+    }
+  };
+
   const removeFromCart = (productId: string) => {
     setCart((prev) => prev.filter((item) => item.id !== productId));
   };
@@ -112,7 +167,6 @@ export default function SalesPage() {
       items: cart.map((item) => ({
         product_id: item.id,
         quantity: item.cartQuantity,
-        unit_price: Number(item.price),
       })),
     };
 
@@ -123,7 +177,8 @@ export default function SalesPage() {
     (p) =>
       p.is_active &&
       (p.name.toLowerCase().includes(searchTerm.toLowerCase()) ||
-        p.sku?.toLowerCase().includes(searchTerm.toLowerCase())),
+        p.sku?.toLowerCase().includes(searchTerm.toLowerCase()) ||
+        p.tags?.some((t) => t.includes(searchTerm.toLowerCase()))),
   );
 
   const paymentMethodsList = [
@@ -158,11 +213,31 @@ export default function SalesPage() {
               <input
                 type="text"
                 className="block w-full pl-11 pr-4 py-3.5 rounded-xl border border-slate-200 bg-slate-50 text-sm text-slate-900 focus:bg-white focus:border-blue-500 focus:ring-4 focus:ring-blue-500/10 transition-all outline-none placeholder:text-slate-400"
-                placeholder="Buscar por nome ou código SKU..."
+                placeholder="Buscar por nome, SKU ou ler código de barras..."
                 value={searchTerm}
                 onChange={(e) => setSearchTerm(e.target.value)}
+                onKeyDown={(e) => {
+                  if (e.key === "Enter") handleScan(searchTerm);
+                }}
               />
             </div>
+            {categories && categories.length > 0 && (
+              <div className="flex gap-2 overflow-x-auto pb-1">
+                {[{ id: "", name: "Todas" }, ...categories].map((c) => (
+                  <button
+                    key={c.id || "all"}
+                    onClick={() => setCategoryId(c.id)}
+                    className={`shrink-0 px-3 py-1.5 rounded-lg border text-xs font-semibold transition-all ${
+                      categoryId === c.id
+                        ? "border-blue-600 bg-blue-50 text-blue-700"
+                        : "border-slate-200 bg-white text-slate-600 hover:bg-slate-50"
+                    }`}
+                  >
+                    {c.name}
+                  </button>
+                ))}
+              </div>
+            )}
           </div>
 
           <div className="flex-1 overflow-y-auto pr-2 custom-scrollbar">
@@ -185,7 +260,8 @@ export default function SalesPage() {
             ) : (
               <div className="grid grid-cols-1 gap-4 sm:grid-cols-2 xl:grid-cols-3 pb-6">
                 {filteredProducts?.map((product) => {
-                  const inStock = product.stock_quantity > 0;
+                  const inStock =
+                    product.has_variants || product.stock_quantity > 0;
                   const cartItem = cart.find((item) => item.id === product.id);
                   const isMaxReached =
                     cartItem?.cartQuantity === product.stock_quantity;
@@ -193,9 +269,13 @@ export default function SalesPage() {
                   return (
                     <button
                       key={product.id}
-                      onClick={() =>
-                        inStock && !isMaxReached && addToCart(product)
-                      }
+                      onClick={() => {
+                        if (product.has_variants) {
+                          setVariantParent(product);
+                        } else if (inStock && !isMaxReached) {
+                          addToCart(product);
+                        }
+                      }}
                       disabled={!inStock || isMaxReached}
                       className={`group relative flex flex-col justify-between rounded-2xl border p-5 text-left transition-all duration-200 ${
                         inStock && !isMaxReached
@@ -231,7 +311,11 @@ export default function SalesPage() {
                               inStock ? "text-emerald-600" : "text-red-500"
                             }`}
                           >
-                            {inStock ? (
+                            {product.has_variants ? (
+                              <>
+                                <Layers size={12} /> Ver variações
+                              </>
+                            ) : inStock ? (
                               <>
                                 <CheckCircle2 size={12} />{" "}
                                 {product.stock_quantity} un
@@ -385,6 +469,85 @@ export default function SalesPage() {
           </div>
         </div>
       </div>
+
+      {variantParent && (
+        <div className="fixed inset-0 z-50 flex items-center justify-center bg-slate-900/50 p-4">
+          <div className="w-full max-w-lg rounded-2xl bg-white shadow-xl overflow-hidden">
+            <div className="flex items-center justify-between border-b border-slate-200 p-5">
+              <div>
+                <h2 className="text-lg font-bold text-slate-900">
+                  {variantParent.name}
+                </h2>
+                <p className="text-sm text-slate-500">
+                  Selecione a variação para adicionar ao pedido.
+                </p>
+              </div>
+              <button
+                onClick={() => setVariantParent(null)}
+                className="p-1 text-slate-400 hover:text-slate-900 transition-colors"
+              >
+                <X size={20} />
+              </button>
+            </div>
+            <div className="max-h-[60vh] overflow-y-auto p-4 space-y-2">
+              {loadingVariants ? (
+                <div className="flex justify-center py-8">
+                  <Loader2 className="h-6 w-6 animate-spin text-blue-600" />
+                </div>
+              ) : (
+                variantData?.variants
+                  .filter((v) => v.is_active)
+                  .map((variant) => {
+                    const cartItem = cart.find((item) => item.id === variant.id);
+                    const available =
+                      variant.stock_quantity > 0 &&
+                      (cartItem?.cartQuantity ?? 0) < variant.stock_quantity;
+
+                    return (
+                      <button
+                        key={variant.id}
+                        disabled={!available}
+                        onClick={() => {
+                          addToCart(variant);
+                          setVariantParent(null);
+                        }}
+                        className="flex w-full items-center justify-between rounded-xl border border-slate-200 p-4 text-left transition-all hover:border-blue-400 disabled:cursor-not-allowed disabled:opacity-60 disabled:hover:border-slate-200"
+                      >
+                        <div>
+                          <p className="font-semibold text-slate-900">
+                            {variant.option_values?.join(" / ")}
+                          </p>
+                          <span className="text-[10px] font-mono text-slate-500">
+                            {variant.sku || "SEM SKU"}
+                          </span>
+                        </div>
+                        <div className="text-right">
+                          <p className="font-bold text-slate-900">
+                            {new Intl.NumberFormat("pt-BR", {
+                              style: "currency",
+                              currency: "BRL",
+                            }).format(Number(variant.price))}
+                          </p>
+                          <p
+                            className={`text-xs font-semibold ${
+                              variant.stock_quantity > 0
+                                ? "text-emerald-600"
+                                : "text-red-500"
+                            }`}
+                          >
+                            {variant.stock_quantity > 0
+                              ? `${variant.stock_quantity} un`
+                              : "Esgotado"}
+                          </p>
+                        </div>
+                      </button>
+                    );
+                  })
+              )}
+            </div>
+          </div>
+        </div>
+      )}
     </DashboardLayout>
   );
 }